@@ -0,0 +1,171 @@
+package recurly
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// AccountSnapshot is the state of an account fetched at once: the account,
+// its balance and billing info, its live subscriptions and its open
+// invoices.
+type AccountSnapshot struct {
+	Account     *Account
+	Balance     *AccountBalance
+	BillingInfo *Billing
+
+	// Subscriptions are the live subscriptions: active, canceled but not
+	// yet expired, in trial and future subscriptions.
+	Subscriptions []Subscription
+
+	// Invoices are the pending, processing and past-due invoices, ordered
+	// by invoice number.
+	Invoices []Invoice
+}
+
+// snapshotInvoiceStates are the states of the invoices in an
+// AccountSnapshot.
+var snapshotInvoiceStates = []string{ChargeInvoiceStatePending, ChargeInvoiceStateProcessing, ChargeInvoiceStatePastDue}
+
+// ErrAccountSnapshot is returned by AccountSnapshot when a request returns
+// an error status. It implements the error interface.
+type ErrAccountSnapshot struct {
+	Response *Response
+}
+
+// Error implements the error interface.
+func (e ErrAccountSnapshot) Error() string {
+	return fmt.Sprintf("recurly: %s %s: %d", e.Response.Request.Method, e.Response.Request.URL.Path, e.Response.StatusCode)
+}
+
+// AccountSnapshot fetches an account with its balance, billing info, live
+// subscriptions and open invoices, making the requests concurrently. It
+// returns nil if the account is not found, and a nil BillingInfo if the
+// account has none. If any request fails, the first error is returned.
+func (c *Client) AccountSnapshot(code string) (*AccountSnapshot, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		snapshot AccountSnapshot
+		invoices = make([][]Invoice, len(snapshotInvoiceStates))
+	)
+
+	run := func(fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	run(func() error {
+		resp, a, err := c.Accounts.Get(code)
+		if err := snapshotError(resp, err); err != nil {
+			return err
+		}
+		snapshot.Account = a
+		return nil
+	})
+	run(func() error {
+		resp, b, err := c.Accounts.LookupAccountBalance(code)
+		if err := snapshotError(resp, err); err != nil {
+			return err
+		} else if resp.StatusCode != http.StatusNotFound {
+			snapshot.Balance = b
+		}
+		return nil
+	})
+	run(func() error {
+		resp, b, err := c.Billing.Get(code)
+		if err := snapshotError(resp, err); err != nil {
+			return err
+		}
+		snapshot.BillingInfo = b
+		return nil
+	})
+	run(func() error {
+		subs, err := c.snapshotSubscriptions(code)
+		snapshot.Subscriptions = subs
+		return err
+	})
+	for i, state := range snapshotInvoiceStates {
+		i, state := i, state
+		run(func() error {
+			list, err := c.snapshotInvoices(code, state)
+			invoices[i] = list
+			return err
+		})
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	} else if snapshot.Account == nil {
+		return nil, nil
+	}
+
+	for _, list := range invoices {
+		snapshot.Invoices = append(snapshot.Invoices, list...)
+	}
+	sort.Slice(snapshot.Invoices, func(i, j int) bool {
+		return snapshot.Invoices[i].InvoiceNumber < snapshot.Invoices[j].InvoiceNumber
+	})
+	return &snapshot, nil
+}
+
+// snapshotSubscriptions returns all live subscriptions of an account.
+func (c *Client) snapshotSubscriptions(code string) ([]Subscription, error) {
+	var subs []Subscription
+	params := Params{"state": SubscriptionStateLive, "per_page": 200}
+	for {
+		resp, page, err := c.Subscriptions.ListAccount(code, params)
+		if err := snapshotError(resp, err); err != nil {
+			return nil, err
+		}
+		subs = append(subs, page...)
+
+		next := resp.Next()
+		if next == "" || resp.StatusCode == http.StatusNotFound {
+			return subs, nil
+		}
+		params["cursor"] = next
+	}
+}
+
+// snapshotInvoices returns all invoices of an account in state.
+func (c *Client) snapshotInvoices(code string, state string) ([]Invoice, error) {
+	var invoices []Invoice
+	params := Params{"state": state, "per_page": 200}
+	for {
+		resp, page, err := c.Invoices.ListAccount(code, params)
+		if err := snapshotError(resp, err); err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, page...)
+
+		next := resp.Next()
+		if next == "" || resp.StatusCode == http.StatusNotFound {
+			return invoices, nil
+		}
+		params["cursor"] = next
+	}
+}
+
+// snapshotError returns err, or an ErrAccountSnapshot if resp has an error
+// status other than not found.
+func snapshotError(resp *Response, err error) error {
+	if err != nil {
+		return err
+	} else if resp.IsError() && resp.StatusCode != http.StatusNotFound {
+		return ErrAccountSnapshot{Response: resp}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package recurly
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_AccountSnapshot(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// Each handler waits until every request has arrived, so the snapshot
+	// only completes if its requests are made concurrently.
+	const requests = 7
+	var (
+		mu      sync.Mutex
+		arrived int
+		ready   = make(chan struct{})
+	)
+	wait := func() {
+		mu.Lock()
+		arrived++
+		if arrived == requests {
+			close(ready)
+		}
+		mu.Unlock()
+
+		select {
+		case <-ready:
+		case <-time.After(2 * time.Second):
+			t.Errorf("requests were not made concurrently")
+		}
+	}
+
+	mux.HandleFunc("/v2/accounts/1", func(w http.ResponseWriter, r *http.Request) {
+		wait()
+		fmt.Fprint(w, `<account><account_code>1</account_code><state>active</state></account>`)
+	})
+	mux.HandleFunc("/v2/accounts/1/balance", func(w http.ResponseWriter, r *http.Request) {
+		wait()
+		fmt.Fprint(w, `<account_balance>
+			<past_due type="boolean">true</past_due>
+			<balance_in_cents><USD type="integer">2000</USD></balance_in_cents>
+			<available_credit_in_cents><USD type="integer">500</USD></available_credit_in_cents>
+		</account_balance>`)
+	})
+	mux.HandleFunc("/v2/accounts/1/billing_info", func(w http.ResponseWriter, r *http.Request) {
+		wait()
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `<error><symbol>not_found</symbol><description>Couldn't find BillingInfo</description></error>`)
+	})
+	mux.HandleFunc("/v2/accounts/1/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		wait()
+		if state := r.URL.Query().Get("state"); state != "live" {
+			t.Errorf("unexpected state: %s", state)
+		}
+		fmt.Fprint(w, `<subscriptions><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid><state>active</state></subscription></subscriptions>`)
+	})
+	mux.HandleFunc("/v2/accounts/1/invoices", func(w http.ResponseWriter, r *http.Request) {
+		wait()
+		switch state := r.URL.Query().Get("state"); state {
+		case "pending":
+			fmt.Fprint(w, `<invoices><invoice><invoice_number type="integer">1005</invoice_number><state>pending</state></invoice></invoices>`)
+		case "processing":
+			fmt.Fprint(w, `<invoices></invoices>`)
+		case "past_due":
+			fmt.Fprint(w, `<invoices><invoice><invoice_number type="integer">1001</invoice_number><state>past_due</state></invoice></invoices>`)
+		default:
+			t.Errorf("unexpected state: %s", state)
+		}
+	})
+
+	s, err := client.AccountSnapshot("1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if s.Account == nil || s.Account.Code != "1" {
+		t.Fatalf("unexpected account: %#v", s.Account)
+	} else if s.Balance == nil || !s.Balance.PastDue || s.Balance.Balance("USD").Amount != 2000 || s.Balance.AvailableCredit("USD").Amount != 500 {
+		t.Fatalf("unexpected balance: %#v", s.Balance)
+	} else if s.BillingInfo != nil {
+		t.Fatalf("unexpected billing info: %#v", s.BillingInfo)
+	} else if len(s.Subscriptions) != 1 || s.Subscriptions[0].UUID != "44f83d7cba354d5b84812419f923ea96" {
+		t.Fatalf("unexpected subscriptions: %#v", s.Subscriptions)
+	} else if len(s.Invoices) != 2 || s.Invoices[0].InvoiceNumber != 1001 || s.Invoices[1].InvoiceNumber != 1005 {
+		t.Fatalf("unexpected invoices: %#v", s.Invoices)
+	}
+}
+
+func TestClient_AccountSnapshot_NotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `<error><symbol>not_found</symbol><description>Couldn't find Account</description></error>`)
+	})
+
+	if s, err := client.AccountSnapshot("1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if s != nil {
+		t.Fatalf("expected snapshot to be nil: %#v", s)
+	}
+}
+
+func TestClient_AccountSnapshot_Error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/accounts/1" {
+			fmt.Fprint(w, `<account><account_code>1</account_code></account>`)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	s, err := client.AccountSnapshot("1")
+	if s != nil {
+		t.Fatalf("expected snapshot to be nil: %#v", s)
+	} else if e, ok := err.(ErrAccountSnapshot); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	} else if e.Response.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("unexpected status: %d", e.Response.StatusCode)
+	}
+}
@@ -7,54 +7,74 @@ import (
 
 // Account represents an individual account on your site
 type Account struct {
-	XMLName                 xml.Name           `xml:"account"`
-	Code                    string             `xml:"account_code,omitempty"`
-	State                   string             `xml:"state,omitempty"`
-	Username                string             `xml:"username,omitempty"`
-	Email                   string             `xml:"email,omitempty"`
-	FirstName               string             `xml:"first_name,omitempty"`
-	LastName                string             `xml:"last_name,omitempty"`
-	CompanyName             string             `xml:"company_name,omitempty"`
-	VATNumber               string             `xml:"vat_number,omitempty"`
-	TaxExempt               NullBool           `xml:"tax_exempt,omitempty"`
-	BillingInfo             *Billing           `xml:"billing_info,omitempty"`
-	Address                 Address            `xml:"address,omitempty"`
-	AcceptLanguage          string             `xml:"accept_language,omitempty"`
-	HostedLoginToken        string             `xml:"hosted_login_token,omitempty"`
-	CreatedAt               NullTime           `xml:"created_at,omitempty"`
-	UpdatedAt               NullTime           `xml:"updated_at,omitempty"`
-	ClosedAt                NullTime           `xml:"closed_at,omitempty"`
-	HasLiveSubscription     NullBool           `xml:"has_live_subscription,omitempty"`
-	HasActiveSubscription   NullBool           `xml:"has_active_subscription,omitempty"`
-	HasFutureSubscription   NullBool           `xml:"has_future_subscription,omitempty"`
-	HasCanceledSubscription NullBool           `xml:"has_canceled_subscription,omitempty"`
-	HasPastDueInvoice       NullBool           `xml:"has_past_due_invoice,omitempty"`
-	ShippingAddresses       *[]ShippingAddress `xml:"shipping_addresses>shipping_address,omitempty"`
+	XMLName                 xml.Name           `xml:"account" json:"-"`
+	Code                    string             `xml:"account_code,omitempty" json:"account_code,omitempty"`
+	State                   string             `xml:"state,omitempty" json:"state,omitempty"`
+	Username                string             `xml:"username,omitempty" json:"username,omitempty"`
+	Email                   string             `xml:"email,omitempty" json:"email,omitempty"`
+	FirstName               string             `xml:"first_name,omitempty" json:"first_name,omitempty"`
+	LastName                string             `xml:"last_name,omitempty" json:"last_name,omitempty"`
+	CompanyName             string             `xml:"company_name,omitempty" json:"company_name,omitempty"`
+	VATNumber               string             `xml:"vat_number,omitempty" json:"vat_number,omitempty"`
+	TaxExempt               NullBool           `xml:"tax_exempt,omitempty" json:"tax_exempt"`
+	BillingInfo             *Billing           `xml:"billing_info,omitempty" json:"billing_info,omitempty"`
+	Address                 Address            `xml:"address,omitempty" json:"address"`
+	AcceptLanguage          string             `xml:"accept_language,omitempty" json:"accept_language,omitempty"`
+	HostedLoginToken        string             `xml:"hosted_login_token,omitempty" json:"hosted_login_token,omitempty"`
+	CreatedAt               NullTime           `xml:"created_at,omitempty" json:"created_at"`
+	UpdatedAt               NullTime           `xml:"updated_at,omitempty" json:"updated_at"`
+	ClosedAt                NullTime           `xml:"closed_at,omitempty" json:"closed_at"`
+	HasLiveSubscription     NullBool           `xml:"has_live_subscription,omitempty" json:"has_live_subscription"`
+	HasActiveSubscription   NullBool           `xml:"has_active_subscription,omitempty" json:"has_active_subscription"`
+	HasFutureSubscription   NullBool           `xml:"has_future_subscription,omitempty" json:"has_future_subscription"`
+	HasCanceledSubscription NullBool           `xml:"has_canceled_subscription,omitempty" json:"has_canceled_subscription"`
+	HasPastDueInvoice       NullBool           `xml:"has_past_due_invoice,omitempty" json:"has_past_due_invoice"`
+	ShippingAddresses       *[]ShippingAddress `xml:"shipping_addresses>shipping_address,omitempty" json:"shipping_addresses,omitempty"`
 }
 
-// AccountBalance is used for getting the account balance.
+// AccountBalance is used for getting the account balance. BalanceInCents
+// holds the balance in each currency the account has been invoiced in, and
+// AvailableCreditInCents the open credit that can be applied to future
+// invoices.
 type AccountBalance struct {
-	XMLName        xml.Name       `xml:"account_balance"`
-	AccountCode    string         `xml:"-"`
-	PastDue        bool           `xml:"past_due"`
-	BalanceInCents BalanceInCents `xml:"balance_in_cents"`
+	XMLName                xml.Name        `xml:"account_balance" json:"-"`
+	AccountCode            string          `xml:"-" json:"account_code,omitempty"`
+	PastDue                bool            `xml:"past_due" json:"past_due,omitempty"`
+	BalanceInCents         CurrencyAmounts `xml:"balance_in_cents" json:"balance_in_cents,omitempty"`
+	AvailableCreditInCents CurrencyAmounts `xml:"available_credit_in_cents" json:"available_credit_in_cents,omitempty"`
 }
 
+// Balance returns the balance in currency.
+func (b AccountBalance) Balance(currency string) Money {
+	return b.BalanceInCents.Money(currency)
+}
+
+// AvailableCredit returns the open credit in currency.
+func (b AccountBalance) AvailableCredit(currency string) Money {
+	return b.AvailableCreditInCents.Money(currency)
+}
+
+// BalanceInCents was the type of AccountBalance.BalanceInCents, which only
+// held the USD and EUR balances.
+//
+// Deprecated: AccountBalance.BalanceInCents is now a CurrencyAmounts, which
+// holds every currency. Use AccountBalance.Balance, for example
+// b.Balance("USD").Amount instead of b.BalanceInCents.USD.
 type BalanceInCents struct {
-	USD int `xml:"USD"`
-	EUR int `xml:"EUR"`
+	USD int `xml:"USD" json:"USD,omitempty"`
+	EUR int `xml:"EUR" json:"EUR,omitempty"`
 }
 
 // Address is used for embedded addresses within other structs.
 type Address struct {
-	Name     string `xml:"name,omitempty"`
-	Address  string `xml:"address1,omitempty"`
-	Address2 string `xml:"address2,omitempty"`
-	City     string `xml:"city,omitempty"`
-	State    string `xml:"state,omitempty"`
-	Zip      string `xml:"zip,omitempty"`
-	Country  string `xml:"country,omitempty"`
-	Phone    string `xml:"phone,omitempty"`
+	Name     string `xml:"name,omitempty" json:"name,omitempty"`
+	Address  string `xml:"address1,omitempty" json:"address1,omitempty"`
+	Address2 string `xml:"address2,omitempty" json:"address2,omitempty"`
+	City     string `xml:"city,omitempty" json:"city,omitempty"`
+	State    string `xml:"state,omitempty" json:"state,omitempty"`
+	Zip      string `xml:"zip,omitempty" json:"zip,omitempty"`
+	Country  string `xml:"country,omitempty" json:"country,omitempty"`
+	Phone    string `xml:"phone,omitempty" json:"phone,omitempty"`
 }
 
 // MarshalXML ensures addresses marshal to nil if empty without the need
@@ -121,7 +141,7 @@ func (a Address) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 
 // Note holds account notes.
 type Note struct {
-	XMLName   xml.Name  `xml:"note"`
-	Message   string    `xml:"message,omitempty"`
-	CreatedAt time.Time `xml:"created_at,omitempty"`
+	XMLName   xml.Name  `xml:"note" json:"-"`
+	Message   string    `xml:"message,omitempty" json:"message,omitempty"`
+	CreatedAt time.Time `xml:"created_at,omitempty" json:"created_at"`
 }
@@ -302,6 +302,9 @@ func TestAccounts_LookupAccountBalance(t *testing.T) {
 						    <USD type="integer">3000</USD>
 						    <EUR type="integer">0</EUR>
 						  </balance_in_cents>
+						  <available_credit_in_cents>
+						    <GBP type="integer">1500</GBP>
+						  </available_credit_in_cents>
 						</account_balance>`)
 	})
 
@@ -316,11 +319,22 @@ func TestAccounts_LookupAccountBalance(t *testing.T) {
 		XMLName:     xml.Name{Local: "account_balance"},
 		AccountCode: "1",
 		PastDue:     false,
-		BalanceInCents: BalanceInCents{
-			USD: 3000,
-			EUR: 0,
+		BalanceInCents: CurrencyAmounts{
+			"USD": 3000,
+			"EUR": 0,
+		},
+		AvailableCreditInCents: CurrencyAmounts{
+			"GBP": 1500,
 		},
 	}, b)
+
+	if m := b.Balance("usd"); m != NewMoney(3000, "USD") {
+		t.Fatalf("unexpected balance: %v", m)
+	} else if m := b.AvailableCredit("GBP"); m != NewMoney(1500, "GBP") {
+		t.Fatalf("unexpected available credit: %v", m)
+	} else if m := b.AvailableCredit("USD"); m != NewMoney(0, "USD") {
+		t.Fatalf("unexpected available credit: %v", m)
+	}
 }
 
 func TestAccounts_Create(t *testing.T) {
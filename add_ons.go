@@ -4,13 +4,13 @@ import "encoding/xml"
 
 // AddOn represents an individual add on linked to a plan.
 type AddOn struct {
-	XMLName                     xml.Name   `xml:"add_on"`
-	Code                        string     `xml:"add_on_code,omitempty"`
-	Name                        string     `xml:"name,omitempty"`
-	DefaultQuantity             NullInt    `xml:"default_quantity,omitempty"`
-	DisplayQuantityOnHostedPage NullBool   `xml:"display_quantity_on_hosted_page,omitempty"`
-	TaxCode                     string     `xml:"tax_code,omitempty"`
-	UnitAmountInCents           UnitAmount `xml:"unit_amount_in_cents,omitempty"`
-	AccountingCode              string     `xml:"accounting_code,omitempty"`
-	CreatedAt                   NullTime   `xml:"created_at,omitempty"`
+	XMLName                     xml.Name   `xml:"add_on" json:"-"`
+	Code                        string     `xml:"add_on_code,omitempty" json:"add_on_code,omitempty"`
+	Name                        string     `xml:"name,omitempty" json:"name,omitempty"`
+	DefaultQuantity             NullInt    `xml:"default_quantity,omitempty" json:"default_quantity"`
+	DisplayQuantityOnHostedPage NullBool   `xml:"display_quantity_on_hosted_page,omitempty" json:"display_quantity_on_hosted_page"`
+	TaxCode                     string     `xml:"tax_code,omitempty" json:"tax_code,omitempty"`
+	UnitAmountInCents           UnitAmount `xml:"unit_amount_in_cents,omitempty" json:"unit_amount_in_cents"`
+	AccountingCode              string     `xml:"accounting_code,omitempty" json:"accounting_code,omitempty"`
+	CreatedAt                   NullTime   `xml:"created_at,omitempty" json:"created_at"`
 }
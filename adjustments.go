@@ -10,33 +10,33 @@ const (
 
 // Adjustment works with charges and credits on a given account.
 type Adjustment struct {
-	AccountCode            string
-	InvoiceNumber          int
-	SubscriptionUUID       string
-	UUID                   string
-	State                  string
-	Description            string
-	AccountingCode         string
-	ProductCode            string
-	Origin                 string
-	UnitAmountInCents      int
-	Quantity               int
-	OriginalAdjustmentUUID string
-	DiscountInCents        int
-	TaxInCents             int
-	TotalInCents           int
-	Currency               string
-	Taxable                NullBool
-	TaxCode                string
-	TaxType                string
-	TaxRegion              string
-	TaxRate                float64
-	TaxExempt              NullBool
-	TaxDetails             []TaxDetail
-	StartDate              NullTime
-	EndDate                NullTime
-	CreatedAt              NullTime
-	UpdatedAt              NullTime
+	AccountCode            string      `json:"account_code,omitempty"`
+	InvoiceNumber          int         `json:"invoice_number,omitempty"`
+	SubscriptionUUID       string      `json:"subscription_uuid,omitempty"`
+	UUID                   string      `json:"uuid,omitempty"`
+	State                  string      `json:"state,omitempty"`
+	Description            string      `json:"description,omitempty"`
+	AccountingCode         string      `json:"accounting_code,omitempty"`
+	ProductCode            string      `json:"product_code,omitempty"`
+	Origin                 string      `json:"origin,omitempty"`
+	UnitAmountInCents      int         `json:"unit_amount_in_cents,omitempty"`
+	Quantity               int         `json:"quantity,omitempty"`
+	OriginalAdjustmentUUID string      `json:"original_adjustment_uuid,omitempty"`
+	DiscountInCents        int         `json:"discount_in_cents,omitempty"`
+	TaxInCents             int         `json:"tax_in_cents,omitempty"`
+	TotalInCents           int         `json:"total_in_cents,omitempty"`
+	Currency               string      `json:"currency,omitempty"`
+	Taxable                NullBool    `json:"taxable"`
+	TaxCode                string      `json:"tax_code,omitempty"`
+	TaxType                string      `json:"tax_type,omitempty"`
+	TaxRegion              string      `json:"tax_region,omitempty"`
+	TaxRate                float64     `json:"tax_rate,omitempty"`
+	TaxExempt              NullBool    `json:"tax_exempt"`
+	TaxDetails             []TaxDetail `json:"tax_details,omitempty"`
+	StartDate              NullTime    `json:"start_date"`
+	EndDate                NullTime    `json:"end_date"`
+	CreatedAt              NullTime    `json:"created_at"`
+	UpdatedAt              NullTime    `json:"updated_at"`
 }
 
 // MarshalXML marshals only the fields needed for creating/updating adjustments
@@ -70,39 +70,30 @@ func (a Adjustment) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	return e.Encode(v)
 }
 
+// UnitAmount returns the adjustment's unit amount.
+func (a Adjustment) UnitAmount() Money {
+	return NewMoney(a.UnitAmountInCents, a.Currency)
+}
+
+// Discount returns the adjustment's discount.
+func (a Adjustment) Discount() Money {
+	return NewMoney(a.DiscountInCents, a.Currency)
+}
+
+// Tax returns the adjustment's tax.
+func (a Adjustment) Tax() Money {
+	return NewMoney(a.TaxInCents, a.Currency)
+}
+
+// Total returns the adjustment's total.
+func (a Adjustment) Total() Money {
+	return NewMoney(a.TotalInCents, a.Currency)
+}
+
 // UnmarshalXML unmarshal a coupon redemption object. Minaly converts href links
 // for coupons and accounts to CouponCode and AccountCodes.
 func (a *Adjustment) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	var v struct {
-		XMLName                xml.Name    `xml:"adjustment"`
-		AccountCode            hrefString  `xml:"account,omitempty"`      // Read only
-		InvoiceNumber          hrefInt     `xml:"invoice,omitempty"`      // Read only
-		SubscriptionUUID       hrefString  `xml:"subscription,omitempty"` // Read only
-		UUID                   string      `xml:"uuid,omitempty"`
-		State                  string      `xml:"state,omitempty"`
-		Description            string      `xml:"description,omitempty"`
-		AccountingCode         string      `xml:"accounting_code,omitempty"`
-		ProductCode            string      `xml:"product_code,omitempty"`
-		Origin                 string      `xml:"origin,omitempty"`
-		UnitAmountInCents      int         `xml:"unit_amount_in_cents"`
-		Quantity               int         `xml:"quantity,omitempty"`
-		OriginalAdjustmentUUID string      `xml:"original_adjustment_uuid,omitempty"`
-		DiscountInCents        int         `xml:"discount_in_cents,omitempty"`
-		TaxInCents             int         `xml:"tax_in_cents,omitempty"`
-		TotalInCents           int         `xml:"total_in_cents,omitempty"`
-		Currency               string      `xml:"currency,omitempty"`
-		Taxable                NullBool    `xml:"taxable,omitempty"`
-		TaxCode                string      `xml:"tax_code,omitempty"`
-		TaxType                string      `xml:"tax_type,omitempty"`
-		TaxRegion              string      `xml:"tax_region,omitempty"`
-		TaxRate                float64     `xml:"tax_rate,omitempty"`
-		TaxExempt              NullBool    `xml:"tax_exempt,omitempty"`
-		TaxDetails             []TaxDetail `xml:"tax_details>tax_detail,omitempty"`
-		StartDate              NullTime    `xml:"start_date,omitempty"`
-		EndDate                NullTime    `xml:"end_date,omitempty"`
-		CreatedAt              NullTime    `xml:"created_at,omitempty"`
-		UpdatedAt              NullTime    `xml:"updated_at,omitempty"`
-	}
+	var v adjustmentFields
 	if err := d.DecodeElement(&v, &start); err != nil {
 		return err
 	}
@@ -139,12 +130,44 @@ func (a *Adjustment) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 	return nil
 }
 
+// adjustmentFields is used by the custom unmarshal function.
+type adjustmentFields struct {
+	XMLName                xml.Name    `xml:"adjustment"`
+	AccountCode            hrefString  `xml:"account,omitempty"`      // Read only
+	InvoiceNumber          hrefInt     `xml:"invoice,omitempty"`      // Read only
+	SubscriptionUUID       hrefString  `xml:"subscription,omitempty"` // Read only
+	UUID                   string      `xml:"uuid,omitempty"`
+	State                  string      `xml:"state,omitempty"`
+	Description            string      `xml:"description,omitempty"`
+	AccountingCode         string      `xml:"accounting_code,omitempty"`
+	ProductCode            string      `xml:"product_code,omitempty"`
+	Origin                 string      `xml:"origin,omitempty"`
+	UnitAmountInCents      int         `xml:"unit_amount_in_cents"`
+	Quantity               int         `xml:"quantity,omitempty"`
+	OriginalAdjustmentUUID string      `xml:"original_adjustment_uuid,omitempty"`
+	DiscountInCents        int         `xml:"discount_in_cents,omitempty"`
+	TaxInCents             int         `xml:"tax_in_cents,omitempty"`
+	TotalInCents           int         `xml:"total_in_cents,omitempty"`
+	Currency               string      `xml:"currency,omitempty"`
+	Taxable                NullBool    `xml:"taxable,omitempty"`
+	TaxCode                string      `xml:"tax_code,omitempty"`
+	TaxType                string      `xml:"tax_type,omitempty"`
+	TaxRegion              string      `xml:"tax_region,omitempty"`
+	TaxRate                float64     `xml:"tax_rate,omitempty"`
+	TaxExempt              NullBool    `xml:"tax_exempt,omitempty"`
+	TaxDetails             []TaxDetail `xml:"tax_details>tax_detail,omitempty"`
+	StartDate              NullTime    `xml:"start_date,omitempty"`
+	EndDate                NullTime    `xml:"end_date,omitempty"`
+	CreatedAt              NullTime    `xml:"created_at,omitempty"`
+	UpdatedAt              NullTime    `xml:"updated_at,omitempty"`
+}
+
 // TaxDetail holds tax information and is embedded in an Adjustment.
 // TaxDetails are a read only field, so theys houldn't marshall
 type TaxDetail struct {
-	XMLName    xml.Name `xml:"tax_detail"`
-	Name       string   `xml:"name,omitempty"`
-	Type       string   `xml:"type,omitempty"`
-	TaxRate    float64  `xml:"tax_rate,omitempty"`
-	TaxInCents int      `xml:"tax_in_cents,omitempty"`
+	XMLName    xml.Name `xml:"tax_detail" json:"-"`
+	Name       string   `xml:"name,omitempty" json:"name,omitempty"`
+	Type       string   `xml:"type,omitempty" json:"type,omitempty"`
+	TaxRate    float64  `xml:"tax_rate,omitempty" json:"tax_rate,omitempty"`
+	TaxInCents int      `xml:"tax_in_cents,omitempty" json:"tax_in_cents,omitempty"`
 }
@@ -7,92 +7,55 @@ import (
 
 // Billing represents billing info for a single account on your site
 type Billing struct {
-	XMLName          xml.Name `xml:"billing_info"`
-	FirstName        string   `xml:"first_name,omitempty"`
-	LastName         string   `xml:"last_name,omitempty"`
-	Company          string   `xml:"company,omitempty"`
-	Address          string   `xml:"address1,omitempty"`
-	Address2         string   `xml:"address2,omitempty"`
-	City             string   `xml:"city,omitempty"`
-	State            string   `xml:"state,omitempty"`
-	Zip              string   `xml:"zip,omitempty"`
-	Country          string   `xml:"country,omitempty"`
-	Phone            string   `xml:"phone,omitempty"`
-	VATNumber        string   `xml:"vat_number,omitempty"`
-	IPAddress        net.IP   `xml:"ip_address,omitempty"`
-	IPAddressCountry string   `xml:"ip_address_country,omitempty"`
+	XMLName          xml.Name `xml:"billing_info" json:"-"`
+	FirstName        string   `xml:"first_name,omitempty" json:"first_name,omitempty"`
+	LastName         string   `xml:"last_name,omitempty" json:"last_name,omitempty"`
+	Company          string   `xml:"company,omitempty" json:"company,omitempty"`
+	Address          string   `xml:"address1,omitempty" json:"address1,omitempty"`
+	Address2         string   `xml:"address2,omitempty" json:"address2,omitempty"`
+	City             string   `xml:"city,omitempty" json:"city,omitempty"`
+	State            string   `xml:"state,omitempty" json:"state,omitempty"`
+	Zip              string   `xml:"zip,omitempty" json:"zip,omitempty"`
+	Country          string   `xml:"country,omitempty" json:"country,omitempty"`
+	Phone            string   `xml:"phone,omitempty" json:"phone,omitempty"`
+	VATNumber        string   `xml:"vat_number,omitempty" json:"vat_number,omitempty"`
+	IPAddress        net.IP   `xml:"ip_address,omitempty" json:"ip_address,omitempty"`
+	IPAddressCountry string   `xml:"ip_address_country,omitempty" json:"ip_address_country,omitempty"`
 
 	// Credit Card Info
-	FirstSix int    `xml:"first_six,omitempty"`
-	LastFour string `xml:"last_four,omitempty"` // String not int so that leading zeros are present
-	CardType string `xml:"card_type,omitempty"`
-	Number   int    `xml:"number,omitempty"`
-	Month    int    `xml:"month,omitempty"`
-	Year     int    `xml:"year,omitempty"`
+	FirstSix int    `xml:"first_six,omitempty" json:"first_six,omitempty"`
+	LastFour string `xml:"last_four,omitempty" json:"last_four,omitempty"` // String not int so that leading zeros are present
+	CardType string `xml:"card_type,omitempty" json:"card_type,omitempty"`
+	Number   int    `xml:"number,omitempty" json:"-"`
+	Month    int    `xml:"month,omitempty" json:"month,omitempty"`
+	Year     int    `xml:"year,omitempty" json:"year,omitempty"`
 	// VerificationValue is only used for create/update only. A Verification
 	// Value will never be returned on read.
-	VerificationValue int `xml:"verification_value,omitempty"`
+	VerificationValue int `xml:"verification_value,omitempty" json:"-"`
 
 	// Paypal
-	PaypalAgreementID string `xml:"paypal_billing_agreement_id,omitempty"`
+	PaypalAgreementID string `xml:"paypal_billing_agreement_id,omitempty" json:"paypal_billing_agreement_id,omitempty"`
 
 	// Amazon
-	AmazonAgreementID string `xml:"amazon_billing_agreement_id,omitempty"`
+	AmazonAgreementID string `xml:"amazon_billing_agreement_id,omitempty" json:"amazon_billing_agreement_id,omitempty"`
 
 	// Bank Account
 	// Note: routing numbers and account numbers may start with zeros, so need
 	// to treat them as strings
-	NameOnAccount string `xml:"name_on_account,omitempty"`
-	RoutingNumber string `xml:"routing_number,omitempty"`
-	AccountNumber string `xml:"account_number,omitempty"`
-	AccountType   string `xml:"account_type,omitempty"`
+	NameOnAccount string `xml:"name_on_account,omitempty" json:"name_on_account,omitempty"`
+	RoutingNumber string `xml:"routing_number,omitempty" json:"-"`
+	AccountNumber string `xml:"account_number,omitempty" json:"-"`
+	AccountType   string `xml:"account_type,omitempty" json:"account_type,omitempty"`
 
 	// Token is used for create/update only. A token will never be returned
 	// on read.
-	Token string `xml:"token_id,omitempty"`
+	Token string `xml:"token_id,omitempty" json:"token_id,omitempty"`
 }
 
 // UnmarshalXML is a customer XML unmarshaler for billing info that supports
 // unmarshaling null fields without errors.
 func (b *Billing) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	var v struct {
-		XMLName          xml.Name `xml:"billing_info"`
-		FirstName        string   `xml:"first_name,omitempty"`
-		LastName         string   `xml:"last_name,omitempty"`
-		Company          string   `xml:"company,omitempty"`
-		Address          string   `xml:"address1,omitempty"`
-		Address2         string   `xml:"address2,omitempty"`
-		City             string   `xml:"city,omitempty"`
-		State            string   `xml:"state,omitempty"`
-		Zip              string   `xml:"zip,omitempty"`
-		Country          string   `xml:"country,omitempty"`
-		Phone            string   `xml:"phone,omitempty"`
-		VATNumber        string   `xml:"vat_number,omitempty"`
-		IPAddress        net.IP   `xml:"ip_address,omitempty"`
-		IPAddressCountry string   `xml:"ip_address_country,omitempty"`
-
-		// Credit Card Info
-		FirstSix NullInt `xml:"first_six,omitempty"`
-		LastFour string  `xml:"last_four,omitempty"`
-		CardType string  `xml:"card_type,omitempty"`
-		Number   int     `xml:"number,omitempty"`
-		Month    NullInt `xml:"month,omitempty"`
-		Year     NullInt `xml:"year,omitempty"`
-
-		// Paypal
-		PaypalAgreementID string `xml:"paypal_billing_agreement_id,omitempty"`
-
-		// Amazon
-		AmazonAgreementID string `xml:"amazon_billing_agreement_id,omitempty"`
-
-		// Bank Account
-		// Note: routing numbers and account numbers may start with zeros, so need
-		// to treat them as strings
-		NameOnAccount string `xml:"name_on_account,omitempty"`
-		RoutingNumber string `xml:"routing_number,omitempty"`
-		AccountNumber string `xml:"account_number,omitempty"`
-		AccountType   string `xml:"account_type,omitempty"`
-	}
+	var v billingFields
 	if err := d.DecodeElement(&v, &start); err != nil {
 		return err
 	}
@@ -130,6 +93,46 @@ func (b *Billing) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	return nil
 }
 
+// billingFields is used by the custom unmarshal function.
+type billingFields struct {
+	XMLName          xml.Name `xml:"billing_info"`
+	FirstName        string   `xml:"first_name,omitempty"`
+	LastName         string   `xml:"last_name,omitempty"`
+	Company          string   `xml:"company,omitempty"`
+	Address          string   `xml:"address1,omitempty"`
+	Address2         string   `xml:"address2,omitempty"`
+	City             string   `xml:"city,omitempty"`
+	State            string   `xml:"state,omitempty"`
+	Zip              string   `xml:"zip,omitempty"`
+	Country          string   `xml:"country,omitempty"`
+	Phone            string   `xml:"phone,omitempty"`
+	VATNumber        string   `xml:"vat_number,omitempty"`
+	IPAddress        net.IP   `xml:"ip_address,omitempty"`
+	IPAddressCountry string   `xml:"ip_address_country,omitempty"`
+
+	// Credit Card Info
+	FirstSix NullInt `xml:"first_six,omitempty"`
+	LastFour string  `xml:"last_four,omitempty"`
+	CardType string  `xml:"card_type,omitempty"`
+	Number   int     `xml:"number,omitempty"`
+	Month    NullInt `xml:"month,omitempty"`
+	Year     NullInt `xml:"year,omitempty"`
+
+	// Paypal
+	PaypalAgreementID string `xml:"paypal_billing_agreement_id,omitempty"`
+
+	// Amazon
+	AmazonAgreementID string `xml:"amazon_billing_agreement_id,omitempty"`
+
+	// Bank Account
+	// Note: routing numbers and account numbers may start with zeros, so need
+	// to treat them as strings
+	NameOnAccount string `xml:"name_on_account,omitempty"`
+	RoutingNumber string `xml:"routing_number,omitempty"`
+	AccountNumber string `xml:"account_number,omitempty"`
+	AccountType   string `xml:"account_type,omitempty"`
+}
+
 // Type returns the billing info type. Currently options: card, bank, ""
 func (b Billing) Type() string {
 	if b.FirstSix > 0 && b.LastFour != "" && b.Month > 0 && b.Year > 0 {
@@ -2,6 +2,7 @@ package recurly
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"net"
@@ -516,3 +517,26 @@ func TestBilling_Clear(t *testing.T) {
 		t.Fatal("expected deleting billing_info to return OK")
 	}
 }
+
+func TestBilling_JSON(t *testing.T) {
+	b := Billing{
+		FirstName:         "Verena",
+		LastFour:          "1111",
+		Number:            4111111111111111,
+		VerificationValue: 123,
+		RoutingNumber:     "065400137",
+		AccountNumber:     "4564564565",
+	}
+
+	given, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !bytes.Contains(given, []byte(`"last_four":"1111"`)) {
+		t.Fatalf("unexpected json: %s", given)
+	}
+	for _, s := range []string{"4111111111111111", "123", "065400137", "4564564565"} {
+		if bytes.Contains(given, []byte(s)) {
+			t.Fatalf("unexpected json, contains %s: %s", s, given)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package recurly
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// IDIterator yields the IDs a bulk operation is applied to, such as account
+// codes or subscription UUIDs.
+type IDIterator interface {
+	// Next advances to the next ID. It returns false when there are no more
+	// IDs or an error occurred.
+	Next() bool
+
+	// ID returns the current ID.
+	ID() string
+
+	// Err returns the first error encountered while iterating.
+	Err() error
+}
+
+// IDs returns an IDIterator over a fixed list of IDs.
+func IDs(ids ...string) IDIterator {
+	return &sliceIterator{ids: ids, i: -1}
+}
+
+type sliceIterator struct {
+	ids []string
+	i   int
+}
+
+func (s *sliceIterator) Next() bool {
+	s.i++
+	return s.i < len(s.ids)
+}
+
+func (s *sliceIterator) ID() string {
+	return s.ids[s.i]
+}
+
+func (s *sliceIterator) Err() error {
+	return nil
+}
+
+// BulkFunc applies a change to the resource identified by id.
+type BulkFunc func(c *Client, id string) (*Response, error)
+
+// BulkOperation is a change applied to many resources by Client.Bulk.
+type BulkOperation struct {
+	// Do applies the change.
+	Do BulkFunc
+
+	// Preview is called instead of Do in dry-run mode. It must not modify
+	// anything, for example by calling a preview endpoint or Get.
+	Preview BulkFunc
+}
+
+// BulkOptions configures Client.Bulk.
+type BulkOptions struct {
+	// Concurrency is the maximum number of requests in flight. Defaults to 4.
+	Concurrency int
+
+	// DryRun calls the operation's Preview function instead of Do.
+	DryRun bool
+
+	// MaxRetries is the number of times an item is retried after a 429
+	// Too Many Requests response. Defaults to 3 if zero; set it to -1 to
+	// disable retries.
+	MaxRetries int
+
+	// OnResult, if set, is called as each item completes. Calls are
+	// serialized, so it is safe to write progress from it.
+	OnResult func(BulkResult)
+}
+
+// BulkResult is the outcome of a bulk operation for a single ID.
+type BulkResult struct {
+	// ID is the ID the operation was applied to.
+	ID string
+
+	// Response is the last response received, if any.
+	Response *Response
+
+	// Errors holds the validation errors returned by Recurly, if any.
+	Errors []Error
+
+	// Err holds a transport or decoding error, if any.
+	Err error
+}
+
+// OK returns true if the operation succeeded.
+func (r BulkResult) OK() bool {
+	return r.Err == nil && r.Response != nil && r.Response.IsOK()
+}
+
+// BulkReport holds the results of Client.Bulk in the order the IDs were
+// returned by the iterator.
+type BulkReport struct {
+	DryRun  bool
+	Results []BulkResult
+}
+
+// Succeeded returns the results of the items that succeeded.
+func (r *BulkReport) Succeeded() []BulkResult {
+	var results []BulkResult
+	for _, result := range r.Results {
+		if result.OK() {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// Failed returns the results of the items that failed.
+func (r *BulkReport) Failed() []BulkResult {
+	var results []BulkResult
+	for _, result := range r.Results {
+		if !result.OK() {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// ErrNoPreview is returned by Client.Bulk in dry-run mode when the operation
+// has no Preview function.
+var ErrNoPreview = errors.New("recurly: bulk operation has no preview")
+
+// bulkSleep is replaced in tests.
+var bulkSleep = time.Sleep
+
+// Bulk applies op to each ID returned by ids with bounded concurrency.
+// When a response reports the rate limit is nearly exhausted, or the API
+// responds with 429 Too Many Requests, all workers pause until the limit
+// resets. Items that were rate limited are retried.
+//
+// A failure for one item does not stop the others; check the report for
+// per-item results. The returned error is only set if the operation could
+// not be run or the iterator failed, in which case the report holds the
+// items processed before the failure.
+func (c *Client) Bulk(ids IDIterator, op BulkOperation, opts BulkOptions) (*BulkReport, error) {
+	fn := op.Do
+	if opts.DryRun {
+		fn = op.Preview
+		if fn == nil {
+			return nil, ErrNoPreview
+		}
+	} else if fn == nil {
+		return nil, errors.New("recurly: bulk operation has no Do function")
+	}
+
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = 3
+	} else if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+
+	type job struct {
+		index int
+		id    string
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		report   = &BulkReport{DryRun: opts.DryRun}
+		jobs     = make(chan job)
+		throttle = &bulkThrottle{threshold: opts.Concurrency}
+	)
+
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				result := c.bulkOne(fn, j.id, throttle, opts.MaxRetries)
+
+				mu.Lock()
+				report.Results[j.index] = result
+				if opts.OnResult != nil {
+					opts.OnResult(result)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var n int
+	for ids.Next() {
+		mu.Lock()
+		report.Results = append(report.Results, BulkResult{})
+		mu.Unlock()
+
+		jobs <- job{index: n, id: ids.ID()}
+		n++
+	}
+	close(jobs)
+	wg.Wait()
+
+	return report, ids.Err()
+}
+
+// bulkOne runs fn for a single ID, retrying if the API is rate limited.
+func (c *Client) bulkOne(fn BulkFunc, id string, throttle *bulkThrottle, maxRetries int) BulkResult {
+	result := BulkResult{ID: id}
+	for attempt := 0; ; attempt++ {
+		throttle.wait()
+		resp, err := fn(c, id)
+		throttle.observe(resp)
+
+		result.Response, result.Err = resp, err
+		if resp != nil {
+			result.Errors = resp.Errors
+		}
+		if resp == nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= maxRetries {
+			return result
+		}
+	}
+}
+
+// bulkThrottle pauses all workers once the rate limit is nearly exhausted.
+type bulkThrottle struct {
+	mu        sync.Mutex
+	until     time.Time
+	threshold int
+}
+
+// wait blocks until any pause has ended.
+func (t *bulkThrottle) wait() {
+	t.mu.Lock()
+	d := time.Until(t.until)
+	t.mu.Unlock()
+	if d > 0 {
+		bulkSleep(d)
+	}
+}
+
+// observe pauses workers if resp shows the rate limit is exhausted.
+func (t *bulkThrottle) observe(resp *Response) {
+	if resp == nil || resp.Response == nil {
+		return
+	}
+
+	var until time.Time
+	rl, ok := resp.RateLimit()
+	if resp.StatusCode == http.StatusTooManyRequests {
+		until = time.Now().Add(time.Second)
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			until = time.Now().Add(time.Duration(secs) * time.Second)
+		} else if ok && rl.Reset.After(time.Now()) {
+			until = rl.Reset
+		}
+	} else if ok && rl.Remaining <= t.threshold {
+		until = rl.Reset
+	}
+
+	t.mu.Lock()
+	if until.After(t.until) {
+		t.until = until
+	}
+	t.mu.Unlock()
+}
+
+// BulkPostponeSubscriptions returns an operation that postpones each
+// subscription to dt. In dry-run mode each subscription is fetched instead.
+func BulkPostponeSubscriptions(dt time.Time, bulk bool) BulkOperation {
+	return BulkOperation{
+		Do: func(c *Client, uuid string) (*Response, error) {
+			resp, _, err := c.Subscriptions.Postpone(uuid, dt, bulk)
+			return resp, err
+		},
+		Preview: getSubscription,
+	}
+}
+
+// BulkCancelSubscriptions returns an operation that cancels each
+// subscription. In dry-run mode each subscription is fetched instead.
+func BulkCancelSubscriptions() BulkOperation {
+	return BulkOperation{
+		Do: func(c *Client, uuid string) (*Response, error) {
+			resp, _, err := c.Subscriptions.Cancel(uuid)
+			return resp, err
+		},
+		Preview: getSubscription,
+	}
+}
+
+// BulkUpdateSubscriptions returns an operation that applies sub to each
+// subscription. In dry-run mode the change is previewed with PreviewChange.
+func BulkUpdateSubscriptions(sub UpdateSubscription) BulkOperation {
+	return BulkOperation{
+		Do: func(c *Client, uuid string) (*Response, error) {
+			resp, _, err := c.Subscriptions.Update(uuid, sub)
+			return resp, err
+		},
+		Preview: func(c *Client, uuid string) (*Response, error) {
+			resp, _, err := c.Subscriptions.PreviewChange(uuid, sub)
+			return resp, err
+		},
+	}
+}
+
+func getSubscription(c *Client, uuid string) (*Response, error) {
+	resp, _, err := c.Subscriptions.Get(uuid)
+	return resp, err
+}
@@ -0,0 +1,217 @@
+package recurly
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_Bulk(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	mux.HandleFunc("/v2/subscriptions/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		time.Sleep(5 * time.Millisecond)
+
+		if r.Method != "PUT" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if r.URL.Path == "/v2/subscriptions/sub5/cancel" {
+			w.WriteHeader(404)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><error><symbol>not_found</symbol><description lang="en-US">Couldn't find Subscription with uuid = sub5</description></error>`)
+			return
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><state>canceled</state></subscription>`)
+	})
+
+	var ids []string
+	for i := 0; i < 10; i++ {
+		ids = append(ids, "sub"+strconv.Itoa(i))
+	}
+
+	var called int
+	report, err := client.Bulk(IDs(ids...), BulkCancelSubscriptions(), BulkOptions{
+		Concurrency: 3,
+		OnResult:    func(BulkResult) { called++ },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if report.DryRun {
+		t.Fatal("expected DryRun to be false")
+	} else if len(report.Results) != 10 || called != 10 {
+		t.Fatalf("unexpected results: %d, called %d", len(report.Results), called)
+	} else if maxInFlight > 3 {
+		t.Fatalf("expected at most 3 requests in flight, given %d", maxInFlight)
+	} else if len(report.Succeeded()) != 9 {
+		t.Fatalf("unexpected succeeded: %d", len(report.Succeeded()))
+	}
+
+	for i, result := range report.Results {
+		if result.ID != ids[i] {
+			t.Fatalf("unexpected order: %d %s", i, result.ID)
+		}
+	}
+
+	failed := report.Failed()
+	if len(failed) != 1 {
+		t.Fatalf("unexpected failed: %#v", failed)
+	} else if failed[0].ID != "sub5" || failed[0].Err != nil || failed[0].Response.StatusCode != 404 {
+		t.Fatalf("unexpected failed result: %#v", failed[0])
+	} else if len(failed[0].Errors) != 1 || failed[0].Errors[0].Symbol != "not_found" {
+		t.Fatalf("unexpected errors: %#v", failed[0].Errors)
+	}
+}
+
+func TestClient_Bulk_DryRun(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/subscriptions/sub1/preview", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><plan><plan_code>gold</plan_code></plan></subscription>`)
+	})
+	mux.HandleFunc("/v2/subscriptions/sub1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
+	})
+
+	report, err := client.Bulk(IDs("sub1"), BulkUpdateSubscriptions(UpdateSubscription{PlanCode: "gold"}), BulkOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !report.DryRun || len(report.Succeeded()) != 1 {
+		t.Fatalf("unexpected report: %#v", report)
+	}
+
+	// Operations without a preview endpoint fetch the subscription.
+	report, err = client.Bulk(IDs("sub1"), BulkPostponeSubscriptions(time.Now(), true), BulkOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(report.Succeeded()) != 1 {
+		t.Fatalf("unexpected report: %#v", report)
+	}
+
+	op := BulkOperation{Do: func(c *Client, id string) (*Response, error) {
+		t.Fatal("unexpected call")
+		return nil, nil
+	}}
+	if _, err := client.Bulk(IDs("sub1"), op, BulkOptions{DryRun: true}); err != ErrNoPreview {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestClient_Bulk_RateLimit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var slept []time.Duration
+	bulkSleep = func(d time.Duration) { slept = append(slept, d) }
+	defer func() { bulkSleep = time.Sleep }()
+
+	reset := time.Now().Add(time.Hour).Unix()
+	var requests int
+	mux.HandleFunc("/v2/subscriptions/sub1/postpone", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(429)
+			return
+		}
+		w.Header().Set("X-RateLimit-Limit", "2000")
+		w.Header().Set("X-RateLimit-Remaining", "1")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
+	})
+	mux.HandleFunc("/v2/subscriptions/sub2/postpone", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription></subscription>`)
+	})
+
+	report, err := client.Bulk(IDs("sub1", "sub2"), BulkPostponeSubscriptions(time.Now(), true), BulkOptions{Concurrency: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(report.Succeeded()) != 2 {
+		t.Fatalf("unexpected report: %#v", report.Failed())
+	} else if requests != 2 {
+		t.Fatalf("expected rate limited request to be retried, given %d requests", requests)
+	} else if len(slept) != 2 {
+		t.Fatalf("unexpected sleeps: %v", slept)
+	} else if slept[0] <= 29*time.Second || slept[0] > 30*time.Second {
+		t.Fatalf("expected to wait for Retry-After, given %v", slept[0])
+	} else if slept[1] <= 59*time.Minute || slept[1] > time.Hour {
+		t.Fatalf("expected to wait for rate limit reset, given %v", slept[1])
+	}
+}
+
+func TestClient_Bulk_MaxRetries(t *testing.T) {
+	setup()
+	defer teardown()
+
+	bulkSleep = func(time.Duration) {}
+	defer func() { bulkSleep = time.Sleep }()
+
+	var requests int
+	mux.HandleFunc("/v2/subscriptions/sub1/cancel", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(429)
+	})
+
+	report, err := client.Bulk(IDs("sub1"), BulkCancelSubscriptions(), BulkOptions{MaxRetries: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if requests != 3 {
+		t.Fatalf("unexpected requests: %d", requests)
+	} else if failed := report.Failed(); len(failed) != 1 || failed[0].Response.StatusCode != 429 {
+		t.Fatalf("unexpected failed: %#v", failed)
+	}
+
+	// Negative MaxRetries disables retries.
+	requests = 0
+	if _, err := client.Bulk(IDs("sub1"), BulkCancelSubscriptions(), BulkOptions{MaxRetries: -1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if requests != 1 {
+		t.Fatalf("unexpected requests: %d", requests)
+	}
+}
+
+type errIterator struct{ sliceIterator }
+
+func (e *errIterator) Err() error { return fmt.Errorf("iterator failed") }
+
+func TestClient_Bulk_IteratorError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	op := BulkOperation{Do: func(c *Client, id string) (*Response, error) {
+		return nil, fmt.Errorf("transport error for %s", id)
+	}}
+	report, err := client.Bulk(&errIterator{sliceIterator{ids: []string{"a"}, i: -1}}, op, BulkOptions{})
+	if err == nil || err.Error() != "iterator failed" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(report.Results) != 1 || report.Results[0].OK() || !strings.Contains(report.Results[0].Err.Error(), "transport error for a") {
+		t.Fatalf("unexpected results: %#v", report.Results)
+	}
+}
@@ -0,0 +1,387 @@
+// Package cassette provides an http.RoundTripper that records interactions
+// with the Recurly API to a file and replays them in tests.
+//
+// Record a cassette once against a real (sandbox) site:
+//
+//	rec, err := cassette.New("testdata/accounts.json", cassette.ModeRecord)
+//	client := recurly.NewClient("subdomain", "apiKey", rec.Client())
+//
+// Then replay it without network access:
+//
+//	rec, err := cassette.New("testdata/accounts.json", cassette.ModeReplay)
+//	client := recurly.NewClient("subdomain", "apiKey", rec.Client())
+//
+// The Authorization header and card data are filtered before anything is
+// written to disk. Bodies that are not well-formed XML cannot be filtered,
+// so they are recorded as Filtered.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Mode determines whether a Recorder records or replays interactions.
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette file and never makes
+	// a network request.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends requests to the real API and appends each
+	// interaction to the cassette file.
+	ModeRecord
+)
+
+// Filtered replaces sensitive values in recorded interactions.
+const Filtered = "[FILTERED]"
+
+// filteredHeaders are replaced with Filtered when recorded.
+var filteredHeaders = []string{"Authorization"}
+
+// filteredElements are XML elements whose text is replaced with Filtered
+// when recorded.
+var filteredElements = map[string]bool{
+	"number":             true,
+	"verification_value": true,
+	"account_number":     true,
+	"routing_number":     true,
+	"iban":               true,
+	"sort_code":          true,
+	"bsb_code":           true,
+}
+
+// Cassette is the file format of a recorded session.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request and response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// ErrNoInteraction is returned in replay mode when no unused interaction in
+// the cassette matches a request. It implements the error interface.
+type ErrNoInteraction struct {
+	Method string
+	Action string
+}
+
+// Error implements the error interface.
+func (e ErrNoInteraction) Error() string {
+	return fmt.Sprintf("cassette: no recorded interaction for %s %s", e.Method, e.Action)
+}
+
+// Recorder is an http.RoundTripper that records or replays interactions.
+// Requests are matched on method, action (path and sorted query) and the
+// normalized XML body. Each recorded interaction is replayed at most once,
+// in the order recorded, so repeated requests can return different
+// responses.
+type Recorder struct {
+	// Transport is used to make requests in record mode. If nil,
+	// http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	path     string
+	mode     Mode
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New returns a Recorder for the cassette file at path. In replay mode the
+// file must exist. In record mode any existing file is overwritten as new
+// interactions are recorded.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode}
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &r.cassette); err != nil {
+		return nil, fmt.Errorf("cassette: %s: %v", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// Client returns an *http.Client using the Recorder as its transport. It
+// can be passed directly to recurly.NewClient.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns the interactions recorded or loaded so far.
+func (r *Recorder) Interactions() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Interaction(nil), r.cassette.Interactions...)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+		req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	}
+
+	if r.mode == ModeRecord {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	action := Action(req)
+	reqBody := NormalizeXML(filterXML(string(body)))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || in.Request.Method != req.Method {
+			continue
+		}
+		if actionOf(in.Request.URL) != action || NormalizeXML(in.Request.Body) != reqBody {
+			continue
+		}
+
+		r.used[i] = true
+		return in.Response.httpResponse(req), nil
+	}
+	return nil, ErrNoInteraction{Method: req.Method, Action: action}
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	in := &Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: filterHeader(req.Header),
+			Body:   filterXML(string(body)),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     filterHeader(resp.Header),
+			Body:       filterXML(string(respBody)),
+		},
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+	err = r.save()
+	r.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// save writes the cassette to disk. The caller must hold r.mu.
+func (r *Recorder) save() error {
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(b, '\n'), 0644)
+}
+
+// httpResponse returns the recorded response as an *http.Response for req.
+func (r Response) httpResponse(req *http.Request) *http.Response {
+	header := http.Header{}
+	for k, v := range r.Header {
+		header[k] = append([]string(nil), v...)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// Action returns the path and sorted query of a request, for example
+// "/v2/accounts?per_page=20&state=active". The host is ignored so cassettes
+// recorded against one subdomain replay against any base url.
+func Action(req *http.Request) string {
+	action := req.URL.Path
+	if q := req.URL.Query().Encode(); q != "" {
+		action += "?" + q
+	}
+	return action
+}
+
+func actionOf(rawurl string) string {
+	req, err := http.NewRequest("GET", rawurl, nil)
+	if err != nil {
+		return rawurl
+	}
+	return Action(req)
+}
+
+// NormalizeXML returns s re-encoded without the XML declaration,
+// whitespace between elements or comments, so that documents that differ
+// only in formatting compare equal. If s is not valid XML it is returned
+// with surrounding whitespace trimmed.
+func NormalizeXML(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return s
+	}
+
+	var buf bytes.Buffer
+	d := xml.NewDecoder(strings.NewReader(s))
+	e := xml.NewEncoder(&buf)
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return s
+		}
+
+		switch t := tok.(type) {
+		case xml.ProcInst, xml.Comment, xml.Directive:
+			continue
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) == 0 {
+				continue
+			}
+		}
+		if err := e.EncodeToken(tok); err != nil {
+			return s
+		}
+	}
+	if err := e.Flush(); err != nil {
+		return s
+	}
+	return buf.String()
+}
+
+// filterHeader returns a copy of h with sensitive headers filtered.
+func filterHeader(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+
+	out := make(http.Header, len(h))
+	for k, v := range h {
+		out[k] = append([]string(nil), v...)
+	}
+	for _, k := range filteredHeaders {
+		if out.Get(k) != "" {
+			out.Set(k, Filtered)
+		}
+	}
+	return out
+}
+
+// filterXML replaces the text of card and bank account elements with
+// Filtered. Formatting is preserved. If s is not well-formed XML, such as
+// an HTML error page or a truncated body, it cannot be filtered and is
+// replaced with Filtered entirely.
+func filterXML(s string) string {
+	if s == "" {
+		return s
+	}
+
+	var buf bytes.Buffer
+	d := xml.NewDecoder(strings.NewReader(s))
+	var filtering bool
+	var last int64
+	depth := 0
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return Filtered
+		}
+
+		offset := d.InputOffset()
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			filtering = filteredElements[t.Name.Local]
+		case xml.EndElement:
+			depth--
+			filtering = false
+		case xml.CharData:
+			if filtering && len(bytes.TrimSpace(t)) > 0 {
+				buf.WriteString(Filtered)
+				last = offset
+				continue
+			}
+		}
+		buf.WriteString(s[last:offset])
+		last = offset
+	}
+	if depth != 0 {
+		return Filtered
+	}
+	buf.WriteString(s[last:])
+	return buf.String()
+}
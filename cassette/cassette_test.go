@@ -0,0 +1,221 @@
+package cassette_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kmikiy/recurly"
+	"github.com/kmikiy/recurly/cassette"
+)
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "billing.json")
+
+	var requests int
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/v2/accounts/1/billing_info", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-Request-Id", "abc")
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<billing_info type="credit_card">
+				<first_name>Verena</first_name>
+				<last_four>1111</last_four>
+			</billing_info>`)
+	})
+
+	rec, err := cassette.New(path, cassette.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := recurly.NewClient("test", "abc", rec.Client(), recurly.WithBaseURL(server.URL))
+	billing := recurly.Billing{FirstName: "Verena", Number: 4111111111111111, VerificationValue: 123}
+	if _, b, err := client.Billing.Update("1", billing); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if b.LastFour != "1111" {
+		t.Fatalf("unexpected billing: %#v", b)
+	}
+
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"4111111111111111", "123</verification_value>", "Basic "} {
+		if strings.Contains(string(raw), secret) {
+			t.Fatalf("expected %q to be filtered from cassette: %s", secret, raw)
+		}
+	}
+
+	var c cassette.Cassette
+	if err := json.Unmarshal(raw, &c); err != nil {
+		t.Fatal(err)
+	} else if len(c.Interactions) != 1 {
+		t.Fatalf("unexpected interactions: %d", len(c.Interactions))
+	} else if in := c.Interactions[0]; in.Request.Method != "PUT" {
+		t.Fatalf("unexpected method: %s", in.Request.Method)
+	} else if in.Request.Header.Get("Authorization") != cassette.Filtered {
+		t.Fatalf("unexpected authorization: %s", in.Request.Header.Get("Authorization"))
+	} else if !strings.Contains(in.Request.Body, "<number>"+cassette.Filtered+"</number>") {
+		t.Fatalf("unexpected request body: %s", in.Request.Body)
+	} else if in.Response.StatusCode != 200 || in.Response.Header.Get("X-Request-Id") != "abc" {
+		t.Fatalf("unexpected response: %#v", in.Response)
+	}
+
+	// Replay against a different base url with a different card number.
+	rec, err = cassette.New(path, cassette.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = recurly.NewClient("other", "xyz", rec.Client())
+	billing.Number = 4000000000000002
+	if resp, b, err := client.Billing.Update("1", billing); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.StatusCode != 200 || resp.Header.Get("X-Request-Id") != "abc" {
+		t.Fatalf("unexpected response: %#v", resp)
+	} else if b.FirstName != "Verena" || b.LastFour != "1111" {
+		t.Fatalf("unexpected billing: %#v", b)
+	} else if requests != 1 {
+		t.Fatalf("expected replay not to make a request, given %d requests", requests)
+	}
+
+	// Each interaction is replayed once.
+	if _, _, err := client.Billing.Update("1", billing); err == nil || !strings.Contains(err.Error(), "no recorded interaction for PUT /v2/accounts/1/billing_info") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRecorder_RecordUnparsable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "error.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+		switch r.URL.Path {
+		case "/v2/accounts/1/billing_info":
+			fmt.Fprint(w, `<html><body>Declined card 4111111111111111<br></body></html>`)
+		default:
+			fmt.Fprint(w, `<billing_info><number>4111111111111111</number><last_four>11`)
+		}
+	}))
+	defer server.Close()
+
+	rec, err := cassette.New(path, cassette.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := recurly.NewClient("test", "abc", rec.Client(), recurly.WithBaseURL(server.URL))
+	client.Billing.Get("1")
+	client.Billing.Get("2")
+
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	} else if strings.Contains(string(raw), "4111111111111111") {
+		t.Fatalf("expected card number to be filtered from cassette: %s", raw)
+	}
+
+	var c cassette.Cassette
+	if err := json.Unmarshal(raw, &c); err != nil {
+		t.Fatal(err)
+	} else if len(c.Interactions) != 2 {
+		t.Fatalf("unexpected interactions: %d", len(c.Interactions))
+	}
+	for _, in := range c.Interactions {
+		if in.Response.Body != cassette.Filtered {
+			t.Fatalf("unexpected response body: %s", in.Response.Body)
+		}
+	}
+}
+
+func TestRecorder_Replay(t *testing.T) {
+	rec, err := cassette.New("testdata/accounts.json", cassette.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := recurly.NewClient("test", "abc", rec.Client())
+
+	// Query parameters are matched regardless of order.
+	if resp, accounts, err := client.Accounts.List(recurly.Params{"state": "active", "per_page": 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(accounts) != 1 || accounts[0].Code != "1" {
+		t.Fatalf("unexpected accounts: %#v", accounts)
+	} else if resp.Next() != "1318388868" {
+		t.Fatalf("unexpected cursor: %s", resp.Next())
+	}
+
+	// Repeated requests return responses in the order recorded.
+	if _, a, err := client.Accounts.Get("1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if a.State != "active" {
+		t.Fatalf("unexpected account: %#v", a)
+	}
+	if _, err := client.Accounts.Close("1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, a, err := client.Accounts.Get("1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if a.State != "closed" {
+		t.Fatalf("unexpected account: %#v", a)
+	}
+
+	// Error responses are replayed too.
+	if resp, a, err := client.Accounts.Get("2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.StatusCode != http.StatusNotFound || a != nil {
+		t.Fatalf("unexpected response: %d %#v", resp.StatusCode, a)
+	}
+
+	// Bodies are matched on their normalized XML.
+	if _, a, err := client.Accounts.Create(recurly.Account{Code: "3", Email: "verena@example.com"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if a.Code != "3" {
+		t.Fatalf("unexpected account: %#v", a)
+	}
+	if _, _, err := client.Accounts.Create(recurly.Account{Code: "4"}); err == nil {
+		t.Fatal("expected error. None given.")
+	} else if _, ok := err.(*url.Error); !ok {
+		t.Fatalf("unexpected error type: %T", err)
+	}
+}
+
+func TestRecorder_MissingCassette(t *testing.T) {
+	if _, err := cassette.New("testdata/missing.json", cassette.ModeReplay); !os.IsNotExist(err) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNormalizeXML(t *testing.T) {
+	a := `<?xml version="1.0" encoding="UTF-8"?>
+		<account>
+			<!-- comment -->
+			<account_code>1</account_code>
+			<email>verena@example.com</email>
+		</account>`
+	b := `<account><account_code>1</account_code><email>verena@example.com</email></account>`
+	if cassette.NormalizeXML(a) != cassette.NormalizeXML(b) {
+		t.Fatalf("expected equal, given %q and %q", cassette.NormalizeXML(a), cassette.NormalizeXML(b))
+	} else if cassette.NormalizeXML(b) != b {
+		t.Fatalf("unexpected normalized xml: %q", cassette.NormalizeXML(b))
+	} else if cassette.NormalizeXML(" not xml < ") != "not xml <" {
+		t.Fatalf("unexpected normalized value: %q", cassette.NormalizeXML(" not xml < "))
+	}
+}
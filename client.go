@@ -8,11 +8,17 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const defaultBaseURL = "https://%s.recurly.com/"
 
+// DefaultAPIVersion is the X-Api-Version sent with each request unless
+// overridden with WithAPIVersion.
+const DefaultAPIVersion = "2.11"
+
 // Client manages communication with the Recurly API.
 type Client struct {
 	// client is the HTTP Client used to communicate with the API.
@@ -27,6 +33,26 @@ type Client struct {
 	// BaseURL is the base url for api requests.
 	BaseURL string
 
+	// apiVersion is sent as the X-Api-Version header with each request.
+	apiVersion string
+
+	// userAgent is sent as the User-Agent header if set.
+	userAgent string
+
+	// timeout is applied to the HTTP client after all options are applied.
+	timeout time.Duration
+
+	// strict enables recording unknown elements in responses.
+	strict bool
+
+	// onUnknownElements is called with responses containing unknown elements
+	// when strict is enabled.
+	onUnknownElements func(*Response)
+
+	// checkTransitions enables checking subscription state transitions
+	// before changing a subscription.
+	checkTransitions bool
+
 	// Services used for talking with different parts of the Recurly API
 	Accounts          AccountsService
 	Adjustments       AdjustmentsService
@@ -43,18 +69,81 @@ type Client struct {
 	CreditPayments    CreditPaymentsService
 }
 
+// Option configures a Client. Options are passed to NewClient.
+type Option func(*Client)
+
+// WithBaseURL overrides the default https://subdomain.recurly.com/ base url.
+// This is primarily used to point the client at a test server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if !strings.HasSuffix(baseURL, "/") {
+			baseURL += "/"
+		}
+		c.BaseURL = baseURL
+	}
+}
+
+// WithAPIVersion sets the X-Api-Version header sent with each request.
+// The methods that depend on credit invoices or invoice collections, which
+// were introduced in version 2.10, return an ErrUnsupportedAPIVersion
+// without making a request if an older version is configured: all
+// CreditPayments methods, Invoices.VoidCreditInvoice, Purchases.Create and
+// Purchases.Preview. Other methods are sent with the configured version
+// as-is.
+func WithAPIVersion(version string) Option {
+	return func(c *Client) {
+		c.apiVersion = version
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to communicate with the API.
+// It takes precedence over the httpClient passed to NewClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.client = httpClient
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with each request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithTimeout sets the timeout for each request. The HTTP client is copied
+// so the timeout does not leak into a shared client such as http.DefaultClient.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
 // NewClient returns a new instance of *Client.
 // apiKey should be everything after "Basic ".
-func NewClient(subDomain, apiKey string, httpClient *http.Client) *Client {
+func NewClient(subDomain, apiKey string, httpClient *http.Client, opts ...Option) *Client {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
 
 	client := &Client{
-		client:    httpClient,
-		subDomain: subDomain,
-		apiKey:    base64.StdEncoding.EncodeToString([]byte(apiKey)),
-		BaseURL:   fmt.Sprintf(defaultBaseURL, subDomain),
+		client:     httpClient,
+		subDomain:  subDomain,
+		apiKey:     base64.StdEncoding.EncodeToString([]byte(apiKey)),
+		BaseURL:    fmt.Sprintf(defaultBaseURL, subDomain),
+		apiVersion: DefaultAPIVersion,
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	if client.timeout > 0 {
+		hc := *client.client
+		hc.Timeout = client.timeout
+		client.client = &hc
 	}
 
 	client.Accounts = &accountsImpl{client: client}
@@ -74,6 +163,57 @@ func NewClient(subDomain, apiKey string, httpClient *http.Client) *Client {
 	return client
 }
 
+// APIVersion returns the X-Api-Version the client sends with each request.
+func (c *Client) APIVersion() string {
+	return c.apiVersion
+}
+
+// ErrUnsupportedAPIVersion is returned when a method requires a newer
+// X-Api-Version than the client is configured to send. It implements the
+// error interface.
+type ErrUnsupportedAPIVersion struct {
+	// Required is the minimum version the method depends on.
+	Required string
+
+	// Configured is the version the client sends.
+	Configured string
+}
+
+// Error implements the error interface.
+func (e ErrUnsupportedAPIVersion) Error() string {
+	return fmt.Sprintf("recurly: requires api version %s, client configured with %s", e.Required, e.Configured)
+}
+
+// requireAPIVersion returns an ErrUnsupportedAPIVersion if the configured
+// api version is older than min.
+func (c *Client) requireAPIVersion(min string) error {
+	if compareAPIVersions(c.apiVersion, min) < 0 {
+		return ErrUnsupportedAPIVersion{Required: min, Configured: c.apiVersion}
+	}
+	return nil
+}
+
+// compareAPIVersions compares two dotted version strings numerically
+// ("2.9" < "2.11"). It returns -1, 0, or 1.
+func compareAPIVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+	}
+	return 0
+}
+
 // newRequest creates an authenticated API request that is ready to send.
 func (c *Client) newRequest(method string, action string, params Params, body interface{}) (*http.Request, error) {
 	method = strings.ToUpper(method)
@@ -99,10 +239,16 @@ func (c *Client) newRequest(method string, action string, params Params, body in
 	}
 
 	req, err := http.NewRequest(method, endpoint, &buf)
+	if err != nil {
+		return nil, err
+	}
 
 	req.Header.Set("Authorization", fmt.Sprintf("Basic %s", c.apiKey))
 	req.Header.Set("Accept", "application/xml")
-	req.Header.Set("X-Api-Version", "2.11")
+	req.Header.Set("X-Api-Version", c.apiVersion)
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 	if req.Method == "POST" || req.Method == "PUT" {
 		req.Header.Set("Content-Type", "application/xml; charset=utf-8")
 	}
@@ -129,50 +275,14 @@ func (c *Client) do(req *http.Request, v interface{}) (*Response, error) {
 	}
 	decoder := xml.NewDecoder(resp.Body)
 	if response.IsError() { // Parse validation errors
-		if response.StatusCode == http.StatusUnprocessableEntity {
-			var ve struct {
-				XMLName     xml.Name     `xml:"errors"`
-				Errors      []Error      `xml:"error"`
-				Transaction *Transaction `xml:"transaction,omitempty"`
-			}
-
-			if err = decoder.Decode(&ve); err != nil {
-				return response, err
-			}
-
-			response.Errors = ve.Errors
-
-			// If the response object includes a TransactionError, set the
-			// transaction field on the response object and the TransactionError field.
-			if ve.Transaction != nil {
-				response.transaction = ve.Transaction
-			}
-		} else if response.IsClientError() { // Parse possible individual error message
-			var ve struct {
-				XMLName     xml.Name `xml:"error"`
-				Symbol      string   `xml:"symbol"`
-				Description string   `xml:"description"`
-			}
-			if err = decoder.Decode(&ve); err == io.EOF {
-				return response, nil
-			} else if err != nil {
-				return response, err
-			}
-
-			response.Errors = []Error{
-				{
-					Symbol:  ve.Symbol,
-					Message: ve.Description,
-				},
-			}
-		}
-
-		return response, nil
+		return response, decodeErrors(response, decoder)
 	}
 
 	if v != nil {
 		if w, ok := v.(io.Writer); ok {
 			io.Copy(w, resp.Body)
+		} else if c.strict {
+			err = c.decodeStrict(response, resp.Body, v)
 		} else {
 			err = decoder.Decode(&v)
 		}
@@ -180,3 +290,47 @@ func (c *Client) do(req *http.Request, v interface{}) (*Response, error) {
 
 	return response, err
 }
+
+// decodeErrors parses the validation errors or individual error message
+// of an unsuccessful response and sets them on the response.
+func decodeErrors(response *Response, decoder *xml.Decoder) error {
+	if response.StatusCode == http.StatusUnprocessableEntity {
+		var ve struct {
+			XMLName     xml.Name     `xml:"errors"`
+			Errors      []Error      `xml:"error"`
+			Transaction *Transaction `xml:"transaction,omitempty"`
+		}
+
+		if err := decoder.Decode(&ve); err != nil {
+			return err
+		}
+
+		response.Errors = ve.Errors
+
+		// If the response object includes a TransactionError, set the
+		// transaction field on the response object and the TransactionError field.
+		if ve.Transaction != nil {
+			response.transaction = ve.Transaction
+		}
+	} else if response.IsClientError() { // Parse possible individual error message
+		var ve struct {
+			XMLName     xml.Name `xml:"error"`
+			Symbol      string   `xml:"symbol"`
+			Description string   `xml:"description"`
+		}
+		if err := decoder.Decode(&ve); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		response.Errors = []Error{
+			{
+				Symbol:  ve.Symbol,
+				Message: ve.Description,
+			},
+		}
+	}
+
+	return nil
+}
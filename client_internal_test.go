@@ -9,14 +9,14 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // TestClient_NewRequest tests the internals of recurly.client.
 func TestClient_NewRequest(t *testing.T) {
 	mux := http.NewServeMux()
 	server := httptest.NewServer(mux)
-	client := NewClient("test", "abc", nil)
-	client.BaseURL = server.URL + "/"
+	client := NewClient("test", "abc", nil, WithBaseURL(server.URL))
 	defer server.Close()
 
 	// API key should be base64 encoded.
@@ -74,8 +74,7 @@ func TestClient_NewRequest(t *testing.T) {
 func TestClient_Error(t *testing.T) {
 	mux := http.NewServeMux()
 	server := httptest.NewServer(mux)
-	client := NewClient("test", "abc", nil)
-	client.BaseURL = server.URL + "/"
+	client := NewClient("test", "abc", nil, WithBaseURL(server.URL))
 	defer server.Close()
 
 	mux.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
@@ -123,3 +122,128 @@ func TestClient_Error(t *testing.T) {
 		t.Fatalf("unexpected error: %v", resp.Errors)
 	}
 }
+
+// TestClient_Options tests the options accepted by NewClient.
+func TestClient_Options(t *testing.T) {
+	client := NewClient("test", "abc", nil)
+	if client.BaseURL != "https://test.recurly.com/" {
+		t.Fatalf("unexpected base url: %s", client.BaseURL)
+	} else if client.client != http.DefaultClient {
+		t.Fatal("expected http.DefaultClient")
+	} else if client.APIVersion() != DefaultAPIVersion {
+		t.Fatalf("unexpected api version: %s", client.APIVersion())
+	}
+
+	req, err := client.newRequest("GET", "accounts", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if req.Header.Get("X-Api-Version") != DefaultAPIVersion {
+		t.Fatalf("unexpected X-Api-Version header: %s", req.Header.Get("X-Api-Version"))
+	} else if req.Header.Get("User-Agent") != "" {
+		t.Fatalf("unexpected User-Agent header: %s", req.Header.Get("User-Agent"))
+	}
+
+	hc := &http.Client{}
+	client = NewClient("test", "abc", nil,
+		WithBaseURL("http://localhost:8080"),
+		WithAPIVersion("2.9"),
+		WithHTTPClient(hc),
+		WithUserAgent("recurly-test/1.0"),
+	)
+	if client.BaseURL != "http://localhost:8080/" {
+		t.Fatalf("unexpected base url: %s", client.BaseURL)
+	} else if client.client != hc {
+		t.Fatal("expected http client from WithHTTPClient")
+	}
+
+	req, err = client.newRequest("GET", "accounts", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if req.URL.String() != "http://localhost:8080/v2/accounts" {
+		t.Fatalf("unexpected url: %s", req.URL.String())
+	} else if req.Header.Get("X-Api-Version") != "2.9" {
+		t.Fatalf("unexpected X-Api-Version header: %s", req.Header.Get("X-Api-Version"))
+	} else if req.Header.Get("User-Agent") != "recurly-test/1.0" {
+		t.Fatalf("unexpected User-Agent header: %s", req.Header.Get("User-Agent"))
+	}
+
+	// The timeout must not be set on the shared default client.
+	client = NewClient("test", "abc", nil, WithTimeout(5*time.Second))
+	if client.client == http.DefaultClient {
+		t.Fatal("expected http client to be copied")
+	} else if client.client.Timeout != 5*time.Second {
+		t.Fatalf("unexpected timeout: %s", client.client.Timeout)
+	} else if http.DefaultClient.Timeout != 0 {
+		t.Fatalf("unexpected default client timeout: %s", http.DefaultClient.Timeout)
+	}
+}
+
+// TestClient_APIVersionGating tests that methods depending on a newer api
+// version fail before making a request.
+func TestClient_APIVersionGating(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var requested bool
+	mux.HandleFunc("/v2/credit_payments", func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+		w.WriteHeader(200)
+	})
+
+	client := NewClient("test", "abc", nil, WithBaseURL(server.URL), WithAPIVersion("2.9"))
+	resp, _, err := client.CreditPayments.List(nil)
+	if resp != nil {
+		t.Fatalf("unexpected response: %v", resp)
+	} else if requested {
+		t.Fatal("expected no request to be made")
+	} else if e, ok := err.(ErrUnsupportedAPIVersion); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	} else if e.Required != "2.10" || e.Configured != "2.9" {
+		t.Fatalf("unexpected error: %#v", e)
+	} else if err.Error() != "recurly: requires api version 2.10, client configured with 2.9" {
+		t.Fatalf("unexpected error string: %s", err.Error())
+	}
+
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+		w.WriteHeader(200)
+	})
+	for name, fn := range map[string]func() (*Response, error){
+		"Invoices.VoidCreditInvoice": func() (*Response, error) {
+			resp, _, err := client.Invoices.VoidCreditInvoice(1001)
+			return resp, err
+		},
+		"Purchases.Create": func() (*Response, error) {
+			resp, _, err := client.Purchases.Create(Purchase{})
+			return resp, err
+		},
+		"Purchases.Preview": func() (*Response, error) {
+			resp, _, err := client.Purchases.Preview(Purchase{})
+			return resp, err
+		},
+	} {
+		if resp, err := fn(); resp != nil {
+			t.Fatalf("%s: unexpected response: %v", name, resp)
+		} else if _, ok := err.(ErrUnsupportedAPIVersion); !ok {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		} else if requested {
+			t.Fatalf("%s: expected no request to be made", name)
+		}
+	}
+
+	for _, tt := range []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "2.11", b: "2.11", expected: 0},
+		{a: "2.9", b: "2.10", expected: -1},
+		{a: "2.11", b: "2.10", expected: 1},
+		{a: "2.10", b: "2.10.1", expected: -1},
+		{a: "3", b: "2.29", expected: 1},
+	} {
+		if given := compareAPIVersions(tt.a, tt.b); given != tt.expected {
+			t.Fatalf("compareAPIVersions(%q, %q): expected %d, given %d", tt.a, tt.b, tt.expected, given)
+		}
+	}
+}
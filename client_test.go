@@ -24,8 +24,7 @@ func setup() {
 	mux = http.NewServeMux()
 	server = httptest.NewServer(mux)
 
-	client = NewClient("test", "abc", nil)
-	client.BaseURL = server.URL + "/"
+	client = NewClient("test", "abc", nil, WithBaseURL(server.URL))
 }
 
 func teardown() {
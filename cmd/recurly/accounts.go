@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/kmikiy/recurly"
+)
+
+func init() {
+	register(
+		command{name: "accounts list", args: "[--state STATE]", setup: listAccounts},
+		command{name: "accounts get", args: "CODE", nargs: 1, setup: getAccount},
+	)
+}
+
+func accountsTable(accounts ...recurly.Account) table {
+	t := table{header: []string{"CODE", "STATE", "EMAIL", "NAME", "COMPANY", "CREATED"}}
+	for _, a := range accounts {
+		t.add(a.Code, a.State, a.Email, joinName(a.FirstName, a.LastName), a.CompanyName, a.CreatedAt.String())
+	}
+	return t
+}
+
+func joinName(first, last string) string {
+	if first == "" || last == "" {
+		return first + last
+	}
+	return first + " " + last
+}
+
+func listAccounts(fs *flag.FlagSet) runFunc {
+	state := fs.String("state", "", "only list accounts in `state`: active, closed, subscriber, non_subscriber or past_due")
+	return func(e *env, args []string) error {
+		params := recurly.Params{}
+		filter(params, "state", *state)
+
+		accounts := []recurly.Account{}
+		if err := e.paginate(params, func(p recurly.Params) (*recurly.Response, int, error) {
+			resp, page, err := e.client.Accounts.List(p)
+			accounts = append(accounts, page[:e.remaining(len(page), len(accounts))]...)
+			return resp, len(page), err
+		}); err != nil {
+			return err
+		}
+		return e.print(accounts, accountsTable(accounts...))
+	}
+}
+
+func getAccount(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		resp, a, err := e.client.Accounts.Get(args[0])
+		if err := check(resp, err); err != nil {
+			return err
+		}
+		return e.print(a, accountsTable(*a))
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+// perPage is the page size used when paginating lists.
+const perPage = 200
+
+// errNotFound is returned when a resource does not exist.
+var errNotFound = errors.New("not found")
+
+// apiError is returned when the API responds with an error status.
+type apiError struct {
+	resp *recurly.Response
+}
+
+func (e apiError) Error() string {
+	msg := fmt.Sprintf("%s %s: %s", e.resp.Request.Method, e.resp.Request.URL.Path, e.resp.Status)
+	for _, err := range e.resp.Errors {
+		if err.Field != "" {
+			msg += fmt.Sprintf("\n  %s: %s", err.Field, err.Message)
+		} else {
+			msg += "\n  " + err.Message
+		}
+	}
+	return msg
+}
+
+// check returns the error from a call to the client, or an apiError if the
+// response has an error status.
+func check(resp *recurly.Response, err error) error {
+	if err != nil {
+		return err
+	} else if resp != nil && resp.StatusCode == 404 {
+		return errNotFound
+	} else if resp != nil && resp.IsError() {
+		return apiError{resp: resp}
+	}
+	return nil
+}
+
+// paginate calls fetch with params and a cursor until every page has been
+// fetched or the --limit is reached. fetch returns the number of results in
+// the page. The caller trims the results to the limit.
+func (e *env) paginate(params recurly.Params, fetch func(recurly.Params) (*recurly.Response, int, error)) error {
+	if params == nil {
+		params = recurly.Params{}
+	}
+	params["per_page"] = perPage
+	if e.limit > 0 && e.limit < perPage {
+		params["per_page"] = e.limit
+	}
+
+	for total := 0; ; {
+		resp, n, err := fetch(params)
+		if err := check(resp, err); err != nil {
+			return err
+		}
+
+		total += n
+		next := resp.Next()
+		if next == "" || n == 0 || (e.limit > 0 && total >= e.limit) {
+			return nil
+		}
+		params["cursor"] = next
+	}
+}
+
+// remaining returns how many of n results fit within the --limit.
+func (e *env) remaining(n, have int) int {
+	if e.limit > 0 && have+n > e.limit {
+		return e.limit - have
+	}
+	return n
+}
+
+// print writes v in the --format, using t for table and CSV output.
+func (e *env) print(v interface{}, t table) error {
+	return write(e.stdout, e.format, v, t)
+}
+
+// dryRunf reports a change that was not made because of --dry-run.
+func (e *env) dryRunf(format string, args ...interface{}) {
+	fmt.Fprintf(e.stderr, "dry run: "+format+"; no changes made\n", args...)
+}
+
+// filter adds the flag value to params if it is set.
+func filter(params recurly.Params, key, value string) {
+	if value != "" {
+		params[key] = value
+	}
+}
+
+// parseDate parses a YYYY-MM-DD date, in UTC, or an RFC 3339 time.
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return t, fmt.Errorf("invalid date %q, must be YYYY-MM-DD or RFC 3339", s)
+	}
+	return t, nil
+}
+
+// parseNumber parses an invoice number, which may have a prefix such as
+// "CN1001".
+func parseNumber(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimLeft(s, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid invoice number %q", s)
+	}
+	return n, nil
+}
+
+// itoa formats an int, or an empty string for zero.
+func itoa(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
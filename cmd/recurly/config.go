@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// config holds the credentials used to create the client.
+type config struct {
+	Subdomain string
+	APIKey    string
+	BaseURL   string
+}
+
+// profiles are the sections of a config file, by name. Profiles are named
+// after the subdomain they hold the credentials for, so a profile's
+// subdomain defaults to its name.
+type profiles map[string]config
+
+// parseProfiles reads a config file of the form:
+//
+//	[acme]
+//	api_key = 0123456789abcdef
+//
+//	[acme-sandbox]
+//	api_key = fedcba9876543210
+//	base_url = http://localhost:8080
+//
+// Blank lines and lines starting with # or ; are ignored.
+func parseProfiles(r io.Reader) (profiles, error) {
+	p := profiles{}
+	var section string
+
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if _, ok := p[section]; !ok {
+				p[section] = config{Subdomain: section}
+			}
+			continue
+		}
+
+		i := strings.Index(line, "=")
+		if i < 0 || section == "" {
+			return nil, fmt.Errorf("line %d: expected [profile] or key = value", n)
+		}
+
+		c := p[section]
+		key, value := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		switch key {
+		case "subdomain":
+			c.Subdomain = value
+		case "api_key":
+			c.APIKey = value
+		case "base_url":
+			c.BaseURL = value
+		default:
+			return nil, fmt.Errorf("line %d: unknown key %q", n, key)
+		}
+		p[section] = c
+	}
+	return p, s.Err()
+}
+
+// configPath returns the config file location: $RECURLY_CONFIG, or
+// ~/.recurly/config.
+func configPath(getenv func(string) string) string {
+	if path := getenv("RECURLY_CONFIG"); path != "" {
+		return path
+	}
+	if home := getenv("HOME"); home != "" {
+		return filepath.Join(home, ".recurly", "config")
+	}
+	return ""
+}
+
+// loadConfig resolves credentials. The profile is the --profile flag, the
+// --subdomain flag, $RECURLY_PROFILE, $RECURLY_SUBDOMAIN, or "default", in
+// that order. $RECURLY_SUBDOMAIN, $RECURLY_API_KEY and $RECURLY_BASE_URL
+// override the profile, unless the profile was selected with --profile or
+// --subdomain, in which case the profile must be in the config file with an
+// api_key, so a mistyped name is an error rather than using the
+// environment's credentials, and only $RECURLY_BASE_URL fills in what the
+// profile leaves unset. The --subdomain flag overrides everything. A
+// missing config file is not an error if the environment has the
+// credentials.
+func loadConfig(getenv func(string) string, profile, subdomain string) (config, error) {
+	explicit := profile != "" || subdomain != ""
+	if profile == "" {
+		profile = firstNonEmpty(subdomain, getenv("RECURLY_PROFILE"), getenv("RECURLY_SUBDOMAIN"), "default")
+	}
+
+	var c config
+	var found bool
+	path := configPath(getenv)
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil && !os.IsNotExist(err) {
+			return c, err
+		} else if err == nil {
+			p, err := parseProfiles(f)
+			f.Close()
+			if err != nil {
+				return c, fmt.Errorf("%s: %v", path, err)
+			}
+			c, found = p[profile]
+		}
+	}
+
+	if explicit {
+		if !found {
+			return c, fmt.Errorf("no [%s] profile in %s", profile, path)
+		} else if c.APIKey == "" {
+			return c, fmt.Errorf("no api_key in the [%s] profile in %s", profile, path)
+		}
+		c.Subdomain = firstNonEmpty(subdomain, c.Subdomain)
+		c.BaseURL = firstNonEmpty(c.BaseURL, getenv("RECURLY_BASE_URL"))
+	} else {
+		c.Subdomain = firstNonEmpty(getenv("RECURLY_SUBDOMAIN"), c.Subdomain)
+		c.APIKey = firstNonEmpty(getenv("RECURLY_API_KEY"), c.APIKey)
+		c.BaseURL = firstNonEmpty(getenv("RECURLY_BASE_URL"), c.BaseURL)
+	}
+
+	if c.Subdomain == "" && c.BaseURL == "" {
+		return c, fmt.Errorf("no subdomain: pass --subdomain, set RECURLY_SUBDOMAIN or add a [%s] profile to %s", profile, path)
+	} else if c.APIKey == "" {
+		return c, fmt.Errorf("no API key for %s: set RECURLY_API_KEY or add api_key to the [%s] profile in %s", c.Subdomain, profile, path)
+	}
+	return c, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
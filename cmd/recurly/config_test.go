@@ -0,0 +1,112 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseProfiles(t *testing.T) {
+	p, err := parseProfiles(strings.NewReader(`
+# Production
+[acme]
+api_key = 0123456789abcdef
+
+; Sandbox
+[sandbox]
+subdomain = acme-sandbox
+api_key = fedcba9876543210
+base_url = http://localhost:8080
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(p, profiles{
+		"acme":    {Subdomain: "acme", APIKey: "0123456789abcdef"},
+		"sandbox": {Subdomain: "acme-sandbox", APIKey: "fedcba9876543210", BaseURL: "http://localhost:8080"},
+	}) {
+		t.Fatalf("unexpected profiles: %#v", p)
+	}
+
+	if _, err := parseProfiles(strings.NewReader("api_key = abc")); err == nil || err.Error() != "line 1: expected [profile] or key = value" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := parseProfiles(strings.NewReader("[acme]\npassword = abc")); err == nil || err.Error() != `line 2: unknown key "password"` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recurly-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	if err := ioutil.WriteFile(path, []byte("[default]\nsubdomain = acme\napi_key = abc\n\n[acme-sandbox]\napi_key = def\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{"HOME": dir, "RECURLY_CONFIG": path}
+	getenv := func(key string) string { return env[key] }
+
+	if c, err := loadConfig(getenv, "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if c != (config{Subdomain: "acme", APIKey: "abc"}) {
+		t.Fatalf("unexpected config: %#v", c)
+	}
+
+	// The subdomain selects its profile.
+	if c, err := loadConfig(getenv, "", "acme-sandbox"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if c != (config{Subdomain: "acme-sandbox", APIKey: "def"}) {
+		t.Fatalf("unexpected config: %#v", c)
+	}
+
+	// The environment overrides the profile.
+	env["RECURLY_PROFILE"] = "acme-sandbox"
+	env["RECURLY_API_KEY"] = "ghi"
+	if c, err := loadConfig(getenv, "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if c != (config{Subdomain: "acme-sandbox", APIKey: "ghi"}) {
+		t.Fatalf("unexpected config: %#v", c)
+	}
+
+	// An explicit profile or subdomain takes precedence over the environment.
+	env["RECURLY_SUBDOMAIN"] = "acme"
+	if c, err := loadConfig(getenv, "default", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if c != (config{Subdomain: "acme", APIKey: "abc"}) {
+		t.Fatalf("unexpected config: %#v", c)
+	}
+	if c, err := loadConfig(getenv, "", "acme-sandbox"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if c != (config{Subdomain: "acme-sandbox", APIKey: "def"}) {
+		t.Fatalf("unexpected config: %#v", c)
+	}
+
+	// An explicit profile or subdomain must be in the config file with an
+	// api_key, rather than taking the environment's credentials.
+	if _, err := loadConfig(getenv, "acme-sanbox", ""); err == nil || err.Error() != "no [acme-sanbox] profile in "+path {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := loadConfig(getenv, "", "other"); err == nil || err.Error() != "no [other] profile in "+path {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ioutil.WriteFile(path, []byte("[acme]\nsubdomain = acme\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfig(getenv, "acme", ""); err == nil || err.Error() != "no api_key in the [acme] profile in "+path {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Without a profile, the key must come from the environment.
+	delete(env, "RECURLY_SUBDOMAIN")
+	delete(env, "RECURLY_PROFILE")
+	delete(env, "RECURLY_API_KEY")
+	env["RECURLY_PROFILE"] = "acme"
+	if _, err := loadConfig(getenv, "", ""); err == nil || !strings.HasPrefix(err.Error(), "no API key for acme") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+
+	"github.com/kmikiy/recurly"
+)
+
+func init() {
+	register(
+		command{name: "invoices list", args: "[--state STATE] [--account CODE]", setup: listInvoices},
+		command{name: "invoices get", args: "NUMBER", nargs: 1, setup: getInvoice},
+		command{name: "invoices pdf", args: "NUMBER [-o FILE] [--language LANG]", nargs: 1, setup: invoicePDF},
+		command{name: "invoices create", args: "ACCOUNT", nargs: 1, setup: createInvoice},
+		command{name: "invoices collect", args: "NUMBER", nargs: 1, setup: collectInvoice},
+	)
+}
+
+func invoicesTable(invoices ...recurly.Invoice) table {
+	t := table{header: []string{"NUMBER", "ACCOUNT", "STATE", "TOTAL", "BALANCE", "CREATED"}}
+	for _, i := range invoices {
+		t.add(i.InvoiceNumberPrefix+itoa(i.InvoiceNumber), i.AccountCode, i.State, i.Total().String(), i.Balance().String(), i.CreatedAt.String())
+	}
+	return t
+}
+
+func listInvoices(fs *flag.FlagSet) runFunc {
+	state := fs.String("state", "", "only list invoices in `state`, such as pending, paid or past_due")
+	account := fs.String("account", "", "only list invoices for the account with `code`")
+	return func(e *env, args []string) error {
+		params := recurly.Params{}
+		filter(params, "state", *state)
+
+		invoices := []recurly.Invoice{}
+		if err := e.paginate(params, func(p recurly.Params) (*recurly.Response, int, error) {
+			var resp *recurly.Response
+			var page []recurly.Invoice
+			var err error
+			if *account != "" {
+				resp, page, err = e.client.Invoices.ListAccount(*account, p)
+			} else {
+				resp, page, err = e.client.Invoices.List(p)
+			}
+			invoices = append(invoices, page[:e.remaining(len(page), len(invoices))]...)
+			return resp, len(page), err
+		}); err != nil {
+			return err
+		}
+		return e.print(invoices, invoicesTable(invoices...))
+	}
+}
+
+func getInvoice(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		number, err := parseNumber(args[0])
+		if err != nil {
+			return err
+		}
+
+		resp, i, err := e.client.Invoices.Get(number)
+		if err := check(resp, err); err != nil {
+			return err
+		}
+		return e.print(i, invoicesTable(*i))
+	}
+}
+
+// invoicePDF writes the invoice PDF to the -o file, or to stdout.
+func invoicePDF(fs *flag.FlagSet) runFunc {
+	output := fs.String("o", "-", "write the PDF to `file`, - for stdout")
+	language := fs.String("language", "", "PDF `language`, such as English or German (default English)")
+	return func(e *env, args []string) error {
+		number, err := parseNumber(args[0])
+		if err != nil {
+			return err
+		}
+
+		resp, pdf, err := e.client.Invoices.GetPDF(number, *language)
+		if err := check(resp, err); err != nil {
+			return err
+		}
+		if *output == "-" {
+			_, err = pdf.WriteTo(e.stdout)
+			return err
+		}
+		return ioutil.WriteFile(*output, pdf.Bytes(), 0644)
+	}
+}
+
+// createInvoice invoices an account's pending charges. With --dry-run it
+// returns the invoice preview.
+func createInvoice(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		var resp *recurly.Response
+		var i *recurly.Invoice
+		var err error
+		if e.dryRun {
+			resp, i, err = e.client.Invoices.Preview(args[0])
+		} else {
+			resp, i, err = e.client.Invoices.Create(args[0], recurly.Invoice{})
+		}
+		if err := check(resp, err); err != nil {
+			return err
+		}
+		return e.print(i, invoicesTable(*i))
+	}
+}
+
+// collectInvoice retries collection of a past due invoice. Recurly has no
+// preview for this, so with --dry-run the invoice is returned unchanged.
+func collectInvoice(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		number, err := parseNumber(args[0])
+		if err != nil {
+			return err
+		}
+
+		var resp *recurly.Response
+		var i *recurly.Invoice
+		if e.dryRun {
+			resp, i, err = e.client.Invoices.Get(number)
+		} else {
+			resp, i, err = e.client.Invoices.Collect(number)
+		}
+		if err := check(resp, err); err != nil {
+			return err
+		}
+		if e.dryRun {
+			e.dryRunf("would collect invoice %s", args[0])
+		}
+		return e.print(i, invoicesTable(*i))
+	}
+}
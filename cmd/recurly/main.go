@@ -0,0 +1,185 @@
+// Command recurly is a command-line interface to the Recurly API for
+// operations work.
+//
+// Usage:
+//
+//	recurly RESOURCE ACTION [ARGS] [FLAGS]
+//
+// For example:
+//
+//	recurly accounts get CODE
+//	recurly subscriptions list --state past_due
+//	recurly invoices pdf 1001 -o 1001.pdf
+//	recurly subscriptions postpone UUID --date 2018-06-01
+//
+// Run "recurly help" for the full list of commands.
+//
+// Credentials are read from the RECURLY_SUBDOMAIN and RECURLY_API_KEY
+// environment variables, or from a profile per subdomain in
+// ~/.recurly/config (or $RECURLY_CONFIG):
+//
+//	[acme]
+//	api_key = 0123456789abcdef
+//
+// Select a profile with --profile or --subdomain. Every command accepts:
+//
+//	--format table|json|csv  output format (default table)
+//	--limit N                stop listing after N results (default all)
+//	--dry-run                preview changes without making them
+//
+// Lists are paginated automatically. With --dry-run, commands backed by a
+// Recurly preview endpoint return the preview; other changes are checked
+// against the current state and reported without being made.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kmikiy/recurly"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr, os.Getenv))
+}
+
+// env is the state shared by commands.
+type env struct {
+	client *recurly.Client
+	stdout io.Writer
+	stderr io.Writer
+	format string
+	limit  int
+	dryRun bool
+}
+
+// runFunc runs a command with its positional arguments.
+type runFunc func(e *env, args []string) error
+
+// command is a RESOURCE ACTION pair.
+type command struct {
+	name  string // e.g. "subscriptions postpone"
+	args  string // usage of positional arguments and flags
+	nargs int    // number of required positional arguments
+
+	// setup registers the command's flags and returns the function that
+	// runs it.
+	setup func(fs *flag.FlagSet) runFunc
+}
+
+// commands are all the commands, by name. They are registered by the
+// resource files.
+var commands = map[string]command{}
+
+func register(cmds ...command) {
+	for _, c := range cmds {
+		commands[c.name] = c
+	}
+}
+
+// run runs the command line args and returns the exit status.
+func run(args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	if len(args) < 2 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stderr)
+		if len(args) > 0 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+			return 0
+		}
+		return 2
+	}
+
+	name := args[0] + " " + args[1]
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "recurly: unknown command %q\n\n", name)
+		usage(stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("recurly "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: recurly %s %s\n", name, cmd.args)
+		fs.PrintDefaults()
+	}
+	profile := fs.String("profile", "", "config `profile` to use")
+	subdomain := fs.String("subdomain", "", "Recurly `subdomain`, and the profile to use")
+	e := &env{stdout: stdout, stderr: stderr}
+	fs.StringVar(&e.format, "format", formatTable, "output `format`: table, json or csv")
+	fs.IntVar(&e.limit, "limit", 0, "stop listing after `n` results, 0 for all")
+	fs.BoolVar(&e.dryRun, "dry-run", false, "preview changes without making them")
+	fn := cmd.setup(fs)
+
+	positional, err := parse(fs, args[2:])
+	if err == flag.ErrHelp {
+		return 0
+	} else if err != nil {
+		return 2
+	} else if len(positional) != cmd.nargs {
+		fs.Usage()
+		return 2
+	} else if !validFormat(e.format) {
+		fmt.Fprintf(stderr, "recurly: unknown format %q, must be table, json or csv\n", e.format)
+		return 2
+	}
+
+	cfg, err := loadConfig(getenv, *profile, *subdomain)
+	if err != nil {
+		fmt.Fprintf(stderr, "recurly: %v\n", err)
+		return 1
+	}
+	var opts []recurly.Option
+	if cfg.BaseURL != "" {
+		opts = append(opts, recurly.WithBaseURL(cfg.BaseURL))
+	}
+	opts = append(opts, recurly.WithUserAgent("recurly-cli"))
+	e.client = recurly.NewClient(cfg.Subdomain, cfg.APIKey, nil, opts...)
+
+	if err := fn(e, positional); err != nil {
+		fmt.Fprintf(stderr, "recurly: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parse parses flags that may appear before, between or after positional
+// arguments, as in "invoices pdf 1001 -o 1001.pdf", and returns the
+// positional arguments. Arguments after "--" are all positional.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		} else if len(rest) < len(args) && args[len(args)-len(rest)-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "usage: recurly RESOURCE ACTION [ARGS] [FLAGS]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\n", strings.TrimSpace(name+" "+commands[name].args))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Every command accepts --profile, --subdomain, --format table|json|csv,")
+	fmt.Fprintln(w, "--limit and --dry-run. Run a command with -h for its flags.")
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// cli runs the command line against a test server.
+type cli struct {
+	mux    *http.ServeMux
+	server *httptest.Server
+	env    map[string]string
+}
+
+func newCLI() *cli {
+	c := &cli{mux: http.NewServeMux()}
+	c.server = httptest.NewServer(c.mux)
+	c.env = map[string]string{
+		"RECURLY_SUBDOMAIN": "test",
+		"RECURLY_API_KEY":   "abc",
+		"RECURLY_BASE_URL":  c.server.URL,
+		"RECURLY_CONFIG":    filepath.Join(os.TempDir(), "recurly-cli-test-missing"),
+	}
+	return c
+}
+
+func (c *cli) run(args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = run(args, &out, &errOut, func(key string) string { return c.env[key] })
+	return code, out.String(), errOut.String()
+}
+
+func subscriptionXML(uuid, state string) string {
+	return fmt.Sprintf(`<subscription href="https://test.recurly.com/v2/subscriptions/%s">
+		<account href="https://test.recurly.com/v2/accounts/1"/>
+		<plan><plan_code>gold</plan_code><name>Gold plan</name></plan>
+		<uuid>%s</uuid>
+		<state>%s</state>
+		<currency>USD</currency>
+		<quantity type="integer">1</quantity>
+		<total_amount_in_cents type="integer">1000</total_amount_in_cents>
+		<current_period_ends_at type="datetime">2018-02-01T00:00:00Z</current_period_ends_at>
+	</subscription>`, uuid, uuid, state)
+}
+
+func TestRun_ListPaginates(t *testing.T) {
+	c := newCLI()
+	defer c.server.Close()
+
+	var pages int
+	var perPage string
+	c.mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		perPage = r.URL.Query().Get("per_page")
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if r.URL.Query().Get("state") != "past_due" {
+			t.Fatalf("unexpected query: %s", r.URL.RawQuery)
+		}
+
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Header().Set("Link", `<https://test.recurly.com/v2/subscriptions?cursor=1234&state=past_due>; rel="next"`)
+			w.WriteHeader(200)
+			fmt.Fprintf(w, `<subscriptions>%s</subscriptions>`, subscriptionXML("a", "active"))
+		case "1234":
+			w.WriteHeader(200)
+			fmt.Fprintf(w, `<subscriptions>%s</subscriptions>`, subscriptionXML("b", "canceled"))
+		default:
+			t.Fatalf("unexpected cursor: %s", r.URL.RawQuery)
+		}
+	})
+
+	code, stdout, stderr := c.run("subscriptions", "list", "--state", "past_due", "--format", "csv")
+	if code != 0 {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	} else if pages != 2 || perPage != "200" {
+		t.Fatalf("unexpected pages: %d of %s", pages, perPage)
+	} else if stdout != "UUID,ACCOUNT,PLAN,STATE,QUANTITY,AMOUNT,PERIOD ENDS\n"+
+		"a,1,gold,active,1,10.00 USD,2018-02-01T00:00:00Z\n"+
+		"b,1,gold,canceled,1,10.00 USD,2018-02-01T00:00:00Z\n" {
+		t.Fatalf("unexpected output: %s", stdout)
+	}
+
+	pages = 0
+	if code, stdout, stderr := c.run("subscriptions", "list", "--state", "past_due", "--limit", "1"); code != 0 {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	} else if pages != 1 || perPage != "1" || strings.Count(stdout, "\n") != 2 || !strings.HasPrefix(stdout, "UUID  ") {
+		t.Fatalf("unexpected output: %d pages, %s", pages, stdout)
+	}
+}
+
+func TestRun_GetJSON(t *testing.T) {
+	c := newCLI()
+	defer c.server.Close()
+
+	c.mux.HandleFunc("/v2/accounts/1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<account><account_code>1</account_code><state>active</state><email>verena@example.com</email></account>`)
+	})
+
+	if code, stdout, stderr := c.run("accounts", "get", "1", "--format", "json"); code != 0 {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	} else if !strings.Contains(stdout, `"account_code": "1"`) || !strings.Contains(stdout, `"email": "verena@example.com"`) {
+		t.Fatalf("unexpected output: %s", stdout)
+	}
+
+	if code, _, stderr := c.run("accounts", "get", "2"); code != 1 {
+		t.Fatalf("unexpected exit code: %d", code)
+	} else if stderr != "recurly: not found\n" {
+		t.Fatalf("unexpected error: %s", stderr)
+	}
+}
+
+func TestRun_InvoicePDF(t *testing.T) {
+	c := newCLI()
+	defer c.server.Close()
+
+	c.mux.HandleFunc("/v2/invoices/1001", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/pdf" || r.Header.Get("Accept-Language") != "German" {
+			t.Fatalf("unexpected headers: %v", r.Header)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, "%PDF-1.4")
+	})
+
+	dir, err := ioutil.TempDir("", "recurly-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "1001.pdf")
+
+	if code, _, stderr := c.run("invoices", "pdf", "1001", "-o", path, "--language", "German"); code != 0 {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	} else if b, err := ioutil.ReadFile(path); err != nil {
+		t.Fatal(err)
+	} else if string(b) != "%PDF-1.4" {
+		t.Fatalf("unexpected pdf: %q", b)
+	}
+}
+
+func TestRun_Postpone(t *testing.T) {
+	c := newCLI()
+	defer c.server.Close()
+
+	var puts int
+	c.mux.HandleFunc("/v2/subscriptions/abc", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, subscriptionXML("abc", "active"))
+	})
+	c.mux.HandleFunc("/v2/subscriptions/abc/postpone", func(w http.ResponseWriter, r *http.Request) {
+		puts++
+		if r.Method != "PUT" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if r.URL.Query().Get("next_renewal_date") != "2018-06-01T00:00:00Z" || r.URL.Query().Get("bulk") != "false" {
+			t.Fatalf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, subscriptionXML("abc", "active"))
+	})
+
+	if code, _, stderr := c.run("subscriptions", "postpone", "abc", "--date", "2018-06-01", "--dry-run"); code != 0 {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	} else if puts != 0 {
+		t.Fatal("expected dry run to make no changes")
+	} else if stderr != "dry run: would postpone subscription abc; no changes made\n" {
+		t.Fatalf("unexpected stderr: %s", stderr)
+	}
+
+	if code, _, stderr := c.run("subscriptions", "resume", "abc", "--dry-run"); code != 1 {
+		t.Fatalf("unexpected exit code: %d", code)
+	} else if !strings.Contains(stderr, `cannot resume subscription abc in state "active"`) {
+		t.Fatalf("unexpected stderr: %s", stderr)
+	}
+
+	if code, _, stderr := c.run("subscriptions", "postpone", "abc", "--date", "2018-06-01"); code != 0 {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	} else if puts != 1 {
+		t.Fatalf("unexpected requests: %d", puts)
+	}
+}
+
+func TestRun_DryRunPreview(t *testing.T) {
+	c := newCLI()
+	defer c.server.Close()
+
+	c.mux.HandleFunc("/v2/subscriptions/abc", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected %s request", r.Method)
+	})
+	c.mux.HandleFunc("/v2/subscriptions/abc/preview", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if b, _ := ioutil.ReadAll(r.Body); !bytes.Contains(b, []byte("<plan_code>silver</plan_code>")) {
+			t.Fatalf("unexpected body: %s", b)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, subscriptionXML("abc", "active"))
+	})
+
+	if code, stdout, stderr := c.run("subscriptions", "update", "abc", "--plan", "silver", "--dry-run"); code != 0 {
+		t.Fatalf("unexpected exit code %d: %s", code, stderr)
+	} else if !strings.Contains(stdout, "abc") {
+		t.Fatalf("unexpected output: %s", stdout)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	c := newCLI()
+	defer c.server.Close()
+
+	c.mux.HandleFunc("/v2/subscriptions/abc/pause", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(422)
+		fmt.Fprint(w, `<errors><error field="subscription.remaining_pause_cycles" symbol="invalid">is invalid</error></errors>`)
+	})
+
+	if code, _, stderr := c.run("subscriptions", "pause", "abc", "--cycles", "2"); code != 1 {
+		t.Fatalf("unexpected exit code: %d", code)
+	} else if stderr != "recurly: PUT /v2/subscriptions/abc/pause: 422 Unprocessable Entity\n  subscription.remaining_pause_cycles: is invalid\n" {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+
+	if code, _, stderr := c.run("subscriptions", "frobnicate", "abc"); code != 2 || !strings.Contains(stderr, `unknown command "subscriptions frobnicate"`) {
+		t.Fatalf("unexpected result: %d %s", code, stderr)
+	} else if code, _, _ := c.run("subscriptions", "get"); code != 2 {
+		t.Fatalf("unexpected exit code: %d", code)
+	} else if code, _, stderr := c.run("subscriptions", "get", "abc", "--format", "xml"); code != 2 || !strings.Contains(stderr, `unknown format "xml"`) {
+		t.Fatalf("unexpected result: %d %s", code, stderr)
+	} else if code, _, stderr := c.run("help"); code != 0 || !strings.Contains(stderr, "subscriptions postpone UUID --date YYYY-MM-DD [--bulk]") {
+		t.Fatalf("unexpected result: %d %s", code, stderr)
+	}
+
+	delete(c.env, "RECURLY_API_KEY")
+	if code, _, stderr := c.run("subscriptions", "get", "abc"); code != 1 || !strings.Contains(stderr, "no API key for test") {
+		t.Fatalf("unexpected result: %d %s", code, stderr)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// table is the tabular view of a result, used by the table and CSV formats.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// add appends a row.
+func (t *table) add(values ...string) {
+	t.rows = append(t.rows, values)
+}
+
+// write writes v as JSON, or t as a table or CSV.
+func write(w io.Writer, format string, v interface{}, t table) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write(t.header)
+		cw.WriteAll(t.rows)
+		return cw.Error()
+	case formatTable:
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unknown format %q, must be table, json or csv", format)
+}
+
+// validFormat returns true if format is a supported output format.
+func validFormat(format string) bool {
+	return format == formatTable || format == formatJSON || format == formatCSV
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"strconv"
+
+	"github.com/kmikiy/recurly"
+)
+
+func init() {
+	register(
+		command{name: "plans list", setup: listPlans},
+		command{name: "plans get", args: "CODE", nargs: 1, setup: getPlan},
+		command{name: "coupons list", args: "[--state STATE]", setup: listCoupons},
+		command{name: "coupons get", args: "CODE", nargs: 1, setup: getCoupon},
+	)
+}
+
+func plansTable(plans ...recurly.Plan) table {
+	t := table{header: []string{"CODE", "NAME", "INTERVAL", "USD", "EUR", "CREATED"}}
+	for _, p := range plans {
+		interval := strconv.Itoa(p.IntervalLength) + " " + p.IntervalUnit
+		usd, eur := recurly.NewMoney(p.UnitAmountInCents.USD, "USD"), recurly.NewMoney(p.UnitAmountInCents.EUR, "EUR")
+		t.add(p.Code, p.Name, interval, usd.Decimal(), eur.Decimal(), p.CreatedAt.String())
+	}
+	return t
+}
+
+func listPlans(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		plans := []recurly.Plan{}
+		if err := e.paginate(nil, func(p recurly.Params) (*recurly.Response, int, error) {
+			resp, page, err := e.client.Plans.List(p)
+			plans = append(plans, page[:e.remaining(len(page), len(plans))]...)
+			return resp, len(page), err
+		}); err != nil {
+			return err
+		}
+		return e.print(plans, plansTable(plans...))
+	}
+}
+
+func getPlan(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		resp, p, err := e.client.Plans.Get(args[0])
+		if err := check(resp, err); err != nil {
+			return err
+		}
+		return e.print(p, plansTable(*p))
+	}
+}
+
+func couponsTable(coupons ...recurly.Coupon) table {
+	t := table{header: []string{"CODE", "NAME", "STATE", "DISCOUNT", "DURATION", "CREATED"}}
+	for _, c := range coupons {
+		discount := strconv.Itoa(c.DiscountPercent) + "%"
+		if c.DiscountType != "percent" && c.DiscountInCents != nil {
+			discount = recurly.NewMoney(c.DiscountInCents.USD, "USD").String()
+			if c.DiscountInCents.EUR != 0 {
+				discount += " " + recurly.NewMoney(c.DiscountInCents.EUR, "EUR").String()
+			}
+		}
+		t.add(c.Code, c.Name, c.State, discount, c.Duration, c.CreatedAt.String())
+	}
+	return t
+}
+
+func listCoupons(fs *flag.FlagSet) runFunc {
+	state := fs.String("state", "", "only list coupons in `state`: redeemable, expired or maxed_out")
+	return func(e *env, args []string) error {
+		params := recurly.Params{}
+		filter(params, "state", *state)
+
+		coupons := []recurly.Coupon{}
+		if err := e.paginate(params, func(p recurly.Params) (*recurly.Response, int, error) {
+			resp, page, err := e.client.Coupons.List(p)
+			coupons = append(coupons, page[:e.remaining(len(page), len(coupons))]...)
+			return resp, len(page), err
+		}); err != nil {
+			return err
+		}
+		return e.print(coupons, couponsTable(coupons...))
+	}
+}
+
+func getCoupon(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		resp, c, err := e.client.Coupons.Get(args[0])
+		if err := check(resp, err); err != nil {
+			return err
+		}
+		return e.print(c, couponsTable(*c))
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/kmikiy/recurly"
+)
+
+func init() {
+	register(
+		command{name: "subscriptions list", args: "[--state STATE] [--account CODE]", setup: listSubscriptions},
+		command{name: "subscriptions get", args: "UUID", nargs: 1, setup: getSubscription},
+		command{name: "subscriptions create", args: "--account CODE --plan CODE --currency CURRENCY [--quantity N]", setup: createSubscription},
+		command{name: "subscriptions update", args: "UUID [--plan CODE] [--quantity N] [--unit-amount CENTS] [--timeframe now|renewal]", nargs: 1, setup: updateSubscription},
+		command{name: "subscriptions cancel", args: "UUID", nargs: 1, setup: changeSubscription(recurly.SubscriptionActionCancel)},
+		command{name: "subscriptions reactivate", args: "UUID", nargs: 1, setup: changeSubscription(recurly.SubscriptionActionReactivate)},
+		command{name: "subscriptions terminate", args: "UUID [--refund none|partial|full]", nargs: 1, setup: changeSubscription(recurly.SubscriptionActionTerminate)},
+		command{name: "subscriptions postpone", args: "UUID --date YYYY-MM-DD [--bulk]", nargs: 1, setup: changeSubscription(recurly.SubscriptionActionPostpone)},
+		command{name: "subscriptions pause", args: "UUID --cycles N", nargs: 1, setup: changeSubscription(recurly.SubscriptionActionPause)},
+		command{name: "subscriptions resume", args: "UUID", nargs: 1, setup: changeSubscription(recurly.SubscriptionActionResume)},
+	)
+}
+
+func subscriptionsTable(subs ...recurly.Subscription) table {
+	t := table{header: []string{"UUID", "ACCOUNT", "PLAN", "STATE", "QUANTITY", "AMOUNT", "PERIOD ENDS"}}
+	for _, s := range subs {
+		t.add(s.UUID, s.AccountCode, s.Plan.Code, s.State, itoa(s.Quantity), s.TotalAmount().String(), s.CurrentPeriodEndsAt.String())
+	}
+	return t
+}
+
+func listSubscriptions(fs *flag.FlagSet) runFunc {
+	state := fs.String("state", "", "only list subscriptions in `state`, such as active, canceled or past_due")
+	account := fs.String("account", "", "only list subscriptions for the account with `code`")
+	return func(e *env, args []string) error {
+		params := recurly.Params{}
+		filter(params, "state", *state)
+
+		subs := []recurly.Subscription{}
+		if err := e.paginate(params, func(p recurly.Params) (*recurly.Response, int, error) {
+			var resp *recurly.Response
+			var page []recurly.Subscription
+			var err error
+			if *account != "" {
+				resp, page, err = e.client.Subscriptions.ListAccount(*account, p)
+			} else {
+				resp, page, err = e.client.Subscriptions.List(p)
+			}
+			subs = append(subs, page[:e.remaining(len(page), len(subs))]...)
+			return resp, len(page), err
+		}); err != nil {
+			return err
+		}
+		return e.print(subs, subscriptionsTable(subs...))
+	}
+}
+
+func getSubscription(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		resp, s, err := e.client.Subscriptions.Get(args[0])
+		if err := check(resp, err); err != nil {
+			return err
+		}
+		return e.print(s, subscriptionsTable(*s))
+	}
+}
+
+// createSubscription creates a subscription for an existing account. With
+// --dry-run it returns the subscription preview.
+func createSubscription(fs *flag.FlagSet) runFunc {
+	account := fs.String("account", "", "`code` of the account to subscribe")
+	plan := fs.String("plan", "", "`code` of the plan")
+	currency := fs.String("currency", "", "`currency` of the subscription, such as USD")
+	quantity := fs.Int("quantity", 0, "`n` units of the plan")
+	return func(e *env, args []string) error {
+		if *account == "" || *plan == "" || *currency == "" {
+			return fmt.Errorf("--account, --plan and --currency are required")
+		}
+
+		sub := recurly.NewSubscription{
+			PlanCode: *plan,
+			Account:  recurly.Account{Code: *account},
+			Currency: *currency,
+			Quantity: *quantity,
+		}
+		if e.dryRun {
+			resp, s, err := e.client.Subscriptions.Preview(sub)
+			if err := check(resp, err); err != nil {
+				return err
+			}
+			return e.print(s, subscriptionsTable(*s))
+		}
+
+		resp, created, err := e.client.Subscriptions.Create(sub)
+		if err := check(resp, err); err != nil {
+			return err
+		}
+		return e.print(created.Subscription, subscriptionsTable(*created.Subscription))
+	}
+}
+
+// updateSubscription changes a subscription's plan, quantity or price. With
+// --dry-run it returns the change preview.
+func updateSubscription(fs *flag.FlagSet) runFunc {
+	plan := fs.String("plan", "", "`code` of the new plan")
+	quantity := fs.Int("quantity", 0, "new quantity `n`")
+	unitAmount := fs.Int("unit-amount", 0, "new unit amount in `cents`")
+	timeframe := fs.String("timeframe", recurly.TimeframeNow, "when the change applies: `now` or renewal")
+	return func(e *env, args []string) error {
+		update := recurly.UpdateSubscription{
+			Timeframe:         *timeframe,
+			PlanCode:          *plan,
+			Quantity:          *quantity,
+			UnitAmountInCents: *unitAmount,
+		}
+
+		var resp *recurly.Response
+		var s *recurly.Subscription
+		var err error
+		if e.dryRun {
+			resp, s, err = e.client.Subscriptions.PreviewChange(args[0], update)
+		} else {
+			resp, s, err = e.client.Subscriptions.Update(args[0], update)
+		}
+		if err := check(resp, err); err != nil {
+			return err
+		}
+		return e.print(s, subscriptionsTable(*s))
+	}
+}
+
+// changeSubscription returns the setup for a state change. Recurly has no
+// preview for these, so with --dry-run the subscription is fetched, checked
+// against the transition and returned unchanged.
+func changeSubscription(action recurly.SubscriptionAction) func(fs *flag.FlagSet) runFunc {
+	return func(fs *flag.FlagSet) runFunc {
+		var refund, date *string
+		var bulk *bool
+		var cycles *int
+		switch action {
+		case recurly.SubscriptionActionTerminate:
+			refund = fs.String("refund", "none", "refund the last charge: `none`, partial or full")
+		case recurly.SubscriptionActionPostpone:
+			date = fs.String("date", "", "next renewal `date`, YYYY-MM-DD or RFC 3339")
+			bulk = fs.Bool("bulk", false, "skip the renewal date confirmation email")
+		case recurly.SubscriptionActionPause:
+			cycles = fs.Int("cycles", 0, "pause for `n` billing cycles")
+		}
+
+		return func(e *env, args []string) error {
+			uuid := args[0]
+
+			subs := e.client.Subscriptions
+			var do func(uuid string) (*recurly.Response, *recurly.Subscription, error)
+			switch action {
+			case recurly.SubscriptionActionCancel:
+				do = subs.Cancel
+			case recurly.SubscriptionActionReactivate:
+				do = subs.Reactivate
+			case recurly.SubscriptionActionResume:
+				do = subs.Resume
+			case recurly.SubscriptionActionTerminate:
+				switch *refund {
+				case "none":
+					do = subs.TerminateWithoutRefund
+				case "partial":
+					do = subs.TerminateWithPartialRefund
+				case "full":
+					do = subs.TerminateWithFullRefund
+				default:
+					return fmt.Errorf("invalid refund %q, must be none, partial or full", *refund)
+				}
+			case recurly.SubscriptionActionPostpone:
+				if *date == "" {
+					return fmt.Errorf("--date is required")
+				}
+				dt, err := parseDate(*date)
+				if err != nil {
+					return err
+				}
+				do = func(uuid string) (*recurly.Response, *recurly.Subscription, error) {
+					return subs.Postpone(uuid, dt, *bulk)
+				}
+			case recurly.SubscriptionActionPause:
+				if *cycles <= 0 {
+					return fmt.Errorf("--cycles must be at least 1")
+				}
+				do = func(uuid string) (*recurly.Response, *recurly.Subscription, error) {
+					return subs.Pause(uuid, *cycles)
+				}
+			}
+
+			if e.dryRun {
+				resp, s, err := subs.Get(uuid)
+				if err := check(resp, err); err != nil {
+					return err
+				} else if err := s.CheckTransition(action); err != nil {
+					return err
+				}
+				e.dryRunf("would %s subscription %s", action, uuid)
+				return e.print(s, subscriptionsTable(*s))
+			}
+
+			resp, s, err := do(uuid)
+			if err := check(resp, err); err != nil {
+				return err
+			}
+			return e.print(s, subscriptionsTable(*s))
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/kmikiy/recurly"
+)
+
+func init() {
+	register(
+		command{name: "transactions list", args: "[--state STATE] [--type TYPE] [--account CODE]", setup: listTransactions},
+		command{name: "transactions get", args: "UUID", nargs: 1, setup: getTransaction},
+	)
+}
+
+func transactionsTable(transactions ...recurly.Transaction) table {
+	t := table{header: []string{"UUID", "ACCOUNT", "ACTION", "STATUS", "AMOUNT", "CREATED"}}
+	for _, tx := range transactions {
+		t.add(tx.UUID, tx.Account.Code, tx.Action, tx.Status, tx.Amount().String(), tx.CreatedAt.String())
+	}
+	return t
+}
+
+func listTransactions(fs *flag.FlagSet) runFunc {
+	state := fs.String("state", "", "only list transactions in `state`: successful, failed or voided")
+	typ := fs.String("type", "", "only list transactions of `type`: authorization, refund or purchase")
+	account := fs.String("account", "", "only list transactions for the account with `code`")
+	return func(e *env, args []string) error {
+		params := recurly.Params{}
+		filter(params, "state", *state)
+		filter(params, "type", *typ)
+
+		transactions := []recurly.Transaction{}
+		if err := e.paginate(params, func(p recurly.Params) (*recurly.Response, int, error) {
+			var resp *recurly.Response
+			var page []recurly.Transaction
+			var err error
+			if *account != "" {
+				resp, page, err = e.client.Transactions.ListAccount(*account, p)
+			} else {
+				resp, page, err = e.client.Transactions.List(p)
+			}
+			transactions = append(transactions, page[:e.remaining(len(page), len(transactions))]...)
+			return resp, len(page), err
+		}); err != nil {
+			return err
+		}
+		return e.print(transactions, transactionsTable(transactions...))
+	}
+}
+
+func getTransaction(fs *flag.FlagSet) runFunc {
+	return func(e *env, args []string) error {
+		resp, tx, err := e.client.Transactions.Get(args[0])
+		if err := check(resp, err); err != nil {
+			return err
+		}
+		return e.print(tx, transactionsTable(*tx))
+	}
+}
@@ -5,30 +5,30 @@ import "encoding/xml"
 // Coupon represents an individual coupon on your site.
 
 type Coupon struct {
-	XMLName                  xml.Name    `xml:"coupon"`
-	Code                     string      `xml:"coupon_code"`
-	Name                     string      `xml:"name"`
-	Description              string      `xml:"description,omitempty"`
-	DiscountType             string      `xml:"discount_type"`
-	DiscountInCents          *UnitAmount `xml:"discount_in_cents,omitempty"`
-	DiscountPercent          int         `xml:"discount_percent,omitempty"`
-	InvoiceDescription       string      `xml:"invoice_description,omitempty"`
-	RedeemByDate             NullTime    `xml:"redeem_by_date,omitempty"`
-	MaxRedemptions           NullInt     `xml:"max_redemptions,omitempty"`
-	AppliesToAllPlans        NullBool    `xml:"applies_to_all_plans,omitempty"`
-	Duration                 string      `xml:"duration,omitempty"`
-	TemporalUnit             string      `xml:"temporal_unit,omitempty"`
-	TemporalAmount           NullInt     `xml:"temporal_amount,omitempty"`
-	AppliesToNonPlanCharges  NullBool    `xml:"applies_to_non_plan_charges,omitempty"`
-	RedemptionResource       string      `xml:"redemption_resource,omitempty"`
-	MaxRedemptionsPerAccount NullInt     `xml:"max_redemptions_per_account,omitempty"`
-	CouponType               string      `xml:"coupon_type,omitempty"`
-	UniqueCodeTemplate       string      `xml:"unique_code_template,omitempty"`
-	PlanCodes                []string    `xml:"plan_codes>plan_code,omitempty"`
-	FreeTrialAmount          NullInt     `xml:"free_trial_amount,omitempty"`
-	FreeTrialUnit            string      `xml:"free_trial_unit,omitempty"`
-	CreatedAt                NullTime    `xml:"created_at,omitempty"`
-	State                    string      `xml:"state,omitempty"`
+	XMLName                  xml.Name    `xml:"coupon" json:"-"`
+	Code                     string      `xml:"coupon_code" json:"coupon_code,omitempty"`
+	Name                     string      `xml:"name" json:"name,omitempty"`
+	Description              string      `xml:"description,omitempty" json:"description,omitempty"`
+	DiscountType             string      `xml:"discount_type" json:"discount_type,omitempty"`
+	DiscountInCents          *UnitAmount `xml:"discount_in_cents,omitempty" json:"discount_in_cents,omitempty"`
+	DiscountPercent          int         `xml:"discount_percent,omitempty" json:"discount_percent,omitempty"`
+	InvoiceDescription       string      `xml:"invoice_description,omitempty" json:"invoice_description,omitempty"`
+	RedeemByDate             NullTime    `xml:"redeem_by_date,omitempty" json:"redeem_by_date"`
+	MaxRedemptions           NullInt     `xml:"max_redemptions,omitempty" json:"max_redemptions"`
+	AppliesToAllPlans        NullBool    `xml:"applies_to_all_plans,omitempty" json:"applies_to_all_plans"`
+	Duration                 string      `xml:"duration,omitempty" json:"duration,omitempty"`
+	TemporalUnit             string      `xml:"temporal_unit,omitempty" json:"temporal_unit,omitempty"`
+	TemporalAmount           NullInt     `xml:"temporal_amount,omitempty" json:"temporal_amount"`
+	AppliesToNonPlanCharges  NullBool    `xml:"applies_to_non_plan_charges,omitempty" json:"applies_to_non_plan_charges"`
+	RedemptionResource       string      `xml:"redemption_resource,omitempty" json:"redemption_resource,omitempty"`
+	MaxRedemptionsPerAccount NullInt     `xml:"max_redemptions_per_account,omitempty" json:"max_redemptions_per_account"`
+	CouponType               string      `xml:"coupon_type,omitempty" json:"coupon_type,omitempty"`
+	UniqueCodeTemplate       string      `xml:"unique_code_template,omitempty" json:"unique_code_template,omitempty"`
+	PlanCodes                []string    `xml:"plan_codes>plan_code,omitempty" json:"plan_codes,omitempty"`
+	FreeTrialAmount          NullInt     `xml:"free_trial_amount,omitempty" json:"free_trial_amount"`
+	FreeTrialUnit            string      `xml:"free_trial_unit,omitempty" json:"free_trial_unit,omitempty"`
+	CreatedAt                NullTime    `xml:"created_at,omitempty" json:"created_at"`
+	State                    string      `xml:"state,omitempty" json:"state,omitempty"`
 
 	// Deprecated: SingleUse          NullBool          `xml:"single_use,omitempty"`
 	// Deprecated: AppliesForMonths   NullInt           `xml:"applies_for_months,omitempty"`
@@ -15,34 +15,25 @@ const (
 // This is a read-only object.
 // Unmarshaling an invoice is handled by the custom UnmarshalXML function.
 type CreditPayment struct {
-	XMLName                   xml.Name `xml:"credit_payment"`
-	AccountCode               string   `xml:"-"`
-	UUID                      string   `xml:"uuid"`
-	Action                    string   `xml:"action"`
-	Currency                  string   `xml:"currency"`
-	AmountInCents             int      `xml:"amount_in_cents"`
-	OriginalInvoiceNumber     int      `xml:"-"`
-	AppliedToInvoice          int      `xml:"-"`
-	OriginalCreditPaymentUUID string   `xml:"-"`
-	RefundTransactionUUID     string   `xml:"-"`
-	CreatedAt                 NullTime `xml:"created_at"`
-	UpdatedAt                 NullTime `xml:"updated_at,omitempty"`
-	VoidedAt                  NullTime `xml:"voided_at,omitempty"`
+	XMLName                   xml.Name `xml:"credit_payment" json:"-"`
+	AccountCode               string   `xml:"-" json:"account_code,omitempty"`
+	UUID                      string   `xml:"uuid" json:"uuid,omitempty"`
+	Action                    string   `xml:"action" json:"action,omitempty"`
+	Currency                  string   `xml:"currency" json:"currency,omitempty"`
+	AmountInCents             int      `xml:"amount_in_cents" json:"amount_in_cents,omitempty"`
+	OriginalInvoiceNumber     int      `xml:"-" json:"original_invoice_number,omitempty"`
+	AppliedToInvoice          int      `xml:"-" json:"applied_to_invoice,omitempty"`
+	OriginalCreditPaymentUUID string   `xml:"-" json:"original_credit_payment_uuid,omitempty"`
+	RefundTransactionUUID     string   `xml:"-" json:"refund_transaction_uuid,omitempty"`
+	CreatedAt                 NullTime `xml:"created_at" json:"created_at"`
+	UpdatedAt                 NullTime `xml:"updated_at,omitempty" json:"updated_at"`
+	VoidedAt                  NullTime `xml:"voided_at,omitempty" json:"voided_at"`
 }
 
 // UnmarshalXML unmarshals invoices and handles intermediary state during unmarshaling
 // for types like href.
 func (c *CreditPayment) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	type creditPaymentAlias CreditPayment
-	var v struct {
-		XMLName xml.Name `xml:"credit_payment"`
-		creditPaymentAlias
-		AccountCode           hrefString `xml:"account"`
-		OriginalInvoiceNumber hrefInt    `xml:"original_invoice"`
-		AppliedToInvoice      hrefInt    `xml:"applied_to_invoice"`
-		OriginalCreditPayment hrefString `xml:"original_credit_payment,omitempty"`
-		RefundTransaction     hrefString `xml:"refund_transaction,omitempty"`
-	}
+	var v creditPaymentFields
 	if err := d.DecodeElement(&v, &start); err != nil {
 		return err
 	}
@@ -56,3 +47,16 @@ func (c *CreditPayment) UnmarshalXML(d *xml.Decoder, start xml.StartElement) err
 
 	return nil
 }
+
+type creditPaymentAlias CreditPayment
+
+// creditPaymentFields is used by the custom unmarshal function.
+type creditPaymentFields struct {
+	XMLName xml.Name `xml:"credit_payment"`
+	creditPaymentAlias
+	AccountCode           hrefString `xml:"account"`
+	OriginalInvoiceNumber hrefInt    `xml:"original_invoice"`
+	AppliedToInvoice      hrefInt    `xml:"applied_to_invoice"`
+	OriginalCreditPayment hrefString `xml:"original_credit_payment,omitempty"`
+	RefundTransaction     hrefString `xml:"refund_transaction,omitempty"`
+}
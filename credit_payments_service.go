@@ -6,6 +6,10 @@ import (
 	"net/http"
 )
 
+// creditInvoicesAPIVersion is the api version that introduced credit
+// invoices and credit payments.
+const creditInvoicesAPIVersion = "2.10"
+
 var _ CreditPaymentsService = &creditInvoicesImpl{}
 
 // creditInvoicesImpl handles communication with the credit payment
@@ -17,6 +21,9 @@ type creditInvoicesImpl struct {
 // List returns a list of all credit payments.
 // https://dev.recurly.com/docs/list-credit-payments
 func (s *creditInvoicesImpl) List(params Params) (*Response, []CreditPayment, error) {
+	if err := s.client.requireAPIVersion(creditInvoicesAPIVersion); err != nil {
+		return nil, nil, err
+	}
 	req, err := s.client.newRequest("GET", "credit_payments", params, nil)
 	if err != nil {
 		return nil, nil, err
@@ -34,6 +41,9 @@ func (s *creditInvoicesImpl) List(params Params) (*Response, []CreditPayment, er
 // ListAccount returns a list of all credit payments for an account.
 // https://dev.recurly.com/docs/list-credit-payments-on-account
 func (s *creditInvoicesImpl) ListAccount(accountCode string, params Params) (*Response, []CreditPayment, error) {
+	if err := s.client.requireAPIVersion(creditInvoicesAPIVersion); err != nil {
+		return nil, nil, err
+	}
 	action := fmt.Sprintf("accounts/%s/credit_payments", accountCode)
 	req, err := s.client.newRequest("GET", action, params, nil)
 	if err != nil {
@@ -52,6 +62,9 @@ func (s *creditInvoicesImpl) ListAccount(accountCode string, params Params) (*Re
 // Get returns detailed information about a credit payment.
 // https://dev.recurly.com/docs/lookup-credit-payment
 func (s *creditInvoicesImpl) Get(uuid string) (*Response, *CreditPayment, error) {
+	if err := s.client.requireAPIVersion(creditInvoicesAPIVersion); err != nil {
+		return nil, nil, err
+	}
 	action := fmt.Sprintf("credit_payments/%s", uuid)
 	req, err := s.client.newRequest("GET", action, nil, nil)
 	if err != nil {
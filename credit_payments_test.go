@@ -85,7 +85,7 @@ func TestCreditPayments_List(t *testing.T) {
 			UpdatedAt:                 NewTimeFromString("2017-07-06T15:51:38Z"),
 		},
 	}); diff != "" {
-		t.Fatalf(diff)
+		t.Fatal(diff)
 	}
 }
 
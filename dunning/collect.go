@@ -0,0 +1,85 @@
+package dunning
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+// ErrRateLimited is returned by Collect when collection was attempted for
+// the account less than an Interval ago. It implements the error
+// interface.
+type ErrRateLimited struct {
+	AccountCode string
+	RetryAt     time.Time
+}
+
+// Error implements the error interface.
+func (e ErrRateLimited) Error() string {
+	return fmt.Sprintf("dunning: collection for account %s was attempted recently; retry at %s", e.AccountCode, e.RetryAt.Format(time.RFC3339))
+}
+
+// Collector retries collection of invoices, at most once per account per
+// Interval, as Recurly allows one manual collection attempt per account per
+// hour. It is safe for concurrent use.
+type Collector struct {
+	client *recurly.Client
+
+	// Interval is the minimum time between attempts for an account.
+	// Defaults to an hour.
+	Interval time.Duration
+
+	mu   sync.Mutex
+	last map[string]time.Time
+	now  func() time.Time
+}
+
+// NewCollector returns a Collector that collects with client.
+func NewCollector(client *recurly.Client) *Collector {
+	return &Collector{client: client, Interval: time.Hour, last: map[string]time.Time{}, now: time.Now}
+}
+
+// Collect attempts to collect an invoice of an account, unless collection
+// was attempted for the account less than an Interval ago. The attempt is
+// recorded whether or not it succeeds, as a declined payment still counts
+// towards Recurly's limit.
+func (c *Collector) Collect(accountCode string, invoiceNumber int) (*recurly.Response, *recurly.Invoice, error) {
+	c.mu.Lock()
+	now := c.now()
+	if last, ok := c.last[accountCode]; ok {
+		if retryAt := last.Add(c.Interval); now.Before(retryAt) {
+			c.mu.Unlock()
+			return nil, nil, ErrRateLimited{AccountCode: accountCode, RetryAt: retryAt}
+		}
+	}
+	c.last[accountCode] = now
+	c.mu.Unlock()
+
+	return c.client.Invoices.Collect(invoiceNumber)
+}
+
+// CollectOpen attempts to collect the open cycles of t, one invoice per
+// account, skipping accounts attempted less than an Interval ago. It
+// returns the invoices that were attempted, and stops at the first error
+// other than ErrRateLimited.
+func (c *Collector) CollectOpen(t *Tracker) ([]int, error) {
+	var attempted []int
+	accounts := map[string]bool{}
+	for _, cycle := range t.Cycles() {
+		if cycle.Outcome != Open || accounts[cycle.AccountCode] {
+			continue
+		}
+		accounts[cycle.AccountCode] = true
+
+		if _, _, err := c.Collect(cycle.AccountCode, cycle.InvoiceNumber); err != nil {
+			if _, ok := err.(ErrRateLimited); ok {
+				continue
+			}
+			return attempted, err
+		}
+		attempted = append(attempted, cycle.InvoiceNumber)
+	}
+	return attempted, nil
+}
@@ -0,0 +1,68 @@
+package dunning
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+func TestCollector_Collect(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := recurly.NewClient("test", "abc", nil, recurly.WithBaseURL(server.URL))
+
+	var collected []string
+	mux.HandleFunc("/v2/invoices/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		collected = append(collected, r.URL.Path)
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<invoice><invoice_number type="integer">1001</invoice_number><state>paid</state></invoice>`)
+	})
+
+	c := NewCollector(client)
+	now := at(1, 0)
+	c.now = func() time.Time { return now }
+
+	if _, invoice, err := c.Collect("1", 1001); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if invoice.State != "paid" {
+		t.Fatalf("unexpected invoice: %#v", invoice)
+	}
+
+	now = at(1, 0).Add(59 * time.Minute)
+	if _, _, err := c.Collect("1", 1002); err != (ErrRateLimited{AccountCode: "1", RetryAt: at(1, 1)}) {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, _, err := c.Collect("2", 1003); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now = at(1, 1)
+	if _, _, err := c.Collect("1", 1002); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(collected, []string{"/v2/invoices/1001/collect", "/v2/invoices/1003/collect", "/v2/invoices/1002/collect"}) {
+		t.Fatalf("unexpected requests: %v", collected)
+	}
+
+	// One invoice is collected per account, skipping those attempted
+	// recently.
+	collected = nil
+	tr := tracker(
+		Cycle{AccountCode: "1", InvoiceNumber: 2001, Outcome: Open, Started: at(1, 0)},
+		Cycle{AccountCode: "3", InvoiceNumber: 2002, Outcome: Open, Started: at(1, 0)},
+		Cycle{AccountCode: "3", InvoiceNumber: 2003, Outcome: Open, Started: at(2, 0)},
+		Cycle{AccountCode: "4", InvoiceNumber: 2004, Outcome: Recovered, Started: at(1, 0)},
+	)
+	if attempted, err := c.CollectOpen(tr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(attempted, []int{2002}) {
+		t.Fatalf("unexpected attempts: %v", attempted)
+	}
+}
@@ -0,0 +1,158 @@
+package dunning
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+// Summary totals the dunning cycles in a currency.
+type Summary struct {
+	Currency   string
+	Open       int
+	Recovered  int
+	WrittenOff int
+	Attempts   int
+
+	OpenAmount       recurly.Money
+	RecoveredAmount  recurly.Money
+	WrittenOffAmount recurly.Money
+
+	// MeanTimeToRecovery is the mean time recovered invoices took to be
+	// paid.
+	MeanTimeToRecovery time.Duration
+}
+
+// RecoveryRate returns the fraction of closed cycles that were recovered,
+// or 0 if none are closed.
+func (s Summary) RecoveryRate() float64 {
+	if s.Recovered+s.WrittenOff == 0 {
+		return 0
+	}
+	return float64(s.Recovered) / float64(s.Recovered+s.WrittenOff)
+}
+
+// Summary totals the tracked cycles in each currency.
+func (t *Tracker) Summary() map[string]Summary {
+	summaries := map[string]Summary{}
+	recovery := map[string]time.Duration{}
+	for _, c := range t.Cycles() {
+		currency := c.Amount.Currency
+		s, ok := summaries[currency]
+		if !ok {
+			zero := recurly.NewMoney(0, currency)
+			s = Summary{Currency: currency, OpenAmount: zero, RecoveredAmount: zero, WrittenOffAmount: zero}
+		}
+
+		s.Attempts += c.Attempts
+		switch c.Outcome {
+		case Open:
+			s.Open++
+			s.OpenAmount.Amount += c.Amount.Amount
+		case Recovered:
+			s.Recovered++
+			s.RecoveredAmount.Amount += c.Amount.Amount
+			recovery[currency] += c.TimeToRecovery()
+		case WrittenOff:
+			s.WrittenOff++
+			s.WrittenOffAmount.Amount += c.Amount.Amount
+		}
+		summaries[currency] = s
+	}
+
+	for currency, s := range summaries {
+		if s.Recovered > 0 {
+			s.MeanTimeToRecovery = recovery[currency] / time.Duration(s.Recovered)
+			summaries[currency] = s
+		}
+	}
+	return summaries
+}
+
+// Policy decides which accounts with open cycles to contact.
+type Policy struct {
+	// MinAttempts flags accounts with an open cycle with at least this many
+	// failed attempts. Defaults to 3.
+	MinAttempts int
+
+	// MaxAge flags accounts with an open cycle started at least this long
+	// ago. Defaults to 7 days.
+	MaxAge time.Duration
+}
+
+// Reason is why an account should be contacted.
+type Reason string
+
+// Reasons to contact an account.
+const (
+	ReasonAttempts Reason = "attempts"
+	ReasonAge      Reason = "age"
+)
+
+// Contact is an account to contact about its open cycles.
+type Contact struct {
+	AccountCode string
+	Reasons     []Reason
+	Cycles      []Cycle
+}
+
+// ToContact returns the accounts with open cycles that p flags, ordered by
+// account code.
+func (t *Tracker) ToContact(p Policy) []Contact {
+	if p.MinAttempts <= 0 {
+		p.MinAttempts = 3
+	}
+	if p.MaxAge <= 0 {
+		p.MaxAge = 7 * 24 * time.Hour
+	}
+
+	now := t.now()
+	byAccount := map[string]*Contact{}
+	for _, c := range t.Cycles() {
+		if c.Outcome != Open {
+			continue
+		}
+
+		var reasons []Reason
+		if c.Attempts >= p.MinAttempts {
+			reasons = append(reasons, ReasonAttempts)
+		}
+		if !c.Started.IsZero() && now.Sub(c.Started) >= p.MaxAge {
+			reasons = append(reasons, ReasonAge)
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+
+		contact, ok := byAccount[c.AccountCode]
+		if !ok {
+			contact = &Contact{AccountCode: c.AccountCode}
+			byAccount[c.AccountCode] = contact
+		}
+		contact.Cycles = append(contact.Cycles, c)
+		for _, r := range reasons {
+			if !hasReason(contact.Reasons, r) {
+				contact.Reasons = append(contact.Reasons, r)
+			}
+		}
+	}
+
+	contacts := make([]Contact, 0, len(byAccount))
+	for _, c := range byAccount {
+		contacts = append(contacts, *c)
+	}
+	sort.Slice(contacts, func(i, j int) bool {
+		return contacts[i].AccountCode < contacts[j].AccountCode
+	})
+	return contacts
+}
+
+func hasReason(reasons []Reason, r Reason) bool {
+	for _, reason := range reasons {
+		if reason == r {
+			return true
+		}
+	}
+	return false
+}
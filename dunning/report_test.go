@@ -0,0 +1,62 @@
+package dunning
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+func tracker(cycles ...Cycle) *Tracker {
+	t := NewTracker()
+	t.now = func() time.Time { return at(10, 0) }
+	for _, c := range cycles {
+		t.merge(c)
+	}
+	return t
+}
+
+func TestTracker_Summary(t *testing.T) {
+	usd := func(amount int) recurly.Money { return recurly.NewMoney(amount, "USD") }
+	tr := tracker(
+		Cycle{InvoiceNumber: 1, Outcome: Recovered, Amount: usd(1000), Attempts: 2, Started: at(1, 0), Ended: at(2, 0)},
+		Cycle{InvoiceNumber: 2, Outcome: Recovered, Amount: usd(500), Attempts: 1, Started: at(1, 0), Ended: at(4, 0)},
+		Cycle{InvoiceNumber: 3, Outcome: WrittenOff, Amount: usd(700), Attempts: 4, Started: at(1, 0)},
+		Cycle{InvoiceNumber: 4, Outcome: Open, Amount: usd(300), Attempts: 1, Started: at(9, 0)},
+		Cycle{InvoiceNumber: 5, Outcome: Open, Amount: recurly.NewMoney(900, "EUR"), Started: at(9, 0)},
+	)
+
+	summaries := tr.Summary()
+	if s := summaries["USD"]; !reflect.DeepEqual(s, Summary{
+		Currency: "USD", Open: 1, Recovered: 2, WrittenOff: 1, Attempts: 8,
+		OpenAmount: usd(300), RecoveredAmount: usd(1500), WrittenOffAmount: usd(700),
+		MeanTimeToRecovery: 48 * time.Hour,
+	}) {
+		t.Fatalf("unexpected summary: %#v", s)
+	} else if r := s.RecoveryRate(); r != 2.0/3 {
+		t.Fatalf("unexpected recovery rate: %v", r)
+	} else if s := summaries["EUR"]; s.Open != 1 || s.RecoveryRate() != 0 || s.MeanTimeToRecovery != 0 {
+		t.Fatalf("unexpected summary: %#v", s)
+	}
+}
+
+func TestTracker_ToContact(t *testing.T) {
+	tr := tracker(
+		Cycle{AccountCode: "a", InvoiceNumber: 1, Outcome: Open, Attempts: 3, Started: at(9, 0)},
+		Cycle{AccountCode: "a", InvoiceNumber: 2, Outcome: Open, Attempts: 1, Started: at(1, 0)},
+		Cycle{AccountCode: "b", InvoiceNumber: 3, Outcome: Open, Attempts: 1, Started: at(9, 0)},
+		Cycle{AccountCode: "c", InvoiceNumber: 4, Outcome: WrittenOff, Attempts: 5, Started: at(1, 0)},
+		Cycle{AccountCode: "d", InvoiceNumber: 5, Outcome: Open, Attempts: 1, Started: at(6, 0)},
+	)
+
+	contacts := tr.ToContact(Policy{})
+	if len(contacts) != 1 || contacts[0].AccountCode != "a" || len(contacts[0].Cycles) != 2 || !reflect.DeepEqual(contacts[0].Reasons, []Reason{ReasonAge, ReasonAttempts}) {
+		t.Fatalf("unexpected contacts: %#v", contacts)
+	}
+
+	contacts = tr.ToContact(Policy{MinAttempts: 5, MaxAge: 4 * 24 * time.Hour})
+	if len(contacts) != 2 || contacts[0].AccountCode != "a" || contacts[1].AccountCode != "d" || !reflect.DeepEqual(contacts[1].Reasons, []Reason{ReasonAge}) {
+		t.Fatalf("unexpected contacts: %#v", contacts)
+	}
+}
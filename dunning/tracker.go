@@ -0,0 +1,268 @@
+// Package dunning tracks the dunning cycles of invoices, from the first
+// failed collection until the invoice is paid or written off, to measure
+// recovery and find accounts to contact.
+//
+// A Tracker is fed by webhook notifications as they arrive, and by the
+// invoice history of accounts. A Collector retries collection of past-due
+// invoices while respecting the limit of one attempt per account per hour.
+package dunning
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kmikiy/recurly"
+	"github.com/kmikiy/recurly/webhooks"
+)
+
+// Outcome is the state of a dunning cycle.
+type Outcome string
+
+// Outcomes of dunning cycles.
+const (
+	Open       Outcome = "open"
+	Recovered  Outcome = "recovered"
+	WrittenOff Outcome = "written_off"
+)
+
+// Cycle is the dunning of an invoice.
+type Cycle struct {
+	AccountCode   string
+	InvoiceNumber int
+	Outcome       Outcome
+
+	// Amount is the invoice total.
+	Amount recurly.Money
+
+	// Attempts is the number of failed collection attempts.
+	Attempts int
+
+	Started     time.Time
+	LastAttempt time.Time
+
+	// Ended is when the invoice was paid or written off.
+	Ended time.Time
+}
+
+// TimeToRecovery returns how long a recovered invoice took to be paid, or 0
+// if it has not been.
+func (c Cycle) TimeToRecovery() time.Duration {
+	if c.Outcome != Recovered || c.Started.IsZero() || c.Ended.IsZero() {
+		return 0
+	}
+	return c.Ended.Sub(c.Started)
+}
+
+// ErrList is returned when listing invoices fails. It implements the error
+// interface.
+type ErrList struct {
+	Response *recurly.Response
+}
+
+// Error implements the error interface.
+func (e ErrList) Error() string {
+	return fmt.Sprintf("dunning: %s %s: %d", e.Response.Request.Method, e.Response.Request.URL.Path, e.Response.StatusCode)
+}
+
+// Tracker tracks dunning cycles by invoice. It is safe for concurrent use.
+type Tracker struct {
+	mu     sync.Mutex
+	cycles map[int]*Cycle
+	now    func() time.Time
+}
+
+// NewTracker returns an empty Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{cycles: map[int]*Cycle{}, now: time.Now}
+}
+
+// Handle updates cycles from a webhook notification, so it can be used as,
+// or called from, a webhooks.HandlerFunc. Dunning events start a cycle or
+// count an attempt, at the time they are handled, and closed invoice
+// notifications end cycles. Other notifications are ignored. Wrap the
+// Tracker with a webhooks.Deduplicator so redelivered events are not
+// counted twice.
+func (t *Tracker) Handle(notification interface{}) error {
+	switch n := notification.(type) {
+	case *webhooks.DunningEventNotificationNew:
+		t.attempt(*n)
+	case webhooks.DunningEventNotificationNew:
+		t.attempt(n)
+	case *webhooks.ChargeInvoiceNotificationClosed:
+		t.end(n.Invoice.InvoiceNumber, n.Invoice.State)
+	case webhooks.ChargeInvoiceNotificationClosed:
+		t.end(n.Invoice.InvoiceNumber, n.Invoice.State)
+	case *webhooks.InvoiceNotificationClosed:
+		t.end(n.Invoice.InvoiceNumber, n.Invoice.State)
+	case webhooks.InvoiceNotificationClosed:
+		t.end(n.Invoice.InvoiceNumber, n.Invoice.State)
+	}
+	return nil
+}
+
+// attempt counts a dunning event.
+func (t *Tracker) attempt(n webhooks.DunningEventNotificationNew) {
+	if n.Invoice.InvoiceNumber == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	c, ok := t.cycles[n.Invoice.InvoiceNumber]
+	if !ok {
+		c = &Cycle{
+			AccountCode:   n.Account.Code,
+			InvoiceNumber: n.Invoice.InvoiceNumber,
+			Outcome:       Open,
+			Amount:        recurly.NewMoney(n.Invoice.TotalInCents, n.Invoice.Currency),
+			Started:       now,
+		}
+		t.cycles[c.InvoiceNumber] = c
+	} else if c.Outcome != Open {
+		return
+	}
+	c.Attempts++
+	c.LastAttempt = now
+}
+
+// end ends the cycle of an invoice closed in state.
+func (t *Tracker) end(invoiceNumber int, state string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c, ok := t.cycles[invoiceNumber]
+	if !ok || c.Outcome != Open {
+		return
+	}
+
+	switch state {
+	case recurly.ChargeInvoiceStatePaid, recurly.InvoiceStateCollectedDeprecated:
+		c.Outcome = Recovered
+	case recurly.ChargeInvoiceStateFailed:
+		c.Outcome = WrittenOff
+	default:
+		return
+	}
+	c.Ended = t.now()
+}
+
+// Load adds the cycles of an account's invoice history: past-due invoices,
+// failed invoices and paid invoices with failed transactions. Attempts are
+// counted from failed transactions. Cycles already tracked are merged,
+// keeping the earliest start, the most attempts and the history's outcome
+// once the invoice is closed.
+func (t *Tracker) Load(client *recurly.Client, accountCode string) error {
+	params := recurly.Params{"per_page": 200}
+	for {
+		resp, invoices, err := client.Invoices.ListAccount(accountCode, params)
+		if err != nil {
+			return err
+		} else if resp.IsError() {
+			return ErrList{Response: resp}
+		}
+
+		for _, i := range invoices {
+			if c, ok := history(i); ok {
+				if c.AccountCode == "" {
+					c.AccountCode = accountCode
+				}
+				t.merge(c)
+			}
+		}
+
+		next := resp.Next()
+		if next == "" {
+			return nil
+		}
+		params["cursor"] = next
+	}
+}
+
+// history returns the cycle of an invoice from its state and transactions,
+// and reports whether it was dunned.
+func history(i recurly.Invoice) (Cycle, bool) {
+	c := Cycle{
+		AccountCode:   i.AccountCode,
+		InvoiceNumber: i.InvoiceNumber,
+		Amount:        i.Total(),
+	}
+	for _, tr := range i.Transactions {
+		if tr.Status != recurly.TransactionStatusFailed && tr.Status != "declined" {
+			continue
+		}
+		c.Attempts++
+		if at := tr.CreatedAt.Time; at != nil {
+			if c.Started.IsZero() || at.Before(c.Started) {
+				c.Started = *at
+			}
+			if at.After(c.LastAttempt) {
+				c.LastAttempt = *at
+			}
+		}
+	}
+	if c.Started.IsZero() && i.DueOn.Time != nil {
+		c.Started = *i.DueOn.Time
+	}
+	if i.ClosedAt.Time != nil {
+		c.Ended = *i.ClosedAt.Time
+	}
+
+	switch i.State {
+	case recurly.ChargeInvoiceStatePastDue:
+		c.Outcome, c.Ended = Open, time.Time{}
+	case recurly.ChargeInvoiceStateFailed:
+		c.Outcome = WrittenOff
+	case recurly.ChargeInvoiceStatePaid, recurly.InvoiceStateCollectedDeprecated:
+		if c.Attempts == 0 {
+			return Cycle{}, false
+		}
+		c.Outcome = Recovered
+	default:
+		return Cycle{}, false
+	}
+	return c, true
+}
+
+// merge adds c, or merges it with the tracked cycle of its invoice.
+func (t *Tracker) merge(c Cycle) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	existing, ok := t.cycles[c.InvoiceNumber]
+	if !ok {
+		t.cycles[c.InvoiceNumber] = &c
+		return
+	}
+
+	if !c.Started.IsZero() && (existing.Started.IsZero() || c.Started.Before(existing.Started)) {
+		existing.Started = c.Started
+	}
+	if c.LastAttempt.After(existing.LastAttempt) {
+		existing.LastAttempt = c.LastAttempt
+	}
+	if c.Attempts > existing.Attempts {
+		existing.Attempts = c.Attempts
+	}
+	if c.Outcome != Open {
+		existing.Outcome, existing.Ended = c.Outcome, c.Ended
+	}
+}
+
+// Cycles returns the tracked cycles, ordered by start.
+func (t *Tracker) Cycles() []Cycle {
+	t.mu.Lock()
+	cycles := make([]Cycle, 0, len(t.cycles))
+	for _, c := range t.cycles {
+		cycles = append(cycles, *c)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(cycles, func(i, j int) bool {
+		if !cycles[i].Started.Equal(cycles[j].Started) {
+			return cycles[i].Started.Before(cycles[j].Started)
+		}
+		return cycles[i].InvoiceNumber < cycles[j].InvoiceNumber
+	})
+	return cycles
+}
@@ -0,0 +1,121 @@
+package dunning
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kmikiy/recurly"
+	"github.com/kmikiy/recurly/webhooks"
+)
+
+func at(day, hour int) time.Time {
+	return time.Date(2018, 3, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestTracker_Handle(t *testing.T) {
+	tracker := NewTracker()
+	now := at(1, 0)
+	tracker.now = func() time.Time { return now }
+
+	account := webhooks.Account{Code: "1"}
+	event := func(invoiceNumber int) *webhooks.DunningEventNotificationNew {
+		return webhooks.NewDunningEventNotificationNew(account, webhooks.Invoice{InvoiceNumber: invoiceNumber, TotalInCents: 2000, Currency: "USD"}, recurly.Subscription{}, webhooks.Transaction{})
+	}
+
+	for _, n := range []interface{}{event(1001), event(1002), webhooks.PaymentNotificationFailed{}} {
+		if err := tracker.Handle(n); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	now = at(3, 0)
+	tracker.Handle(*event(1001))
+	now = at(4, 0)
+	tracker.Handle(webhooks.ChargeInvoiceNotificationClosed{Invoice: webhooks.ChargeInvoice{InvoiceNumber: 1001, State: "paid"}})
+	tracker.Handle(&webhooks.InvoiceNotificationClosed{Invoice: webhooks.Invoice{InvoiceNumber: 1002, State: "failed"}})
+
+	// Closed cycles are not reopened, and invoices never dunned are ignored.
+	tracker.Handle(event(1001))
+	tracker.Handle(webhooks.ChargeInvoiceNotificationClosed{Invoice: webhooks.ChargeInvoice{InvoiceNumber: 1003, State: "paid"}})
+
+	cycles := tracker.Cycles()
+	if len(cycles) != 2 {
+		t.Fatalf("unexpected cycles: %#v", cycles)
+	} else if c := cycles[0]; c.InvoiceNumber != 1001 || c.Outcome != Recovered || c.Attempts != 2 || !c.LastAttempt.Equal(at(3, 0)) || c.TimeToRecovery() != 72*time.Hour || c.Amount != recurly.NewMoney(2000, "USD") {
+		t.Fatalf("unexpected cycle: %#v", c)
+	} else if c := cycles[1]; c.InvoiceNumber != 1002 || c.Outcome != WrittenOff || c.AccountCode != "1" || c.TimeToRecovery() != 0 {
+		t.Fatalf("unexpected cycle: %#v", c)
+	}
+}
+
+func TestTracker_Load(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := recurly.NewClient("test", "abc", nil, recurly.WithBaseURL(server.URL))
+
+	mux.HandleFunc("/v2/accounts/1/invoices", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "" {
+			w.Header().Set("Link", `<https://test.recurly.com/v2/accounts/1/invoices?cursor=2>; rel="next"`)
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<invoices>
+				<invoice>
+					<invoice_number type="integer">1001</invoice_number>
+					<state>paid</state>
+					<currency>USD</currency>
+					<total_in_cents type="integer">2000</total_in_cents>
+					<closed_at type="datetime">2018-03-05T00:00:00Z</closed_at>
+					<transactions type="array">
+						<transaction><status>declined</status><created_at type="datetime">2018-03-01T00:00:00Z</created_at></transaction>
+						<transaction><status>declined</status><created_at type="datetime">2018-03-02T00:00:00Z</created_at></transaction>
+						<transaction><status>declined</status><created_at type="datetime">2018-03-03T00:00:00Z</created_at></transaction>
+						<transaction><status>success</status><created_at type="datetime">2018-03-05T00:00:00Z</created_at></transaction>
+					</transactions>
+				</invoice>
+				<invoice>
+					<invoice_number type="integer">1002</invoice_number>
+					<state>paid</state>
+					<currency>USD</currency>
+					<total_in_cents type="integer">2000</total_in_cents>
+				</invoice>
+			</invoices>`)
+			return
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<invoices>
+			<invoice>
+				<invoice_number type="integer">1003</invoice_number>
+				<state>past_due</state>
+				<currency>USD</currency>
+				<total_in_cents type="integer">3000</total_in_cents>
+				<due_on type="datetime">2018-03-04T00:00:00Z</due_on>
+			</invoice>
+		</invoices>`)
+	})
+
+	tracker := NewTracker()
+	tracker.now = func() time.Time { return at(2, 0) }
+	tracker.Handle(webhooks.NewDunningEventNotificationNew(webhooks.Account{Code: "1"}, webhooks.Invoice{InvoiceNumber: 1001, TotalInCents: 2000, Currency: "USD"}, recurly.Subscription{}, webhooks.Transaction{}))
+
+	if err := tracker.Load(client, "1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cycles := tracker.Cycles()
+	if len(cycles) != 2 {
+		t.Fatalf("unexpected cycles: %#v", cycles)
+	} else if c := cycles[0]; c.InvoiceNumber != 1001 || c.Outcome != Recovered || c.Attempts != 3 || !c.Started.Equal(at(1, 0)) || c.TimeToRecovery() != 96*time.Hour {
+		t.Fatalf("unexpected cycle: %#v", c)
+	} else if c := cycles[1]; c.InvoiceNumber != 1003 || c.Outcome != Open || c.AccountCode != "1" || !c.Started.Equal(at(4, 0)) || c.Attempts != 0 {
+		t.Fatalf("unexpected cycle: %#v", c)
+	}
+
+	mux.HandleFunc("/v2/accounts/2/invoices", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	})
+	if err := tracker.Load(client, "2"); err == nil || err.Error() != "dunning: GET /v2/accounts/2/invoices: 500" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
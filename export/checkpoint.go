@@ -0,0 +1,207 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/kmikiy/recurly"
+)
+
+// checkpoint records the progress of WriteFile: the cursor of the next page
+// and the size of the file once the previous page was written. Done is set
+// once the last page has been written. Params are formatted as they are
+// sent in the query string.
+type checkpoint struct {
+	Resource Resource          `json:"resource"`
+	Params   map[string]string `json:"params,omitempty"`
+	Columns  []string          `json:"columns"`
+	Cursor   string            `json:"cursor"`
+	Offset   int64             `json:"offset"`
+	Rows     int               `json:"rows"`
+	Done     bool              `json:"done,omitempty"`
+}
+
+// ErrCheckpointMismatch is returned by WriteFile when resuming an export of
+// a different resource, params or columns. It implements the error interface.
+type ErrCheckpointMismatch struct {
+	Path string
+}
+
+// Error implements the error interface.
+func (e ErrCheckpointMismatch) Error() string {
+	return fmt.Sprintf("export: checkpoint %s is for a different resource, params or columns", e.Path)
+}
+
+// CheckpointPath returns where WriteFile saves its progress when exporting
+// to path.
+func CheckpointPath(path string) string {
+	return path + ".checkpoint"
+}
+
+// WriteFile writes a header and every row of a resource to the file at
+// path, and returns the number of rows in the file.
+//
+// Progress is saved to CheckpointPath(path) after each page. If the export
+// is interrupted, calling WriteFile again with the same resource, params
+// and columns truncates any partially written page and resumes from the
+// cursor of the next page, so each row is written once. The checkpoint is
+// removed when the export completes. Remove it to start over.
+func (e *Exporter) WriteFile(path string, r Resource) (int, error) {
+	values, header, err := e.columns(r)
+	if err != nil {
+		return 0, err
+	}
+
+	cpPath := CheckpointPath(path)
+	cp, err := readCheckpoint(cpPath)
+	if err != nil {
+		return 0, err
+	}
+
+	params := formatParams(e.Params)
+	var f *os.File
+	if cp != nil {
+		if cp.Resource != r || !equalParams(cp.Params, params) || !equal(cp.Columns, header) {
+			return 0, ErrCheckpointMismatch{Path: cpPath}
+		} else if cp.Done {
+			return cp.Rows, os.Remove(cpPath)
+		}
+		if f, err = os.OpenFile(path, os.O_RDWR, 0644); err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		if err := f.Truncate(cp.Offset); err != nil {
+			return 0, err
+		} else if _, err := f.Seek(cp.Offset, io.SeekStart); err != nil {
+			return 0, err
+		}
+	} else {
+		if f, err = os.Create(path); err != nil {
+			return 0, err
+		}
+		defer f.Close()
+
+		cp = &checkpoint{Resource: r, Params: params, Columns: header}
+		if err := flush(f, cp, cpPath, func(cw *csv.Writer) error { return cw.Write(header) }); err != nil {
+			return 0, err
+		}
+	}
+
+	cw := csv.NewWriter(f)
+	err = e.export(r, cw, values, cp.Cursor, func(n int, next string) error {
+		cp.Cursor = next
+		cp.Rows += n
+		cp.Done = next == ""
+		return flush(f, cp, cpPath, nil)
+	})
+	if err != nil {
+		return cp.Rows, err
+	}
+
+	if err := f.Close(); err != nil {
+		return cp.Rows, err
+	}
+	return cp.Rows, os.Remove(cpPath)
+}
+
+// flush calls write, if set, syncs f and saves the checkpoint at its new
+// size.
+func flush(f *os.File, cp *checkpoint, cpPath string, write func(cw *csv.Writer) error) error {
+	if write != nil {
+		cw := csv.NewWriter(f)
+		if err := write(cw); err != nil {
+			return err
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	}
+
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	offset, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	cp.Offset = offset
+	return writeCheckpoint(cpPath, cp)
+}
+
+// readCheckpoint reads the checkpoint at path, or returns nil if there is
+// none.
+func readCheckpoint(path string) (*checkpoint, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var cp checkpoint
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return nil, fmt.Errorf("export: checkpoint %s: %v", path, err)
+	}
+	return &cp, nil
+}
+
+// writeCheckpoint replaces the checkpoint at path, via a temporary file so
+// it is never partially written.
+func writeCheckpoint(path string, cp *checkpoint) error {
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// formatParams formats params as they are sent in the query string, so they
+// can be saved and compared. per_page and cursor are set by the export.
+func formatParams(params recurly.Params) map[string]string {
+	if len(params) == 0 {
+		return nil
+	}
+
+	m := make(map[string]string, len(params))
+	for k, v := range params {
+		if k == "per_page" || k == "cursor" {
+			continue
+		}
+		m[k] = fmt.Sprintf("%v", v)
+	}
+	return m
+}
+
+func equalParams(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || v != w {
+			return false
+		}
+	}
+	return true
+}
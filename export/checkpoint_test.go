@@ -0,0 +1,96 @@
+package export
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kmikiy/recurly"
+)
+
+func TestExporter_WriteFileResumes(t *testing.T) {
+	mux := http.NewServeMux()
+	client, server := newClient(mux)
+	defer server.Close()
+
+	fail := true
+	mux.HandleFunc("/v2/accounts", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Header().Set("Link", `<https://test.recurly.com/v2/accounts?cursor=2>; rel="next"`)
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<accounts><account><account_code>1</account_code></account><account><account_code>2</account_code></account></accounts>`)
+		case "2":
+			if fail {
+				w.WriteHeader(503)
+				return
+			}
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<accounts><account><account_code>3</account_code></account></accounts>`)
+		default:
+			t.Fatalf("unexpected cursor: %s", r.URL.RawQuery)
+		}
+	})
+
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "accounts.csv")
+
+	e := NewExporter(client)
+	e.Columns = []string{"account_code"}
+	if n, err := e.WriteFile(path, Accounts); err == nil || err.Error() != "export: GET /v2/accounts: 503" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if n != 2 {
+		t.Fatalf("unexpected rows: %d", n)
+	} else if _, err := os.Stat(CheckpointPath(path)); err != nil {
+		t.Fatalf("expected checkpoint: %v", err)
+	}
+
+	// Rows written after the checkpoint are discarded on resume.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("partial\n")
+	f.Close()
+
+	// Resuming with different columns is an error.
+	e.Columns = []string{"account_code", "email"}
+	if _, err := e.WriteFile(path, Accounts); err != (ErrCheckpointMismatch{Path: CheckpointPath(path)}) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Resuming with different params is an error.
+	e.Columns = []string{"account_code"}
+	e.Params = recurly.Params{"state": "closed"}
+	if _, err := e.WriteFile(path, Accounts); err != (ErrCheckpointMismatch{Path: CheckpointPath(path)}) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fail = false
+	e.Params = nil
+	if n, err := e.WriteFile(path, Accounts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if n != 3 {
+		t.Fatalf("unexpected rows: %d", n)
+	} else if b, err := ioutil.ReadFile(path); err != nil {
+		t.Fatal(err)
+	} else if string(b) != "account_code\n1\n2\n3\n" {
+		t.Fatalf("unexpected csv: %q", b)
+	} else if _, err := os.Stat(CheckpointPath(path)); !os.IsNotExist(err) {
+		t.Fatalf("expected checkpoint to be removed: %v", err)
+	}
+
+	// Without a checkpoint the export starts over.
+	if n, err := e.WriteFile(path, Accounts); err != nil || n != 3 {
+		t.Fatalf("unexpected result: %d, %v", n, err)
+	} else if b, _ := ioutil.ReadFile(path); string(b) != "account_code\n1\n2\n3\n" {
+		t.Fatalf("unexpected csv: %q", b)
+	}
+}
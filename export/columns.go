@@ -0,0 +1,313 @@
+package export
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kmikiy/recurly"
+)
+
+// valueFunc returns a column's value for a row.
+type valueFunc func(row interface{}) string
+
+// column is a named CSV column.
+type column struct {
+	name  string
+	value valueFunc
+}
+
+// resource describes how to list and flatten a resource.
+type resource struct {
+	columns []column
+
+	// dynamic returns the value of a column that is not in columns, such as
+	// "custom_fields.device_id".
+	dynamic func(name string) (valueFunc, bool)
+
+	// list fetches a page and returns its rows. Invoices have a row per
+	// line item.
+	list func(c *recurly.Client, params recurly.Params) (*recurly.Response, []interface{}, error)
+}
+
+// resources are the supported resources.
+var resources = map[Resource]resource{
+	Accounts: {
+		columns: append([]column{
+			{"account_code", func(r interface{}) string { return r.(recurly.Account).Code }},
+			{"state", func(r interface{}) string { return r.(recurly.Account).State }},
+			{"username", func(r interface{}) string { return r.(recurly.Account).Username }},
+			{"email", func(r interface{}) string { return r.(recurly.Account).Email }},
+			{"first_name", func(r interface{}) string { return r.(recurly.Account).FirstName }},
+			{"last_name", func(r interface{}) string { return r.(recurly.Account).LastName }},
+			{"company_name", func(r interface{}) string { return r.(recurly.Account).CompanyName }},
+			{"vat_number", func(r interface{}) string { return r.(recurly.Account).VATNumber }},
+			{"tax_exempt", func(r interface{}) string { return formatBool(r.(recurly.Account).TaxExempt) }},
+			{"accept_language", func(r interface{}) string { return r.(recurly.Account).AcceptLanguage }},
+		}, append(addressColumns(func(r interface{}) recurly.Address { return r.(recurly.Account).Address }),
+			column{"has_active_subscription", func(r interface{}) string { return formatBool(r.(recurly.Account).HasActiveSubscription) }},
+			column{"has_past_due_invoice", func(r interface{}) string { return formatBool(r.(recurly.Account).HasPastDueInvoice) }},
+			column{"created_at", func(r interface{}) string { return r.(recurly.Account).CreatedAt.String() }},
+			column{"updated_at", func(r interface{}) string { return r.(recurly.Account).UpdatedAt.String() }},
+			column{"closed_at", func(r interface{}) string { return r.(recurly.Account).ClosedAt.String() }},
+		)...),
+		list: func(c *recurly.Client, params recurly.Params) (*recurly.Response, []interface{}, error) {
+			resp, accounts, err := c.Accounts.List(params)
+			rows := make([]interface{}, len(accounts))
+			for i, a := range accounts {
+				rows[i] = a
+			}
+			return resp, rows, err
+		},
+	},
+
+	Subscriptions: {
+		columns: []column{
+			{"uuid", func(r interface{}) string { return r.(recurly.Subscription).UUID }},
+			{"account_code", func(r interface{}) string { return r.(recurly.Subscription).AccountCode }},
+			{"plan_code", func(r interface{}) string { return r.(recurly.Subscription).Plan.Code }},
+			{"plan_name", func(r interface{}) string { return r.(recurly.Subscription).Plan.Name }},
+			{"state", func(r interface{}) string { return r.(recurly.Subscription).State }},
+			{"currency", func(r interface{}) string { return r.(recurly.Subscription).Currency }},
+			{"quantity", func(r interface{}) string { return strconv.Itoa(r.(recurly.Subscription).Quantity) }},
+			{"unit_amount_in_cents", func(r interface{}) string { return strconv.Itoa(r.(recurly.Subscription).UnitAmountInCents) }},
+			{"total_amount_in_cents", func(r interface{}) string { return strconv.Itoa(r.(recurly.Subscription).TotalAmountInCents) }},
+			{"subscription_add_ons", func(r interface{}) string { return formatAddOns(r.(recurly.Subscription).SubscriptionAddOns) }},
+			{"collection_method", func(r interface{}) string { return r.(recurly.Subscription).CollectionMethod }},
+			{"auto_renew", func(r interface{}) string { return strconv.FormatBool(r.(recurly.Subscription).AutoRenew) }},
+			{"activated_at", func(r interface{}) string { return r.(recurly.Subscription).ActivatedAt.String() }},
+			{"canceled_at", func(r interface{}) string { return r.(recurly.Subscription).CanceledAt.String() }},
+			{"expires_at", func(r interface{}) string { return r.(recurly.Subscription).ExpiresAt.String() }},
+			{"current_period_started_at", func(r interface{}) string { return r.(recurly.Subscription).CurrentPeriodStartedAt.String() }},
+			{"current_period_ends_at", func(r interface{}) string { return r.(recurly.Subscription).CurrentPeriodEndsAt.String() }},
+			{"trial_ends_at", func(r interface{}) string { return r.(recurly.Subscription).TrialEndsAt.String() }},
+			{"current_term_ends_at", func(r interface{}) string { return r.(recurly.Subscription).CurrentTermEndsAt.String() }},
+			{"paused_at", func(r interface{}) string { return r.(recurly.Subscription).PausedAt.String() }},
+			{"remaining_pause_cycles", func(r interface{}) string { return strconv.Itoa(r.(recurly.Subscription).RemainingPauseCycles) }},
+			{"custom_fields", func(r interface{}) string { return formatCustomFields(r.(recurly.Subscription).CustomFields) }},
+		},
+		dynamic: subscriptionColumn,
+		list: func(c *recurly.Client, params recurly.Params) (*recurly.Response, []interface{}, error) {
+			resp, subs, err := c.Subscriptions.List(params)
+			rows := make([]interface{}, len(subs))
+			for i, s := range subs {
+				rows[i] = s
+			}
+			return resp, rows, err
+		},
+	},
+
+	Invoices: {
+		columns: append([]column{
+			{"invoice_number", func(r interface{}) string {
+				i := r.(invoiceRow).invoice
+				return i.InvoiceNumberPrefix + strconv.Itoa(i.InvoiceNumber)
+			}},
+			{"uuid", func(r interface{}) string { return r.(invoiceRow).invoice.UUID }},
+			{"account_code", func(r interface{}) string { return r.(invoiceRow).invoice.AccountCode }},
+			{"state", func(r interface{}) string { return r.(invoiceRow).invoice.State }},
+			{"type", func(r interface{}) string { return r.(invoiceRow).invoice.Type }},
+			{"origin", func(r interface{}) string { return r.(invoiceRow).invoice.Origin }},
+			{"currency", func(r interface{}) string { return r.(invoiceRow).invoice.Currency }},
+			{"subtotal_in_cents", func(r interface{}) string { return strconv.Itoa(r.(invoiceRow).invoice.SubtotalInCents) }},
+			{"discount_in_cents", func(r interface{}) string { return strconv.Itoa(r.(invoiceRow).invoice.DiscountInCents) }},
+			{"tax_in_cents", func(r interface{}) string { return strconv.Itoa(r.(invoiceRow).invoice.TaxInCents) }},
+			{"total_in_cents", func(r interface{}) string { return strconv.Itoa(r.(invoiceRow).invoice.TotalInCents) }},
+			{"balance_in_cents", func(r interface{}) string { return strconv.Itoa(r.(invoiceRow).invoice.BalanceInCents) }},
+			{"created_at", func(r interface{}) string { return r.(invoiceRow).invoice.CreatedAt.String() }},
+			{"due_on", func(r interface{}) string { return r.(invoiceRow).invoice.DueOn.String() }},
+			{"closed_at", func(r interface{}) string { return r.(invoiceRow).invoice.ClosedAt.String() }},
+		}, append(addressColumns(func(r interface{}) recurly.Address { return r.(invoiceRow).invoice.Address }),
+			lineItem("uuid", func(a recurly.Adjustment) string { return a.UUID }),
+			lineItem("description", func(a recurly.Adjustment) string { return a.Description }),
+			lineItem("product_code", func(a recurly.Adjustment) string { return a.ProductCode }),
+			lineItem("accounting_code", func(a recurly.Adjustment) string { return a.AccountingCode }),
+			lineItem("subscription_uuid", func(a recurly.Adjustment) string { return a.SubscriptionUUID }),
+			lineItem("origin", func(a recurly.Adjustment) string { return a.Origin }),
+			lineItem("quantity", func(a recurly.Adjustment) string { return strconv.Itoa(a.Quantity) }),
+			lineItem("unit_amount_in_cents", func(a recurly.Adjustment) string { return strconv.Itoa(a.UnitAmountInCents) }),
+			lineItem("discount_in_cents", func(a recurly.Adjustment) string { return strconv.Itoa(a.DiscountInCents) }),
+			lineItem("tax_in_cents", func(a recurly.Adjustment) string { return strconv.Itoa(a.TaxInCents) }),
+			lineItem("total_in_cents", func(a recurly.Adjustment) string { return strconv.Itoa(a.TotalInCents) }),
+			lineItem("start_date", func(a recurly.Adjustment) string { return a.StartDate.String() }),
+			lineItem("end_date", func(a recurly.Adjustment) string { return a.EndDate.String() }),
+		)...),
+		list: func(c *recurly.Client, params recurly.Params) (*recurly.Response, []interface{}, error) {
+			resp, invoices, err := c.Invoices.List(params)
+			var rows []interface{}
+			for i := range invoices {
+				rows = append(rows, invoiceRows(&invoices[i])...)
+			}
+			return resp, rows, err
+		},
+	},
+
+	Transactions: {
+		columns: []column{
+			{"uuid", func(r interface{}) string { return r.(recurly.Transaction).UUID }},
+			{"account_code", func(r interface{}) string { return r.(recurly.Transaction).Account.Code }},
+			{"invoice_number", func(r interface{}) string { return formatInt(r.(recurly.Transaction).InvoiceNumber) }},
+			{"subscription_uuid", func(r interface{}) string { return r.(recurly.Transaction).SubscriptionUUID }},
+			{"action", func(r interface{}) string { return r.(recurly.Transaction).Action }},
+			{"status", func(r interface{}) string { return r.(recurly.Transaction).Status }},
+			{"currency", func(r interface{}) string { return r.(recurly.Transaction).Currency }},
+			{"amount_in_cents", func(r interface{}) string { return strconv.Itoa(r.(recurly.Transaction).AmountInCents) }},
+			{"tax_in_cents", func(r interface{}) string { return strconv.Itoa(r.(recurly.Transaction).TaxInCents) }},
+			{"payment_method", func(r interface{}) string { return r.(recurly.Transaction).PaymentMethod }},
+			{"reference", func(r interface{}) string { return r.(recurly.Transaction).Reference }},
+			{"source", func(r interface{}) string { return r.(recurly.Transaction).Source }},
+			{"recurring", func(r interface{}) string { return formatBool(r.(recurly.Transaction).Recurring) }},
+			{"test", func(r interface{}) string { return strconv.FormatBool(r.(recurly.Transaction).Test) }},
+			{"cvv_result", func(r interface{}) string { return r.(recurly.Transaction).CVVResult.Code }},
+			{"avs_result", func(r interface{}) string { return r.(recurly.Transaction).AVSResult.Code }},
+			{"error_code", func(r interface{}) string {
+				if e := r.(recurly.Transaction).TransactionError; e != nil {
+					return e.ErrorCode
+				}
+				return ""
+			}},
+			{"created_at", func(r interface{}) string { return r.(recurly.Transaction).CreatedAt.String() }},
+		},
+		list: func(c *recurly.Client, params recurly.Params) (*recurly.Response, []interface{}, error) {
+			resp, transactions, err := c.Transactions.List(params)
+			rows := make([]interface{}, len(transactions))
+			for i, t := range transactions {
+				rows[i] = t
+			}
+			return resp, rows, err
+		},
+	},
+}
+
+// addressColumns returns the address.* columns for the address of a row.
+func addressColumns(address func(r interface{}) recurly.Address) []column {
+	field := func(name string, value func(a recurly.Address) string) column {
+		return column{"address." + name, func(r interface{}) string { return value(address(r)) }}
+	}
+	return []column{
+		field("name", func(a recurly.Address) string { return a.Name }),
+		field("address1", func(a recurly.Address) string { return a.Address }),
+		field("address2", func(a recurly.Address) string { return a.Address2 }),
+		field("city", func(a recurly.Address) string { return a.City }),
+		field("state", func(a recurly.Address) string { return a.State }),
+		field("zip", func(a recurly.Address) string { return a.Zip }),
+		field("country", func(a recurly.Address) string { return a.Country }),
+		field("phone", func(a recurly.Address) string { return a.Phone }),
+	}
+}
+
+// invoiceRow is an invoice and one of its line items. item is nil for
+// invoices without line items.
+type invoiceRow struct {
+	invoice *recurly.Invoice
+	item    *recurly.Adjustment
+}
+
+// invoiceRows explodes an invoice into a row per line item.
+func invoiceRows(i *recurly.Invoice) []interface{} {
+	if len(i.LineItems) == 0 {
+		return []interface{}{invoiceRow{invoice: i}}
+	}
+
+	rows := make([]interface{}, len(i.LineItems))
+	for j := range i.LineItems {
+		rows[j] = invoiceRow{invoice: i, item: &i.LineItems[j]}
+	}
+	return rows
+}
+
+// lineItem returns a line_item.* column, which is empty for invoices
+// without line items.
+func lineItem(name string, value func(a recurly.Adjustment) string) column {
+	return column{"line_item." + name, func(r interface{}) string {
+		if item := r.(invoiceRow).item; item != nil {
+			return value(*item)
+		}
+		return ""
+	}}
+}
+
+// subscriptionColumn returns the dynamic subscription columns:
+// custom_fields.NAME, and subscription_add_ons.CODE.quantity and
+// subscription_add_ons.CODE.unit_amount_in_cents.
+func subscriptionColumn(name string) (valueFunc, bool) {
+	if strings.HasPrefix(name, "custom_fields.") {
+		field := strings.TrimPrefix(name, "custom_fields.")
+		return func(r interface{}) string {
+			if cf := r.(recurly.Subscription).CustomFields; cf != nil {
+				return (*cf)[field]
+			}
+			return ""
+		}, true
+	}
+
+	if strings.HasPrefix(name, "subscription_add_ons.") {
+		rest := strings.TrimPrefix(name, "subscription_add_ons.")
+		i := strings.LastIndex(rest, ".")
+		if i <= 0 {
+			return nil, false
+		}
+
+		code, attr := rest[:i], rest[i+1:]
+		var value func(a recurly.SubscriptionAddOn) string
+		switch attr {
+		case "quantity":
+			value = func(a recurly.SubscriptionAddOn) string { return strconv.Itoa(a.Quantity) }
+		case "unit_amount_in_cents":
+			value = func(a recurly.SubscriptionAddOn) string { return strconv.Itoa(a.UnitAmountInCents) }
+		default:
+			return nil, false
+		}
+		return func(r interface{}) string {
+			for _, a := range r.(recurly.Subscription).SubscriptionAddOns {
+				if a.Code == code {
+					return value(a)
+				}
+			}
+			return ""
+		}, true
+	}
+	return nil, false
+}
+
+// formatAddOns formats add-ons as "code:quantity:unit_amount_in_cents",
+// separated by semicolons.
+func formatAddOns(addOns []recurly.SubscriptionAddOn) string {
+	parts := make([]string, len(addOns))
+	for i, a := range addOns {
+		parts[i] = a.Code + ":" + strconv.Itoa(a.Quantity) + ":" + strconv.Itoa(a.UnitAmountInCents)
+	}
+	return strings.Join(parts, ";")
+}
+
+// formatCustomFields formats custom fields as "name=value", sorted by name
+// and separated by semicolons.
+func formatCustomFields(cf *recurly.CustomFields) string {
+	if cf == nil {
+		return ""
+	}
+
+	parts := make([]string, 0, len(*cf))
+	for k, v := range *cf {
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ";")
+}
+
+// formatBool formats a NullBool, or an empty string if it is not set.
+func formatBool(b recurly.NullBool) string {
+	if !b.Valid {
+		return ""
+	}
+	return strconv.FormatBool(b.Bool)
+}
+
+// formatInt formats an int, or an empty string for zero, for references
+// such as invoice numbers.
+func formatInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
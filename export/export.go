@@ -0,0 +1,211 @@
+// Package export writes Recurly accounts, subscriptions, invoices and
+// transactions as flattened CSV, for spreadsheets and data warehouses.
+//
+// Each resource has a set of columns, named after the API's fields, which
+// are all written by default. Nested fields are flattened:
+//   - Addresses become address.city, address.country and so on.
+//   - Subscription add-ons are written as "code:quantity:unit_amount_in_cents"
+//     in subscription_add_ons, and can be selected individually as
+//     subscription_add_ons.CODE.quantity and
+//     subscription_add_ons.CODE.unit_amount_in_cents.
+//   - Subscription custom fields are written as "name=value" in
+//     custom_fields, and can be selected individually as
+//     custom_fields.NAME. Custom fields are not discovered, so each one
+//     that needs its own column must be listed in Columns.
+//   - Invoices are written with a row per line item, with the line item in
+//     the line_item.* columns.
+//
+// WriteFile saves its progress so an interrupted export resumes from the
+// last page written.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/kmikiy/recurly"
+)
+
+// Resource is a resource that can be exported.
+type Resource string
+
+// Resources that can be exported.
+const (
+	Accounts      Resource = "accounts"
+	Subscriptions Resource = "subscriptions"
+	Invoices      Resource = "invoices"
+	Transactions  Resource = "transactions"
+)
+
+// Columns returns the default columns of a resource. Dynamic columns, such
+// as custom_fields.NAME, are not included.
+func Columns(r Resource) []string {
+	res, ok := resources[r]
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, len(res.columns))
+	for i, c := range res.columns {
+		names[i] = c.name
+	}
+	return names
+}
+
+// ErrUnknownResource is returned when exporting an unsupported resource.
+// It implements the error interface.
+type ErrUnknownResource struct {
+	Resource Resource
+}
+
+// Error implements the error interface.
+func (e ErrUnknownResource) Error() string {
+	return fmt.Sprintf("export: unknown resource %q", e.Resource)
+}
+
+// ErrUnknownColumn is returned when a selected column does not exist. It
+// implements the error interface.
+type ErrUnknownColumn struct {
+	Resource Resource
+	Column   string
+}
+
+// Error implements the error interface.
+func (e ErrUnknownColumn) Error() string {
+	return fmt.Sprintf("export: unknown %s column %q", e.Resource, e.Column)
+}
+
+// ErrList is returned when listing a resource fails. It implements the
+// error interface.
+type ErrList struct {
+	Response *recurly.Response
+}
+
+// Error implements the error interface.
+func (e ErrList) Error() string {
+	return fmt.Sprintf("export: %s %s: %d", e.Response.Request.Method, e.Response.Request.URL.Path, e.Response.StatusCode)
+}
+
+// Exporter writes resources as CSV.
+type Exporter struct {
+	client *recurly.Client
+
+	// Params are sent with each list request, to filter what is exported,
+	// such as {"state": "past_due"} or {"begin_time": ...}.
+	Params recurly.Params
+
+	// Columns selects the columns to write, in order. Defaults to Columns
+	// of the resource, which has no dynamic columns: list each
+	// custom_fields.NAME or subscription_add_ons.CODE.* column to write.
+	Columns []string
+
+	// PerPage is the page size used when listing resources. Defaults to 200.
+	PerPage int
+}
+
+// NewExporter returns an Exporter that lists resources with client.
+func NewExporter(client *recurly.Client) *Exporter {
+	return &Exporter{client: client}
+}
+
+// Write writes a header and every row of a resource to w. It returns the
+// number of rows written.
+func (e *Exporter) Write(w io.Writer, r Resource) (int, error) {
+	values, header, err := e.columns(r)
+	if err != nil {
+		return 0, err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return 0, err
+	}
+
+	rows := 0
+	err = e.export(r, cw, values, "", func(n int, next string) error {
+		rows += n
+		return nil
+	})
+	return rows, err
+}
+
+// columns returns the value functions and names of the selected columns.
+func (e *Exporter) columns(r Resource) ([]valueFunc, []string, error) {
+	res, ok := resources[r]
+	if !ok {
+		return nil, nil, ErrUnknownResource{Resource: r}
+	}
+
+	names := e.Columns
+	if len(names) == 0 {
+		names = Columns(r)
+	}
+
+	values := make([]valueFunc, len(names))
+	for i, name := range names {
+		for _, c := range res.columns {
+			if c.name == name {
+				values[i] = c.value
+				break
+			}
+		}
+		if values[i] == nil && res.dynamic != nil {
+			values[i], _ = res.dynamic(name)
+		}
+		if values[i] == nil {
+			return nil, nil, ErrUnknownColumn{Resource: r, Column: name}
+		}
+	}
+	return values, names, nil
+}
+
+// export writes the rows of each page, starting at cursor, and calls page
+// with the number of rows written and the cursor of the next page once a
+// page has been flushed.
+func (e *Exporter) export(r Resource, cw *csv.Writer, values []valueFunc, cursor string, page func(n int, next string) error) error {
+	perPage := e.PerPage
+	if perPage <= 0 {
+		perPage = 200
+	}
+
+	params := recurly.Params{}
+	for k, v := range e.Params {
+		params[k] = v
+	}
+	params["per_page"] = perPage
+
+	record := make([]string, len(values))
+	for {
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+
+		resp, rows, err := resources[r].list(e.client, params)
+		if err != nil {
+			return err
+		} else if resp.IsError() {
+			return ErrList{Response: resp}
+		}
+
+		for _, row := range rows {
+			for i, value := range values {
+				record[i] = value(row)
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+
+		cursor = resp.Next()
+		if err := page(len(rows), cursor); err != nil {
+			return err
+		} else if cursor == "" {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/kmikiy/recurly"
+)
+
+func newClient(mux *http.ServeMux) (*recurly.Client, *httptest.Server) {
+	server := httptest.NewServer(mux)
+	return recurly.NewClient("test", "abc", nil, recurly.WithBaseURL(server.URL)), server
+}
+
+func TestExporter_Subscriptions(t *testing.T) {
+	mux := http.NewServeMux()
+	client, server := newClient(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != "active" || r.URL.Query().Get("per_page") != "200" {
+			t.Fatalf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<subscriptions>
+			<subscription>
+				<account href="https://test.recurly.com/v2/accounts/1"/>
+				<plan><plan_code>gold</plan_code><name>Gold plan</name></plan>
+				<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+				<quantity type="integer">2</quantity>
+				<subscription_add_ons type="array">
+					<subscription_add_on>
+						<add_on_code>extra_users</add_on_code>
+						<unit_amount_in_cents type="integer">500</unit_amount_in_cents>
+						<quantity type="integer">3</quantity>
+					</subscription_add_on>
+					<subscription_add_on>
+						<add_on_code>support</add_on_code>
+						<unit_amount_in_cents type="integer">1000</unit_amount_in_cents>
+						<quantity type="integer">1</quantity>
+					</subscription_add_on>
+				</subscription_add_ons>
+				<custom_fields type="array">
+					<custom_field><name>device_id</name><value>KIWTL-WER-ZXMRD</value></custom_field>
+					<custom_field><name>color</name><value>blue, "dark"</value></custom_field>
+				</custom_fields>
+			</subscription>
+			<subscription>
+				<account href="https://test.recurly.com/v2/accounts/2"/>
+				<plan><plan_code>silver</plan_code></plan>
+				<uuid>5a1d9f5c3a8e4a8f8f4c2f6b3e7d9c1a</uuid>
+				<quantity type="integer">1</quantity>
+			</subscription>
+		</subscriptions>`)
+	})
+
+	e := NewExporter(client)
+	e.Params = recurly.Params{"state": "active"}
+	e.Columns = []string{
+		"uuid", "account_code", "plan_code", "quantity", "subscription_add_ons",
+		"subscription_add_ons.extra_users.quantity", "custom_fields", "custom_fields.device_id",
+	}
+
+	var buf bytes.Buffer
+	if n, err := e.Write(&buf, Subscriptions); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if n != 2 {
+		t.Fatalf("unexpected rows: %d", n)
+	}
+
+	expected := "uuid,account_code,plan_code,quantity,subscription_add_ons,subscription_add_ons.extra_users.quantity,custom_fields,custom_fields.device_id\n" +
+		`44f83d7cba354d5b84812419f923ea96,1,gold,2,extra_users:3:500;support:1:1000,3,"color=blue, ""dark"";device_id=KIWTL-WER-ZXMRD",KIWTL-WER-ZXMRD` + "\n" +
+		"5a1d9f5c3a8e4a8f8f4c2f6b3e7d9c1a,2,silver,1,,,,\n"
+	if buf.String() != expected {
+		t.Fatalf("unexpected csv:\n%s", buf.String())
+	}
+}
+
+func TestExporter_Invoices(t *testing.T) {
+	mux := http.NewServeMux()
+	client, server := newClient(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/v2/invoices", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<invoices>
+			<invoice>
+				<account href="https://test.recurly.com/v2/accounts/1"/>
+				<invoice_number_prefix>CN</invoice_number_prefix>
+				<invoice_number type="integer">1005</invoice_number>
+				<currency>USD</currency>
+				<total_in_cents type="integer">2500</total_in_cents>
+				<address><city>San Francisco</city><country>US</country></address>
+				<line_items type="array">
+					<adjustment><uuid>a1</uuid><description>Gold plan</description><total_in_cents type="integer">2000</total_in_cents></adjustment>
+					<adjustment><uuid>a2</uuid><description>Extra users</description><total_in_cents type="integer">500</total_in_cents></adjustment>
+				</line_items>
+			</invoice>
+			<invoice>
+				<account href="https://test.recurly.com/v2/accounts/2"/>
+				<invoice_number type="integer">1006</invoice_number>
+				<currency>EUR</currency>
+				<total_in_cents type="integer">0</total_in_cents>
+			</invoice>
+		</invoices>`)
+	})
+
+	e := NewExporter(client)
+	e.Columns = []string{"invoice_number", "account_code", "total_in_cents", "address.country", "line_item.uuid", "line_item.description", "line_item.total_in_cents"}
+
+	var buf bytes.Buffer
+	if n, err := e.Write(&buf, Invoices); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if n != 3 {
+		t.Fatalf("unexpected rows: %d", n)
+	} else if buf.String() != "invoice_number,account_code,total_in_cents,address.country,line_item.uuid,line_item.description,line_item.total_in_cents\n"+
+		"CN1005,1,2500,US,a1,Gold plan,2000\n"+
+		"CN1005,1,2500,US,a2,Extra users,500\n"+
+		"1006,2,0,,,,\n" {
+		t.Fatalf("unexpected csv:\n%s", buf.String())
+	}
+}
+
+func TestExporter_Errors(t *testing.T) {
+	mux := http.NewServeMux()
+	client, server := newClient(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/v2/transactions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	})
+
+	e := NewExporter(client)
+	if _, err := e.Write(&bytes.Buffer{}, Transactions); err == nil || err.Error() != "export: GET /v2/transactions: 500" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := e.Write(&bytes.Buffer{}, "plans"); err != (ErrUnknownResource{Resource: "plans"}) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	e.Columns = []string{"uuid", "custom_fields.device_id"}
+	if _, err := e.Write(&bytes.Buffer{}, Transactions); err != (ErrUnknownColumn{Resource: Transactions, Column: "custom_fields.device_id"}) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e.Columns = []string{"subscription_add_ons.extra_users.description"}
+	if _, err := e.Write(&bytes.Buffer{}, Subscriptions); err == nil || err.Error() != `export: unknown subscriptions column "subscription_add_ons.extra_users.description"` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestColumns(t *testing.T) {
+	if c := Columns(Transactions); !reflect.DeepEqual(c[:4], []string{"uuid", "account_code", "invoice_number", "subscription_uuid"}) {
+		t.Fatalf("unexpected columns: %v", c)
+	} else if c := Columns("plans"); c != nil {
+		t.Fatalf("unexpected columns: %v", c)
+	}
+
+	// Every default column must resolve.
+	for r := range resources {
+		if _, _, err := NewExporter(nil).columns(r); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
@@ -72,40 +72,40 @@ const (
 // The only fields annotated with XML tags are those for posting an invoice.
 // Unmarshaling an invoice is handled by the custom UnmarshalXML function.
 type Invoice struct {
-	XMLName                 xml.Name        `xml:"invoice,omitempty"`
-	AccountCode             string          `xml:"-"`
-	Address                 Address         `xml:"-"`
-	OriginalInvoiceNumber   int             `xml:"-"`
-	UUID                    string          `xml:"-"`
-	State                   string          `xml:"-"`
-	InvoiceNumberPrefix     string          `xml:"-"`
-	InvoiceNumber           int             `xml:"-"`
-	PONumber                string          `xml:"po_number,omitempty"` // PostInvoice param
-	VATNumber               string          `xml:"-"`
-	DiscountInCents         int             `xml:"-"`
-	SubtotalInCents         int             `xml:"-"`
-	TaxInCents              int             `xml:"-"`
-	TotalInCents            int             `xml:"-"`
-	BalanceInCents          int             `xml:"-"`
-	Currency                string          `xml:"-"`
-	DueOn                   NullTime        `xml:"-"`
-	CreatedAt               NullTime        `xml:"-"`
-	UpdatedAt               NullTime        `xml:"-"`
-	AttemptNextCollectionAt NullTime        `xml:"-"`
-	ClosedAt                NullTime        `xml:"-"`
-	Type                    string          `xml:"-"`
-	Origin                  string          `xml:"-"`
-	TaxType                 string          `xml:"-"`
-	TaxRegion               string          `xml:"-"`
-	TaxRate                 float64         `xml:"-"`
-	NetTerms                NullInt         `xml:"net_terms,omitempty"`                // PostInvoice param
-	CollectionMethod        string          `xml:"collection_method,omitempty"`        // PostInvoice param
-	TermsAndConditions      string          `xml:"terms_and_conditions,omitempty"`     // PostInvoice param
-	CustomerNotes           string          `xml:"customer_notes,omitempty"`           // PostInvoice param
-	VatReverseChargeNotes   string          `xml:"vat_reverse_charge_notes,omitempty"` // PostInvoice param
-	LineItems               []Adjustment    `xml:"-"`
-	Transactions            []Transaction   `xml:"-"`
-	CreditPayments          []CreditPayment `xml:"-"`
+	XMLName                 xml.Name        `xml:"invoice,omitempty" json:"-"`
+	AccountCode             string          `xml:"-" json:"account_code,omitempty"`
+	Address                 Address         `xml:"-" json:"address"`
+	OriginalInvoiceNumber   int             `xml:"-" json:"original_invoice_number,omitempty"`
+	UUID                    string          `xml:"-" json:"uuid,omitempty"`
+	State                   string          `xml:"-" json:"state,omitempty"`
+	InvoiceNumberPrefix     string          `xml:"-" json:"invoice_number_prefix,omitempty"`
+	InvoiceNumber           int             `xml:"-" json:"invoice_number,omitempty"`
+	PONumber                string          `xml:"po_number,omitempty" json:"po_number,omitempty"` // PostInvoice param
+	VATNumber               string          `xml:"-" json:"vat_number,omitempty"`
+	DiscountInCents         int             `xml:"-" json:"discount_in_cents,omitempty"`
+	SubtotalInCents         int             `xml:"-" json:"subtotal_in_cents,omitempty"`
+	TaxInCents              int             `xml:"-" json:"tax_in_cents,omitempty"`
+	TotalInCents            int             `xml:"-" json:"total_in_cents,omitempty"`
+	BalanceInCents          int             `xml:"-" json:"balance_in_cents,omitempty"`
+	Currency                string          `xml:"-" json:"currency,omitempty"`
+	DueOn                   NullTime        `xml:"-" json:"due_on"`
+	CreatedAt               NullTime        `xml:"-" json:"created_at"`
+	UpdatedAt               NullTime        `xml:"-" json:"updated_at"`
+	AttemptNextCollectionAt NullTime        `xml:"-" json:"attempt_next_collection_at"`
+	ClosedAt                NullTime        `xml:"-" json:"closed_at"`
+	Type                    string          `xml:"-" json:"type,omitempty"`
+	Origin                  string          `xml:"-" json:"origin,omitempty"`
+	TaxType                 string          `xml:"-" json:"tax_type,omitempty"`
+	TaxRegion               string          `xml:"-" json:"tax_region,omitempty"`
+	TaxRate                 float64         `xml:"-" json:"tax_rate,omitempty"`
+	NetTerms                NullInt         `xml:"net_terms,omitempty" json:"net_terms"`                                         // PostInvoice param
+	CollectionMethod        string          `xml:"collection_method,omitempty" json:"collection_method,omitempty"`               // PostInvoice param
+	TermsAndConditions      string          `xml:"terms_and_conditions,omitempty" json:"terms_and_conditions,omitempty"`         // PostInvoice param
+	CustomerNotes           string          `xml:"customer_notes,omitempty" json:"customer_notes,omitempty"`                     // PostInvoice param
+	VatReverseChargeNotes   string          `xml:"vat_reverse_charge_notes,omitempty" json:"vat_reverse_charge_notes,omitempty"` // PostInvoice param
+	LineItems               []Adjustment    `xml:"-" json:"line_items,omitempty"`
+	Transactions            []Transaction   `xml:"-" json:"transactions,omitempty"`
+	CreditPayments          []CreditPayment `xml:"-" json:"credit_payments,omitempty"`
 }
 
 // UnmarshalXML unmarshals invoices and handles intermediary state during unmarshaling
@@ -155,12 +155,37 @@ func (i *Invoice) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	return nil
 }
 
+// Subtotal returns the invoice subtotal.
+func (i Invoice) Subtotal() Money {
+	return NewMoney(i.SubtotalInCents, i.Currency)
+}
+
+// Discount returns the invoice discount.
+func (i Invoice) Discount() Money {
+	return NewMoney(i.DiscountInCents, i.Currency)
+}
+
+// Tax returns the invoice tax.
+func (i Invoice) Tax() Money {
+	return NewMoney(i.TaxInCents, i.Currency)
+}
+
+// Total returns the invoice total.
+func (i Invoice) Total() Money {
+	return NewMoney(i.TotalInCents, i.Currency)
+}
+
+// Balance returns the amount remaining to be paid on the invoice.
+func (i Invoice) Balance() Money {
+	return NewMoney(i.BalanceInCents, i.Currency)
+}
+
 // InvoiceCollection is the data type returned from Preview, Post,
 // MarkFailed, and inside PreviewSubscription, and PreviewSubscriptionChange.
 // In v2.12 this struct will include `credit_invoices`.
 type InvoiceCollection struct {
-	XMLName       xml.Name `xml:"invoice_collection"`
-	ChargeInvoice *Invoice `xml:"-"`
+	XMLName       xml.Name `xml:"invoice_collection" json:"-"`
+	ChargeInvoice *Invoice `xml:"-" json:"charge_invoice,omitempty"`
 }
 
 // UnmarshalXML unmarshals invoices and handles intermediary state during unmarshaling
@@ -254,10 +279,10 @@ type invoiceFields struct {
 
 // OfflinePayment is a payment received outside the system to be recorded in Recurly.
 type OfflinePayment struct {
-	XMLName       xml.Name   `xml:"transaction"`
-	InvoiceNumber int        `xml:"-"`
-	PaymentMethod string     `xml:"payment_method"`
-	CollectedAt   *time.Time `xml:"collected_at,omitempty"`
-	Amount        int        `xml:"amount_in_cents,omitempty"`
-	Description   string     `xml:"description,omitempty"`
+	XMLName       xml.Name   `xml:"transaction" json:"-"`
+	InvoiceNumber int        `xml:"-" json:"invoice_number,omitempty"`
+	PaymentMethod string     `xml:"payment_method" json:"payment_method,omitempty"`
+	CollectedAt   *time.Time `xml:"collected_at,omitempty" json:"collected_at,omitempty"`
+	Amount        int        `xml:"amount_in_cents,omitempty" json:"amount_in_cents,omitempty"`
+	Description   string     `xml:"description,omitempty" json:"description,omitempty"`
 }
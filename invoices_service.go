@@ -220,6 +220,9 @@ func (s *invoicesImpl) RefundVoidOpenAmount(invoiceNumber int, amountInCents int
 // VoidCreditInvoice voids a credit invoice.
 // https://dev.recurly.com/docs/void-credit-invoice
 func (s *invoicesImpl) VoidCreditInvoice(invoiceNumber int) (*Response, *Invoice, error) {
+	if err := s.client.requireAPIVersion(creditInvoicesAPIVersion); err != nil {
+		return nil, nil, err
+	}
 	action := fmt.Sprintf("invoices/%d/void", invoiceNumber)
 	req, err := s.client.newRequest("PUT", action, nil, nil)
 	if err != nil {
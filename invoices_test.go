@@ -2,6 +2,7 @@ package recurly
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
@@ -1088,3 +1089,68 @@ func TestInvoices_RecordPayment(t *testing.T) {
 		t.Fatal("handler not invoked")
 	}
 }
+
+func TestInvoice_JSON(t *testing.T) {
+	invoice := Invoice{
+		AccountCode:     "1",
+		UUID:            "421f7b7d414e4c6792938e7c49d552e9",
+		State:           ChargeInvoiceStatePaid,
+		InvoiceNumber:   1005,
+		SubtotalInCents: 1200,
+		TotalInCents:    1200,
+		Currency:        "USD",
+		CreatedAt:       NewTimeFromString("2011-08-25T12:00:00Z"),
+		NetTerms:        NewInt(0),
+		LineItems: []Adjustment{{
+			AccountCode:       "1",
+			InvoiceNumber:     1005,
+			UUID:              "626db120a84102b1809909071c701c60",
+			UnitAmountInCents: 1200,
+			Quantity:          1,
+			TotalInCents:      1200,
+			Currency:          "USD",
+			Taxable:           NewBool(false),
+			StartDate:         NewTimeFromString("2011-08-25T12:00:00Z"),
+		}},
+		Transactions: []Transaction{{
+			InvoiceNumber: 1005,
+			UUID:          "a13acd8fe4294916b79aec87b7ea441f",
+			Action:        "purchase",
+			AmountInCents: 1200,
+			Currency:      "USD",
+			Status:        "success",
+			Voidable:      NewBool(true),
+			IPAddress:     net.ParseIP("127.0.0.1"),
+			CVVResult:     CVVResult{TransactionResult{Code: "M", Message: "Match"}},
+			Account:       Account{Code: "1", Email: "verena@example.com"},
+		}},
+		CreditPayments: []CreditPayment{{
+			AccountCode:           "1",
+			UUID:                  "451b7b7e2d2a5b81f43c0b462b3eaa05",
+			AmountInCents:         500,
+			OriginalInvoiceNumber: 1001,
+			AppliedToInvoice:      1005,
+		}},
+	}
+
+	b, err := json.Marshal(invoice)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if fields["account_code"] != "1" || fields["invoice_number"] != float64(1005) || fields["net_terms"] != float64(0) {
+		t.Fatalf("unexpected fields: %s", b)
+	} else if fields["closed_at"] != nil || fields["XMLName"] != nil {
+		t.Fatalf("unexpected fields: %s", b)
+	}
+
+	var given Invoice
+	if err := json.Unmarshal(b, &given); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if diff := cmp.Diff(invoice, given); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -0,0 +1,55 @@
+package journal
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+// csvHeader is the header written by WriteCSV.
+var csvHeader = []string{"entry_id", "date", "currency", "invoice_number", "reference", "description", "account", "debit", "credit"}
+
+// WriteCSV writes a row for each line of entries to w, with a header.
+// Dates are written in RFC 3339 format, in UTC, and amounts in the major
+// unit of the currency, such as "12.34". Empty debits and credits are left
+// blank.
+func WriteCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		invoiceNumber := ""
+		if e.InvoiceNumber != 0 {
+			invoiceNumber = strconv.Itoa(e.InvoiceNumber)
+		}
+		date := ""
+		if !e.Date.IsZero() {
+			date = e.Date.UTC().Format(time.RFC3339)
+		}
+
+		for _, l := range e.Lines {
+			if err := cw.Write([]string{
+				e.ID, date, e.Currency, invoiceNumber, e.Reference, e.Description, l.Account,
+				decimal(l.Debit, e.Currency), decimal(l.Credit, e.Currency),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// decimal formats amount in the major unit of currency, or returns an
+// empty string for zero.
+func decimal(amount int, currency string) string {
+	if amount == 0 {
+		return ""
+	}
+	return recurly.NewMoney(amount, currency).Decimal()
+}
@@ -0,0 +1,32 @@
+package journal
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWriteCSV(t *testing.T) {
+	entries := []Entry{
+		{
+			ID: "invoice:1005", Date: time.Date(2018, 3, 15, 10, 0, 0, 0, time.FixedZone("PST", -8*60*60)), Currency: "USD",
+			Description: "Invoice 1005", InvoiceNumber: 1005, Reference: "1005",
+			Lines: []Line{{Account: "accounts_receivable", Debit: 1234}, {Account: "revenue", Credit: 1234}},
+		},
+		{
+			ID: "transaction:t1", Currency: "JPY", Description: "Payment, card", Reference: "t1",
+			Lines: []Line{{Account: "cash", Debit: 500}, {Account: "accounts_receivable", Credit: 500}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, entries); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if buf.String() != "entry_id,date,currency,invoice_number,reference,description,account,debit,credit\n"+
+		"invoice:1005,2018-03-15T18:00:00Z,USD,1005,1005,Invoice 1005,accounts_receivable,12.34,\n"+
+		"invoice:1005,2018-03-15T18:00:00Z,USD,1005,1005,Invoice 1005,revenue,,12.34\n"+
+		"transaction:t1,,JPY,,t1,\"Payment, card\",cash,500,\n"+
+		"transaction:t1,,JPY,,t1,\"Payment, card\",accounts_receivable,,500\n" {
+		t.Fatalf("unexpected csv:\n%s", buf.String())
+	}
+}
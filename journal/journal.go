@@ -0,0 +1,310 @@
+// Package journal turns invoices, transactions and credit payments into
+// balanced double-entry journal entries, for importing into a general
+// ledger.
+//
+// Invoice line items are credited to the revenue account of their
+// AccountingCode, with discounts debited to a contra-revenue account and
+// tax credited to a tax liability account. Line items with a service
+// period, such as subscription charges, are credited to deferred revenue
+// instead and recognized by recognition entries at the end of each month
+// of the period, in proportion to the time elapsed. Credit invoices and
+// credit line items are posted with the signs reversed, so credits for
+// unused time reverse the revenue still to be recognized.
+//
+// Resources are converted in their current state: voided transactions and
+// credit payments produce no entries.
+package journal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+// Accounts names the ledger accounts entries are posted to. Empty names
+// default to the names in DefaultAccounts.
+type Accounts struct {
+	Receivable      string
+	Cash            string
+	CustomerCredit  string
+	DeferredRevenue string
+	Discounts       string
+	Tax             string
+
+	// Revenue is the account for line items without an accounting code.
+	Revenue string
+
+	// RevenueByCode maps accounting codes to revenue accounts. Accounting
+	// codes that are not mapped are used as the account name.
+	RevenueByCode map[string]string
+}
+
+// DefaultAccounts are the default account names.
+var DefaultAccounts = Accounts{
+	Receivable:      "accounts_receivable",
+	Cash:            "cash",
+	CustomerCredit:  "customer_credit",
+	DeferredRevenue: "deferred_revenue",
+	Discounts:       "discounts",
+	Tax:             "sales_tax_payable",
+	Revenue:         "revenue",
+}
+
+// Line is a debit or credit to an account, in the minor unit of the
+// entry's currency. One of Debit and Credit is zero.
+type Line struct {
+	Account string
+	Debit   int
+	Credit  int
+}
+
+// Entry is a journal entry. The debits and credits of its lines balance.
+type Entry struct {
+	// ID identifies the entry, so it can be imported once, such as
+	// "invoice:1005" or "recognition:1005:2018-03".
+	ID          string
+	Date        time.Time
+	Currency    string
+	Description string
+
+	// InvoiceNumber is the invoice the entry relates to, if any.
+	InvoiceNumber int
+
+	// Reference is the invoice number, with its prefix, or the UUID of the
+	// transaction or credit payment.
+	Reference string
+
+	Lines []Line
+}
+
+// Balanced reports whether the debits and credits of e are equal.
+func (e Entry) Balanced() bool {
+	total := 0
+	for _, l := range e.Lines {
+		total += l.Debit - l.Credit
+	}
+	return total == 0
+}
+
+// ErrNoLineItems is returned when converting an invoice without line items.
+// It implements the error interface.
+type ErrNoLineItems struct {
+	InvoiceNumber int
+}
+
+// Error implements the error interface.
+func (e ErrNoLineItems) Error() string {
+	return fmt.Sprintf("journal: invoice %d has no line items", e.InvoiceNumber)
+}
+
+// Journal converts resources to entries.
+type Journal struct {
+	accounts Accounts
+}
+
+// New returns a Journal that posts to accounts.
+func New(accounts Accounts) *Journal {
+	defaults := DefaultAccounts
+	for _, a := range []struct{ name, def *string }{
+		{&accounts.Receivable, &defaults.Receivable},
+		{&accounts.Cash, &defaults.Cash},
+		{&accounts.CustomerCredit, &defaults.CustomerCredit},
+		{&accounts.DeferredRevenue, &defaults.DeferredRevenue},
+		{&accounts.Discounts, &defaults.Discounts},
+		{&accounts.Tax, &defaults.Tax},
+		{&accounts.Revenue, &defaults.Revenue},
+	} {
+		if *a.name == "" {
+			*a.name = *a.def
+		}
+	}
+	return &Journal{accounts: accounts}
+}
+
+// revenue returns the revenue account of an accounting code.
+func (j *Journal) revenue(accountingCode string) string {
+	if accountingCode == "" {
+		return j.accounts.Revenue
+	} else if account, ok := j.accounts.RevenueByCode[accountingCode]; ok {
+		return account
+	}
+	return accountingCode
+}
+
+// Entries converts invoices, and the transactions and credit payments
+// listed with them, to entries ordered by date. Transactions and credit
+// payments listed with several invoices are converted once.
+func (j *Journal) Entries(invoices []recurly.Invoice) ([]Entry, error) {
+	var entries []Entry
+	seen := map[string]bool{}
+	for _, i := range invoices {
+		e, err := j.Invoice(i)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e...)
+
+		for _, t := range i.Transactions {
+			if e, ok := j.Transaction(t); ok && !seen[e.ID] {
+				seen[e.ID] = true
+				entries = append(entries, e)
+			}
+		}
+		for _, c := range i.CreditPayments {
+			if e, ok := j.CreditPayment(c); ok && !seen[e.ID] {
+				seen[e.ID] = true
+				entries = append(entries, e)
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(a, b int) bool {
+		return entries[a].Date.Before(entries[b].Date)
+	})
+	return entries, nil
+}
+
+// Invoice converts an invoice to an entry debiting receivables, or for
+// credit invoices crediting customer credit, followed by its recognition
+// entries. Failed and voided invoices have no entries.
+func (j *Journal) Invoice(i recurly.Invoice) ([]Entry, error) {
+	if i.State == recurly.ChargeInvoiceStateFailed || i.State == recurly.CreditInvoiceStateVoided {
+		return nil, nil
+	} else if len(i.LineItems) == 0 {
+		return nil, ErrNoLineItems{InvoiceNumber: i.InvoiceNumber}
+	}
+
+	ref := i.InvoiceNumberPrefix + strconv.Itoa(i.InvoiceNumber)
+	counter := j.accounts.Receivable
+	if i.Type == recurly.InvoiceTypeCredit {
+		counter = j.accounts.CustomerCredit
+	}
+
+	var date time.Time
+	if i.CreatedAt.Time != nil {
+		date = *i.CreatedAt.Time
+	}
+
+	var l ledger
+	var deferred []recurly.Adjustment
+	for _, a := range i.LineItems {
+		gross := a.TotalInCents - a.TaxInCents + a.DiscountInCents
+		l.post(counter, a.TotalInCents)
+		l.post(j.accounts.Tax, -a.TaxInCents)
+		if servicePeriod(a) {
+			l.post(j.accounts.DeferredRevenue, -(gross - a.DiscountInCents))
+			deferred = append(deferred, a)
+			continue
+		}
+		l.post(j.revenue(a.AccountingCode), -gross)
+		l.post(j.accounts.Discounts, a.DiscountInCents)
+	}
+
+	entries := []Entry{{
+		ID:            "invoice:" + ref,
+		Date:          date,
+		Currency:      recurly.NewMoney(0, i.Currency).Currency,
+		Description:   "Invoice " + ref,
+		InvoiceNumber: i.InvoiceNumber,
+		Reference:     ref,
+		Lines:         l.lines,
+	}}
+	return append(entries, j.recognize(i, ref, deferred)...), nil
+}
+
+// Transaction converts a successful payment to an entry debiting cash and
+// crediting receivables, or a successful refund to one debiting customer
+// credit and crediting cash. It reports false for other transactions.
+func (j *Journal) Transaction(t recurly.Transaction) (Entry, bool) {
+	if t.Status != recurly.TransactionStatusSuccess || t.AmountInCents == 0 {
+		return Entry{}, false
+	}
+
+	var l ledger
+	var description string
+	switch t.Action {
+	case "purchase":
+		l.post(j.accounts.Cash, t.AmountInCents)
+		l.post(j.accounts.Receivable, -t.AmountInCents)
+		description = "Payment"
+	case "refund":
+		l.post(j.accounts.CustomerCredit, t.AmountInCents)
+		l.post(j.accounts.Cash, -t.AmountInCents)
+		description = "Refund"
+	default:
+		return Entry{}, false
+	}
+
+	e := Entry{
+		ID:            "transaction:" + t.UUID,
+		Currency:      recurly.NewMoney(0, t.Currency).Currency,
+		Description:   description,
+		InvoiceNumber: t.InvoiceNumber,
+		Reference:     t.UUID,
+		Lines:         l.lines,
+	}
+	if t.CreatedAt.Time != nil {
+		e.Date = *t.CreatedAt.Time
+	}
+	return e, true
+}
+
+// CreditPayment converts credit applied to an invoice, including gift card
+// credit and credit used to void an invoice, to an entry debiting customer
+// credit and crediting receivables. It reports false for refunds and
+// reductions of credit, as the refund transaction posts them.
+func (j *Journal) CreditPayment(c recurly.CreditPayment) (Entry, bool) {
+	switch {
+	case c.VoidedAt.Time != nil || c.AmountInCents == 0:
+		return Entry{}, false
+	case c.Action != recurly.CreditPaymentActionPayment && c.Action != recurly.CreditPaymentActionGiftCard && c.Action != recurly.CreditPaymentActionWriteOff:
+		return Entry{}, false
+	}
+
+	var l ledger
+	l.post(j.accounts.CustomerCredit, c.AmountInCents)
+	l.post(j.accounts.Receivable, -c.AmountInCents)
+	e := Entry{
+		ID:            "credit_payment:" + c.UUID,
+		Currency:      recurly.NewMoney(0, c.Currency).Currency,
+		Description:   "Credit applied",
+		InvoiceNumber: c.AppliedToInvoice,
+		Reference:     c.UUID,
+		Lines:         l.lines,
+	}
+	if c.CreatedAt.Time != nil {
+		e.Date = *c.CreatedAt.Time
+	}
+	return e, true
+}
+
+// ledger accumulates the lines of an entry, one per account.
+type ledger struct {
+	lines []Line
+}
+
+// post debits amount to account, or credits it if negative.
+func (l *ledger) post(account string, amount int) {
+	if amount == 0 {
+		return
+	}
+
+	i := 0
+	for i < len(l.lines) && l.lines[i].Account != account {
+		i++
+	}
+	if i == len(l.lines) {
+		l.lines = append(l.lines, Line{Account: account})
+	}
+
+	balance := l.lines[i].Debit - l.lines[i].Credit + amount
+	l.lines[i].Debit, l.lines[i].Credit = 0, 0
+	if balance > 0 {
+		l.lines[i].Debit = balance
+	} else {
+		l.lines[i].Credit = -balance
+	}
+}
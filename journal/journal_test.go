@@ -0,0 +1,160 @@
+package journal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2018, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func chargeInvoice() recurly.Invoice {
+	return recurly.Invoice{
+		InvoiceNumberPrefix: "CN",
+		InvoiceNumber:       1005,
+		Type:                recurly.InvoiceTypeCharge,
+		State:               recurly.ChargeInvoiceStatePaid,
+		Currency:            "USD",
+		CreatedAt:           recurly.NewTime(day(time.March, 15)),
+		LineItems: []recurly.Adjustment{
+			{
+				UUID: "a1", AccountingCode: "subs", Currency: "USD",
+				DiscountInCents: 100, TaxInCents: 300, TotalInCents: 3300,
+				StartDate: recurly.NewTime(day(time.March, 15)), EndDate: recurly.NewTime(day(time.April, 15)),
+			},
+			{UUID: "a2", Currency: "USD", TotalInCents: 1000},
+		},
+		Transactions: []recurly.Transaction{
+			{UUID: "t1", Action: "purchase", Status: "success", AmountInCents: 4300, Currency: "USD", InvoiceNumber: 1005, CreatedAt: recurly.NewTime(day(time.March, 15))},
+			{UUID: "t0", Action: "purchase", Status: "declined", AmountInCents: 4300, Currency: "USD"},
+		},
+	}
+}
+
+func TestJournal_Invoice(t *testing.T) {
+	j := New(Accounts{Tax: "vat", RevenueByCode: map[string]string{"subs": "4000"}})
+	entries, err := j.Invoice(chargeInvoice())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Entry{
+		{
+			ID: "invoice:CN1005", Date: day(time.March, 15), Currency: "USD", Description: "Invoice CN1005", InvoiceNumber: 1005, Reference: "CN1005",
+			Lines: []Line{
+				{Account: "accounts_receivable", Debit: 4300},
+				{Account: "vat", Credit: 300},
+				{Account: "deferred_revenue", Credit: 3000},
+				{Account: "revenue", Credit: 1000},
+			},
+		},
+		{
+			ID: "recognition:CN1005:2018-03", Date: day(time.April, 1), Currency: "USD", Description: "Revenue recognized for invoice CN1005 in 2018-03", InvoiceNumber: 1005, Reference: "CN1005",
+			Lines: []Line{
+				{Account: "deferred_revenue", Debit: 1645},
+				{Account: "4000", Credit: 1700},
+				{Account: "discounts", Debit: 55},
+			},
+		},
+		{
+			ID: "recognition:CN1005:2018-04", Date: day(time.April, 15), Currency: "USD", Description: "Revenue recognized for invoice CN1005 in 2018-04", InvoiceNumber: 1005, Reference: "CN1005",
+			Lines: []Line{
+				{Account: "deferred_revenue", Debit: 1355},
+				{Account: "4000", Credit: 1400},
+				{Account: "discounts", Debit: 45},
+			},
+		},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Fatalf("unexpected entries: %#v", entries)
+	}
+	for _, e := range entries {
+		if !e.Balanced() {
+			t.Fatalf("unbalanced entry: %#v", e)
+		}
+	}
+
+	// Credits for unused time reverse deferred revenue.
+	entries, err = j.Invoice(recurly.Invoice{
+		InvoiceNumber: 1006,
+		Type:          recurly.InvoiceTypeCredit,
+		State:         recurly.CreditInvoiceStateClosed,
+		Currency:      "USD",
+		CreatedAt:     recurly.NewTime(day(time.April, 1)),
+		LineItems: []recurly.Adjustment{{
+			UUID: "c1", AccountingCode: "subs", TotalInCents: -1550,
+			StartDate: recurly.NewTime(day(time.April, 1)), EndDate: recurly.NewTime(day(time.April, 15)),
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(entries) != 2 {
+		t.Fatalf("unexpected entries: %#v", entries)
+	} else if !reflect.DeepEqual(entries[0].Lines, []Line{{Account: "customer_credit", Credit: 1550}, {Account: "deferred_revenue", Debit: 1550}}) {
+		t.Fatalf("unexpected lines: %#v", entries[0].Lines)
+	} else if !reflect.DeepEqual(entries[1].Lines, []Line{{Account: "deferred_revenue", Credit: 1550}, {Account: "4000", Debit: 1550}}) {
+		t.Fatalf("unexpected lines: %#v", entries[1].Lines)
+	}
+
+	if entries, err := j.Invoice(recurly.Invoice{InvoiceNumber: 1007, State: recurly.ChargeInvoiceStateFailed}); err != nil || entries != nil {
+		t.Fatalf("unexpected result: %#v, %v", entries, err)
+	} else if _, err := j.Invoice(recurly.Invoice{InvoiceNumber: 1008}); err != (ErrNoLineItems{InvoiceNumber: 1008}) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestJournal_Payments(t *testing.T) {
+	j := New(Accounts{})
+
+	if e, ok := j.Transaction(recurly.Transaction{UUID: "t2", Action: "refund", Status: "success", AmountInCents: 1550, Currency: "usd"}); !ok {
+		t.Fatal("expected refund entry")
+	} else if e.ID != "transaction:t2" || e.Currency != "USD" || !reflect.DeepEqual(e.Lines, []Line{{Account: "customer_credit", Debit: 1550}, {Account: "cash", Credit: 1550}}) {
+		t.Fatalf("unexpected entry: %#v", e)
+	} else if _, ok := j.Transaction(recurly.Transaction{UUID: "t3", Action: "verify", Status: "success", AmountInCents: 100}); ok {
+		t.Fatal("unexpected verify entry")
+	} else if _, ok := j.Transaction(recurly.Transaction{UUID: "t4", Action: "purchase", Status: "void", AmountInCents: 100}); ok {
+		t.Fatal("unexpected void entry")
+	}
+
+	if e, ok := j.CreditPayment(recurly.CreditPayment{UUID: "cp1", Action: "payment", AmountInCents: 500, Currency: "USD", AppliedToInvoice: 1005}); !ok {
+		t.Fatal("expected credit payment entry")
+	} else if e.InvoiceNumber != 1005 || !reflect.DeepEqual(e.Lines, []Line{{Account: "customer_credit", Debit: 500}, {Account: "accounts_receivable", Credit: 500}}) {
+		t.Fatalf("unexpected entry: %#v", e)
+	} else if _, ok := j.CreditPayment(recurly.CreditPayment{UUID: "cp2", Action: "refund", AmountInCents: 500}); ok {
+		t.Fatal("unexpected refund entry")
+	} else if _, ok := j.CreditPayment(recurly.CreditPayment{UUID: "cp3", Action: "payment", AmountInCents: 500, VoidedAt: recurly.NewTime(day(time.May, 1))}); ok {
+		t.Fatal("unexpected voided entry")
+	}
+}
+
+func TestJournal_Entries(t *testing.T) {
+	// The payment is listed with both invoices.
+	first, second := chargeInvoice(), chargeInvoice()
+	second.InvoiceNumber = 1006
+	second.CreatedAt = recurly.NewTime(day(time.March, 1))
+
+	entries, err := New(Accounts{}).Entries([]recurly.Invoice{first, second})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []string
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{
+		"invoice:CN1006",
+		"invoice:CN1005",
+		"transaction:t1",
+		"recognition:CN1005:2018-03",
+		"recognition:CN1006:2018-03",
+		"recognition:CN1005:2018-04",
+		"recognition:CN1006:2018-04",
+	}) {
+		t.Fatalf("unexpected entries: %v", ids)
+	}
+}
@@ -0,0 +1,99 @@
+package journal
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+// servicePeriod reports whether a is earned over a service period, rather
+// than when invoiced.
+func servicePeriod(a recurly.Adjustment) bool {
+	return a.StartDate.Time != nil && a.EndDate.Time != nil && a.EndDate.Time.After(*a.StartDate.Time)
+}
+
+// recognition is the revenue of line items recognized in a month.
+type recognition struct {
+	end    time.Time
+	ledger ledger
+}
+
+// recognize returns the entries recognizing the deferred line items of an
+// invoice: one at the end of each month of their service periods, or at
+// the end of the last period, moving the revenue and discount earned
+// during the month out of deferred revenue.
+func (j *Journal) recognize(i recurly.Invoice, ref string, deferred []recurly.Adjustment) []Entry {
+	var months []string
+	byMonth := map[string]*recognition{}
+	for _, a := range deferred {
+		gross := a.TotalInCents - a.TaxInCents + a.DiscountInCents
+		for _, p := range split(*a.StartDate.Time, *a.EndDate.Time, gross, a.DiscountInCents) {
+			month := p.start.Format("2006-01")
+			r, ok := byMonth[month]
+			if !ok {
+				r = &recognition{}
+				byMonth[month] = r
+				months = append(months, month)
+			}
+			if p.end.After(r.end) {
+				r.end = p.end
+			}
+			r.ledger.post(j.accounts.DeferredRevenue, p.gross-p.discount)
+			r.ledger.post(j.revenue(a.AccountingCode), -p.gross)
+			r.ledger.post(j.accounts.Discounts, p.discount)
+		}
+	}
+
+	sort.Strings(months)
+	var entries []Entry
+	for _, month := range months {
+		r := byMonth[month]
+		if len(r.ledger.lines) == 0 {
+			continue
+		}
+		entries = append(entries, Entry{
+			ID:            "recognition:" + ref + ":" + month,
+			Date:          r.end,
+			Currency:      recurly.NewMoney(0, i.Currency).Currency,
+			Description:   "Revenue recognized for invoice " + ref + " in " + month,
+			InvoiceNumber: i.InvoiceNumber,
+			Reference:     ref,
+			Lines:         r.ledger.lines,
+		})
+	}
+	return entries
+}
+
+// portion is the part of a service period within a month, and the amounts
+// earned in it.
+type portion struct {
+	start, end      time.Time
+	gross, discount int
+}
+
+// split divides the period from start until end at month boundaries, in
+// start's location, and allocates gross and discount to each part in
+// proportion to its length. The last part gets any rounding remainder.
+func split(start, end time.Time, gross, discount int) []portion {
+	total := end.Sub(start)
+
+	var portions []portion
+	allocatedGross, allocatedDiscount := 0, 0
+	for from := start; from.Before(end); {
+		to := time.Date(from.Year(), from.Month()+1, 1, 0, 0, 0, 0, from.Location())
+		if !to.Before(end) {
+			portions = append(portions, portion{start: from, end: end, gross: gross - allocatedGross, discount: discount - allocatedDiscount})
+			break
+		}
+
+		share := float64(to.Sub(from)) / float64(total)
+		p := portion{start: from, end: to, gross: int(math.Round(float64(gross) * share)), discount: int(math.Round(float64(discount) * share))}
+		allocatedGross += p.gross
+		allocatedDiscount += p.discount
+		portions = append(portions, p)
+		from = to
+	}
+	return portions
+}
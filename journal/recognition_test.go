@@ -0,0 +1,37 @@
+package journal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSplit(t *testing.T) {
+	// A year starting mid-month spans thirteen calendar months, and the
+	// rounding remainder goes to the last.
+	portions := split(day(time.January, 15), day(time.January, 15).AddDate(1, 0, 0), 1000, 10)
+	if len(portions) != 13 {
+		t.Fatalf("unexpected portions: %d", len(portions))
+	}
+
+	gross, discount := 0, 0
+	for i, p := range portions {
+		gross += p.gross
+		discount += p.discount
+		if i > 0 && !p.start.Equal(portions[i-1].end) {
+			t.Fatalf("portions are not contiguous: %#v", portions)
+		}
+	}
+	if gross != 1000 || discount != 10 {
+		t.Fatalf("unexpected totals: %d, %d", gross, discount)
+	} else if p := portions[0]; p.gross != 47 || !p.end.Equal(day(time.February, 1)) {
+		t.Fatalf("unexpected first portion: %#v", p)
+	}
+
+	// Periods within a month are a single portion.
+	if portions := split(day(time.March, 1), day(time.March, 8), -700, 0); !reflect.DeepEqual(portions, []portion{
+		{start: day(time.March, 1), end: day(time.March, 8), gross: -700},
+	}) {
+		t.Fatalf("unexpected portions: %#v", portions)
+	}
+}
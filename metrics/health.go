@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+// TrialConversion counts the trials that ended in a period, and those that
+// converted to paid subscriptions.
+type TrialConversion struct {
+	Trials    int
+	Converted int
+}
+
+// Rate returns the fraction of trials that converted, or 0 if there were
+// none.
+func (t TrialConversion) Rate() float64 {
+	if t.Trials == 0 {
+		return 0
+	}
+	return float64(t.Converted) / float64(t.Trials)
+}
+
+// TrialConversion counts the trials that ended from begin until end. A
+// trial converted if its subscription did not expire when the trial
+// ended. Trials ending after the Dataset was loaded are not counted.
+func (d *Dataset) TrialConversion(begin, end time.Time) TrialConversion {
+	var t TrialConversion
+	for _, s := range d.Subscriptions {
+		ends := s.TrialEndsAt.Time
+		if ends == nil || ends.Before(begin) || !ends.Before(end) || ends.After(d.At) {
+			continue
+		}
+
+		t.Trials++
+		if s.ExpiresAt.Time == nil || s.ExpiresAt.Time.After(*ends) {
+			t.Converted++
+		}
+	}
+	return t
+}
+
+// PastDue is the outstanding balance of past-due invoices in a currency.
+type PastDue struct {
+	Currency string
+	Invoices int
+	Accounts int
+	Balance  recurly.Money
+
+	// OldestDueOn is the earliest due date of the invoices.
+	OldestDueOn time.Time
+}
+
+// PastDue returns the balance of past-due invoices in each currency.
+func (d *Dataset) PastDue() map[string]PastDue {
+	exposure := map[string]PastDue{}
+	accounts := map[string]map[string]bool{}
+	for _, i := range d.Invoices {
+		if i.State != recurly.ChargeInvoiceStatePastDue {
+			continue
+		}
+
+		currency := recurly.NewMoney(0, i.Currency).Currency
+		p, ok := exposure[currency]
+		if !ok {
+			p = PastDue{Currency: currency, Balance: recurly.NewMoney(0, currency)}
+			accounts[currency] = map[string]bool{}
+		}
+
+		p.Invoices++
+		p.Balance.Amount += i.BalanceInCents
+		if !accounts[currency][i.AccountCode] {
+			accounts[currency][i.AccountCode] = true
+			p.Accounts++
+		}
+		if due := i.DueOn.Time; due != nil && (p.OldestDueOn.IsZero() || due.Before(p.OldestDueOn)) {
+			p.OldestDueOn = *due
+		}
+		exposure[currency] = p
+	}
+	return exposure
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+func TestDataset_TrialConversion(t *testing.T) {
+	d := &Dataset{
+		At: date(time.March, 1),
+		Subscriptions: []recurly.Subscription{
+			// Converted.
+			{TrialEndsAt: recurly.NewTime(date(time.January, 10))},
+			// Canceled during the trial, expiring when it ended.
+			{TrialEndsAt: recurly.NewTime(date(time.January, 20)), ExpiresAt: recurly.NewTime(date(time.January, 20))},
+			// Converted and churned later.
+			{TrialEndsAt: recurly.NewTime(date(time.January, 25)), ExpiresAt: recurly.NewTime(date(time.February, 25))},
+			// Ended outside the period.
+			{TrialEndsAt: recurly.NewTime(date(time.February, 1))},
+			// No trial.
+			{},
+		},
+	}
+
+	if tc := d.TrialConversion(date(time.January, 1), date(time.February, 1)); tc != (TrialConversion{Trials: 3, Converted: 2}) {
+		t.Fatalf("unexpected conversion: %#v", tc)
+	} else if r := tc.Rate(); r != 2.0/3 {
+		t.Fatalf("unexpected rate: %v", r)
+	}
+
+	// Trials ending after the data was loaded are not known yet.
+	d.At = date(time.January, 15)
+	if tc := d.TrialConversion(date(time.January, 1), date(time.February, 1)); tc != (TrialConversion{Trials: 1, Converted: 1}) {
+		t.Fatalf("unexpected conversion: %#v", tc)
+	} else if r := (TrialConversion{}).Rate(); r != 0 {
+		t.Fatalf("unexpected rate: %v", r)
+	}
+}
+
+func TestDataset_PastDue(t *testing.T) {
+	d := &Dataset{
+		Invoices: []recurly.Invoice{
+			{AccountCode: "a", State: "past_due", Currency: "USD", BalanceInCents: 1000, DueOn: recurly.NewTime(date(time.February, 1))},
+			{AccountCode: "a", State: "past_due", Currency: "USD", BalanceInCents: 500, DueOn: recurly.NewTime(date(time.January, 1))},
+			{AccountCode: "b", State: "past_due", Currency: "USD", BalanceInCents: 250},
+			{AccountCode: "c", State: "past_due", Currency: "EUR", BalanceInCents: 700, DueOn: recurly.NewTime(date(time.March, 1))},
+			{AccountCode: "d", State: "paid", Currency: "USD", BalanceInCents: 0},
+		},
+	}
+
+	if exposure := d.PastDue(); !reflect.DeepEqual(exposure, map[string]PastDue{
+		"USD": {Currency: "USD", Invoices: 3, Accounts: 2, Balance: recurly.NewMoney(1750, "USD"), OldestDueOn: date(time.January, 1)},
+		"EUR": {Currency: "EUR", Invoices: 1, Accounts: 1, Balance: recurly.NewMoney(700, "EUR"), OldestDueOn: date(time.March, 1)},
+	}) {
+		t.Fatalf("unexpected exposure: %#v", exposure)
+	}
+}
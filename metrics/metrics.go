@@ -0,0 +1,184 @@
+// Package metrics computes revenue and churn metrics, such as MRR, ARR,
+// MRR movements, trial conversion and past-due exposure, from
+// subscriptions, plans and invoices listed from the API or a local mirror.
+//
+// Subscription amounts are normalized to a month using the interval of
+// their plan, so plans billed every 12 months contribute a twelfth of
+// their amount and plans billed every 7 days contribute 365/84 of theirs.
+// Subscriptions on plans that are not known are normalized using their
+// current period.
+// Amounts are before discounts and taxes, and are kept per currency; use
+// Convert to total them in a single currency.
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/kmikiy/recurly"
+	"github.com/kmikiy/recurly/mirror"
+)
+
+// Dataset holds the resources metrics are computed from.
+type Dataset struct {
+	// At is when the resources were listed. Trials ending after At are not
+	// yet known to have converted.
+	At time.Time
+
+	// Subscriptions should include expired subscriptions, so churn can be
+	// measured.
+	Subscriptions []recurly.Subscription
+
+	// Plans are used for the billing interval of subscriptions.
+	Plans []recurly.Plan
+
+	// Invoices are used for past-due exposure. Only past-due invoices are
+	// needed.
+	Invoices []recurly.Invoice
+}
+
+// ErrList is returned by Load when listing resources fails. It implements
+// the error interface.
+type ErrList struct {
+	Response *recurly.Response
+}
+
+// Error implements the error interface.
+func (e ErrList) Error() string {
+	return fmt.Sprintf("metrics: %s %s: %d", e.Response.Request.Method, e.Response.Request.URL.Path, e.Response.StatusCode)
+}
+
+// Load lists the live and expired subscriptions, plans and past-due
+// invoices of a site.
+func Load(client *recurly.Client) (*Dataset, error) {
+	d := &Dataset{At: time.Now()}
+
+	for _, state := range []string{recurly.SubscriptionStateLive, recurly.SubscriptionStateExpired} {
+		if err := list(recurly.Params{"state": state}, func(params recurly.Params) (*recurly.Response, error) {
+			resp, subs, err := client.Subscriptions.List(params)
+			d.Subscriptions = append(d.Subscriptions, subs...)
+			return resp, err
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := list(recurly.Params{}, func(params recurly.Params) (*recurly.Response, error) {
+		resp, plans, err := client.Plans.List(params)
+		d.Plans = append(d.Plans, plans...)
+		return resp, err
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := list(recurly.Params{"state": recurly.ChargeInvoiceStatePastDue}, func(params recurly.Params) (*recurly.Response, error) {
+		resp, invoices, err := client.Invoices.List(params)
+		d.Invoices = append(d.Invoices, invoices...)
+		return resp, err
+	}); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// FromMirror returns a Dataset of the subscriptions and invoices in m.
+// Plans are not mirrored, so they must be given.
+func FromMirror(m *mirror.Mirror, plans []recurly.Plan) (*Dataset, error) {
+	subs, err := m.Subscriptions()
+	if err != nil {
+		return nil, err
+	}
+	invoices, err := m.Invoices()
+	if err != nil {
+		return nil, err
+	}
+	return &Dataset{At: time.Now(), Subscriptions: subs, Plans: plans, Invoices: invoices}, nil
+}
+
+// list calls fn for each page of resources.
+func list(params recurly.Params, fn func(recurly.Params) (*recurly.Response, error)) error {
+	params["per_page"] = 200
+	for {
+		resp, err := fn(params)
+		if err != nil {
+			return err
+		} else if resp.IsError() {
+			return ErrList{Response: resp}
+		}
+
+		next := resp.Next()
+		if next == "" {
+			return nil
+		}
+		params["cursor"] = next
+	}
+}
+
+// monthly returns the factor converting an amount billed every plan
+// interval to a month.
+func monthly(plan recurly.Plan) (float64, bool) {
+	if plan.IntervalLength <= 0 {
+		return 0, false
+	}
+	switch plan.IntervalUnit {
+	case recurly.IntervalUnitMonths:
+		return 1 / float64(plan.IntervalLength), true
+	case recurly.IntervalUnitDays:
+		return 365.0 / 12 / float64(plan.IntervalLength), true
+	}
+	return 0, false
+}
+
+// amount returns the plan and fixed add-on amount billed each interval.
+// Usage add-ons are billed in arrears and are not included.
+func amount(s recurly.Subscription) int {
+	total := s.UnitAmountInCents * s.Quantity
+	for _, a := range s.SubscriptionAddOns {
+		if a.Type == "usage" {
+			continue
+		}
+		total += a.UnitAmountInCents * a.Quantity
+	}
+	return total
+}
+
+// ErrNoRate is returned by Convert when there is no exchange rate for a
+// currency. It implements the error interface.
+type ErrNoRate struct {
+	Currency string
+}
+
+// Error implements the error interface.
+func (e ErrNoRate) Error() string {
+	return fmt.Sprintf("metrics: no exchange rate for %s", e.Currency)
+}
+
+// Convert totals amounts in currency. rates holds the value of one major
+// unit of each other currency in currency, such as {"EUR": 1.17} to
+// convert euros to dollars. Currencies are matched case-insensitively, and
+// amounts are rounded to the minor unit.
+func Convert(amounts map[string]recurly.Money, currency string, rates map[string]float64) (recurly.Money, error) {
+	currency = recurly.NewMoney(0, currency).Currency
+	normalized := map[string]float64{}
+	for c, r := range rates {
+		normalized[strings.ToUpper(c)] = r
+	}
+
+	total := 0.0
+	for c, m := range amounts {
+		c = strings.ToUpper(c)
+		rate := 1.0
+		if c != currency {
+			r, ok := normalized[c]
+			if !ok {
+				return recurly.Money{}, ErrNoRate{Currency: c}
+			}
+			rate = r
+		}
+		major := float64(m.Amount) / math.Pow10(recurly.CurrencyDecimals(c))
+		total += major * rate * math.Pow10(recurly.CurrencyDecimals(currency))
+	}
+	return recurly.NewMoney(int(math.Round(total)), currency), nil
+}
@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kmikiy/recurly"
+)
+
+func TestLoad(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := recurly.NewClient("test", "abc", nil, recurly.WithBaseURL(server.URL))
+
+	var states []string
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		state := r.URL.Query().Get("state")
+		states = append(states, state)
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `<subscriptions><subscription><plan><plan_code>gold</plan_code></plan><uuid>%s</uuid></subscription></subscriptions>`, state)
+	})
+	mux.HandleFunc("/v2/plans", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<plans><plan><plan_code>gold</plan_code><plan_interval_length>1</plan_interval_length><plan_interval_unit>months</plan_interval_unit></plan></plans>`)
+	})
+	fail := true
+	mux.HandleFunc("/v2/invoices", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != "past_due" {
+			t.Fatalf("unexpected query: %s", r.URL.RawQuery)
+		} else if fail {
+			w.WriteHeader(500)
+			return
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<invoices><invoice><invoice_number type="integer">1001</invoice_number><state>past_due</state></invoice></invoices>`)
+	})
+
+	if _, err := Load(client); err == nil || err.Error() != "metrics: GET /v2/invoices: 500" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(states) != 2 || states[0] != "live" || states[1] != "expired" {
+		t.Fatalf("unexpected states: %v", states)
+	}
+
+	fail = false
+	states = nil
+	d, err := Load(client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(d.Subscriptions) != 2 || d.Subscriptions[1].UUID != "expired" {
+		t.Fatalf("unexpected subscriptions: %#v", d.Subscriptions)
+	} else if len(d.Plans) != 1 || d.Plans[0].IntervalUnit != "months" {
+		t.Fatalf("unexpected plans: %#v", d.Plans)
+	} else if len(d.Invoices) != 1 || d.Invoices[0].InvoiceNumber != 1001 {
+		t.Fatalf("unexpected invoices: %#v", d.Invoices)
+	} else if d.At.IsZero() {
+		t.Fatal("expected At to be set")
+	}
+}
+
+func TestMonthly(t *testing.T) {
+	for _, tt := range []struct {
+		unit   string
+		length int
+		factor float64
+		ok     bool
+	}{
+		{unit: "months", length: 1, factor: 1, ok: true},
+		{unit: "months", length: 12, factor: 1.0 / 12, ok: true},
+		{unit: "days", length: 7, factor: 365.0 / 84, ok: true},
+		{unit: "months", length: 0},
+		{unit: "years", length: 1},
+	} {
+		if f, ok := monthly(recurly.Plan{IntervalUnit: tt.unit, IntervalLength: tt.length}); math.Abs(f-tt.factor) > 1e-9 || ok != tt.ok {
+			t.Fatalf("unexpected factor for %d %s: %v, %v", tt.length, tt.unit, f, ok)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	amounts := map[string]recurly.Money{
+		"USD": recurly.NewMoney(1000, "USD"),
+		"EUR": recurly.NewMoney(1000, "EUR"),
+		"JPY": recurly.NewMoney(1000, "JPY"),
+	}
+
+	if m, err := Convert(amounts, "usd", map[string]float64{"EUR": 1.17, "JPY": 0.009}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if m != recurly.NewMoney(3070, "USD") {
+		t.Fatalf("unexpected total: %v", m)
+	} else if _, err := Convert(amounts, "USD", map[string]float64{"EUR": 1.17}); err != (ErrNoRate{Currency: "JPY"}) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Currencies are matched case-insensitively.
+	lower := map[string]recurly.Money{"usd": {Amount: 1000, Currency: "usd"}, "eur": {Amount: 1000, Currency: "eur"}}
+	if m, err := Convert(lower, "USD", map[string]float64{"eur": 1.17}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if m != recurly.NewMoney(2170, "USD") {
+		t.Fatalf("unexpected total: %v", m)
+	}
+}
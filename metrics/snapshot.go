@@ -0,0 +1,249 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+// Item is the monthly recurring revenue of a subscription.
+type Item struct {
+	SubscriptionUUID string `json:"subscription_uuid"`
+	AccountCode      string `json:"account_code"`
+	PlanCode         string `json:"plan_code"`
+	Currency         string `json:"currency"`
+	MRRInCents       int    `json:"mrr_in_cents"`
+}
+
+// Snapshot is the monthly recurring revenue of each subscription at a
+// point in time. Snapshots can be saved as JSON, to compare with later
+// ones.
+type Snapshot struct {
+	At    time.Time `json:"at"`
+	Items []Item    `json:"items"`
+
+	// Skipped are the UUIDs of subscriptions billed at At that are left
+	// out because neither their plan nor their current period gives a
+	// billing interval.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// Snapshot returns the recurring revenue at a point in time. Subscriptions
+// count from when they are activated, or their trial ends, until they
+// expire, and not while paused. Canceled subscriptions count until they
+// expire at the end of their term.
+//
+// Subscriptions hold their current amounts, so snapshots of the same
+// Dataset at different times only differ by subscriptions starting and
+// ending. Compare snapshots of Datasets loaded at each time to also see
+// upgrades and downgrades.
+//
+// Subscriptions on a plan that is not in the Dataset are normalized using
+// the length of their current period instead, and are skipped if it is not
+// known.
+func (d *Dataset) Snapshot(at time.Time) Snapshot {
+	factors := map[string]float64{}
+	for _, p := range d.Plans {
+		if f, ok := monthly(p); ok {
+			factors[p.Code] = f
+		}
+	}
+
+	snap := Snapshot{At: at, Items: []Item{}}
+	for _, s := range d.Subscriptions {
+		if !recurring(s, at) {
+			continue
+		}
+
+		f, ok := factors[s.Plan.Code]
+		if !ok {
+			f, ok = periodMonthly(s)
+		}
+		if !ok {
+			snap.Skipped = append(snap.Skipped, s.UUID)
+			continue
+		}
+		snap.Items = append(snap.Items, Item{
+			SubscriptionUUID: s.UUID,
+			AccountCode:      s.AccountCode,
+			PlanCode:         s.Plan.Code,
+			Currency:         recurly.NewMoney(0, s.Currency).Currency,
+			MRRInCents:       int(math.Round(float64(amount(s)) * f)),
+		})
+	}
+	return snap
+}
+
+// periodMonthly returns the factor normalizing the amount of s to a month
+// using the length of its current period, in months if it is a whole
+// number of months give or take a few days for months of different
+// lengths, and in days otherwise.
+func periodMonthly(s recurly.Subscription) (float64, bool) {
+	start, end := s.CurrentPeriodStartedAt.Time, s.CurrentPeriodEndsAt.Time
+	if start == nil || end == nil || !end.After(*start) {
+		return 0, false
+	}
+
+	months := (end.Year()-start.Year())*12 + int(end.Month()-start.Month())
+	if months > 0 {
+		if d := end.Sub(start.AddDate(0, months, 0)); d > -72*time.Hour && d < 72*time.Hour {
+			return 1 / float64(months), true
+		}
+	}
+	return 365.0 / 12 / (end.Sub(*start).Hours() / 24), true
+}
+
+// recurring reports whether s is billed at a point in time.
+func recurring(s recurly.Subscription, at time.Time) bool {
+	switch {
+	case s.ActivatedAt.Time == nil || s.ActivatedAt.Time.After(at):
+		return false
+	case s.ExpiresAt.Time != nil && !s.ExpiresAt.Time.After(at):
+		return false
+	case s.TrialEndsAt.Time != nil && s.TrialEndsAt.Time.After(at):
+		return false
+	case s.PausedAt.Time != nil && !s.PausedAt.Time.After(at) && (s.ResumeAt.Time == nil || s.ResumeAt.Time.After(at)):
+		return false
+	}
+	return true
+}
+
+// MRR returns the monthly recurring revenue in each currency.
+func (s Snapshot) MRR() map[string]recurly.Money {
+	mrr := map[string]recurly.Money{}
+	for _, item := range s.Items {
+		m := mrr[item.Currency]
+		mrr[item.Currency] = recurly.NewMoney(m.Amount+item.MRRInCents, item.Currency)
+	}
+	return mrr
+}
+
+// ARR returns the annual recurring revenue in each currency, which is
+// twelve times the MRR.
+func (s Snapshot) ARR() map[string]recurly.Money {
+	arr := s.MRR()
+	for c, m := range arr {
+		arr[c] = m.Mul(12)
+	}
+	return arr
+}
+
+// PlanMRR is the monthly recurring revenue of a plan in a currency.
+type PlanMRR struct {
+	PlanCode      string
+	Currency      string
+	Subscriptions int
+	MRR           recurly.Money
+}
+
+// ByPlan returns the monthly recurring revenue of each plan and currency,
+// ordered by plan code and currency.
+func (s Snapshot) ByPlan() []PlanMRR {
+	index := map[[2]string]int{}
+	var plans []PlanMRR
+	for _, item := range s.Items {
+		key := [2]string{item.PlanCode, item.Currency}
+		i, ok := index[key]
+		if !ok {
+			i = len(plans)
+			index[key] = i
+			plans = append(plans, PlanMRR{PlanCode: item.PlanCode, Currency: item.Currency, MRR: recurly.NewMoney(0, item.Currency)})
+		}
+		plans[i].Subscriptions++
+		plans[i].MRR.Amount += item.MRRInCents
+	}
+
+	sort.Slice(plans, func(i, j int) bool {
+		if plans[i].PlanCode != plans[j].PlanCode {
+			return plans[i].PlanCode < plans[j].PlanCode
+		}
+		return plans[i].Currency < plans[j].Currency
+	})
+	return plans
+}
+
+// Movement explains the change in monthly recurring revenue in a currency
+// between two snapshots. Start + New + Expansion - Contraction - Churned
+// equals End.
+type Movement struct {
+	Currency string
+	Start    recurly.Money
+	End      recurly.Money
+
+	// New is the revenue of accounts with none at the start.
+	New recurly.Money
+
+	// Expansion and Contraction are the increases and decreases of
+	// accounts with revenue at both the start and the end.
+	Expansion   recurly.Money
+	Contraction recurly.Money
+
+	// Churned is the revenue at the start of accounts with none at the end.
+	Churned recurly.Money
+
+	NewAccounts     int
+	ChurnedAccounts int
+}
+
+// Net returns the change in revenue from start to end.
+func (m Movement) Net() recurly.Money {
+	return recurly.NewMoney(m.End.Amount-m.Start.Amount, m.Currency)
+}
+
+// Movements compares the revenue of each account in two snapshots and
+// returns the movements in each currency.
+func Movements(start, end Snapshot) map[string]Movement {
+	before, after := byAccount(start), byAccount(end)
+
+	movements := map[string]Movement{}
+	movement := func(currency string) Movement {
+		if m, ok := movements[currency]; ok {
+			return m
+		}
+		zero := recurly.NewMoney(0, currency)
+		return Movement{Currency: currency, Start: zero, End: zero, New: zero, Expansion: zero, Contraction: zero, Churned: zero}
+	}
+
+	for key, b := range before {
+		m := movement(key[0])
+		m.Start.Amount += b
+		a := after[key]
+		switch {
+		case a == 0:
+			m.Churned.Amount += b
+			m.ChurnedAccounts++
+		case a > b:
+			m.Expansion.Amount += a - b
+		case a < b:
+			m.Contraction.Amount += b - a
+		}
+		movements[key[0]] = m
+	}
+	for key, a := range after {
+		m := movement(key[0])
+		m.End.Amount += a
+		if before[key] == 0 {
+			m.New.Amount += a
+			m.NewAccounts++
+		}
+		movements[key[0]] = m
+	}
+	return movements
+}
+
+// byAccount returns the revenue of each account, keyed by currency and
+// account code. Subscriptions without an account code are counted as
+// separate accounts.
+func byAccount(s Snapshot) map[[2]string]int {
+	totals := map[[2]string]int{}
+	for _, item := range s.Items {
+		account := item.AccountCode
+		if account == "" {
+			account = "subscription:" + item.SubscriptionUUID
+		}
+		totals[[2]string{item.Currency, account}] += item.MRRInCents
+	}
+	return totals
+}
@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+func date(month time.Month, day int) time.Time {
+	return time.Date(2018, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func dataset() *Dataset {
+	return &Dataset{
+		At: date(time.March, 1),
+		Plans: []recurly.Plan{
+			{Code: "gold", IntervalUnit: "months", IntervalLength: 1},
+			{Code: "annual", IntervalUnit: "months", IntervalLength: 12},
+		},
+		Subscriptions: []recurly.Subscription{
+			{
+				UUID: "1", AccountCode: "a", Plan: recurly.NestedPlan{Code: "gold"}, Currency: "USD",
+				UnitAmountInCents: 1000, Quantity: 2, ActivatedAt: recurly.NewTime(date(time.December, 1).AddDate(-1, 0, 0)),
+				SubscriptionAddOns: []recurly.SubscriptionAddOn{
+					{Code: "support", UnitAmountInCents: 500, Quantity: 1},
+					{Code: "api_calls", Type: "usage", UnitAmountInCents: 1, Quantity: 1},
+				},
+			},
+			{
+				UUID: "2", AccountCode: "b", Plan: recurly.NestedPlan{Code: "annual"}, Currency: "eur",
+				UnitAmountInCents: 12000, Quantity: 1, ActivatedAt: recurly.NewTime(date(time.December, 1).AddDate(-1, 0, 0)),
+			},
+			{
+				UUID: "3", AccountCode: "c", Plan: recurly.NestedPlan{Code: "gold"}, Currency: "USD",
+				UnitAmountInCents: 1000, Quantity: 1, ActivatedAt: recurly.NewTime(date(time.January, 15)),
+			},
+			{
+				UUID: "4", AccountCode: "d", Plan: recurly.NestedPlan{Code: "gold"}, Currency: "USD",
+				UnitAmountInCents: 1000, Quantity: 1, ActivatedAt: recurly.NewTime(date(time.November, 1).AddDate(-1, 0, 0)),
+				ExpiresAt: recurly.NewTime(date(time.January, 10)),
+			},
+			{
+				UUID: "5", AccountCode: "e", Plan: recurly.NestedPlan{Code: "gold"}, Currency: "USD",
+				UnitAmountInCents: 1000, Quantity: 1, ActivatedAt: recurly.NewTime(date(time.January, 1)),
+				TrialEndsAt: recurly.NewTime(date(time.February, 15)),
+			},
+			{
+				UUID: "6", AccountCode: "f", Plan: recurly.NestedPlan{Code: "gold"}, Currency: "USD",
+				UnitAmountInCents: 1000, Quantity: 1, ActivatedAt: recurly.NewTime(date(time.November, 1).AddDate(-1, 0, 0)),
+				PausedAt: recurly.NewTime(date(time.December, 20).AddDate(-1, 0, 0)), ResumeAt: recurly.NewTime(date(time.March, 1)),
+			},
+		},
+	}
+}
+
+func TestDataset_Snapshot(t *testing.T) {
+	d := dataset()
+	snap := d.Snapshot(date(time.January, 1))
+	if mrr := snap.MRR(); !reflect.DeepEqual(mrr, map[string]recurly.Money{
+		"USD": recurly.NewMoney(3500, "USD"),
+		"EUR": recurly.NewMoney(1000, "EUR"),
+	}) {
+		t.Fatalf("unexpected mrr: %v", mrr)
+	} else if arr := snap.ARR(); arr["USD"] != recurly.NewMoney(42000, "USD") {
+		t.Fatalf("unexpected arr: %v", arr)
+	} else if plans := snap.ByPlan(); !reflect.DeepEqual(plans, []PlanMRR{
+		{PlanCode: "annual", Currency: "EUR", Subscriptions: 1, MRR: recurly.NewMoney(1000, "EUR")},
+		{PlanCode: "gold", Currency: "USD", Subscriptions: 2, MRR: recurly.NewMoney(3500, "USD")},
+	}) {
+		t.Fatalf("unexpected plans: %#v", plans)
+	}
+
+	// Snapshots round trip through JSON.
+	b, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Snapshot
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(decoded, snap) {
+		t.Fatalf("unexpected snapshot: %#v", decoded)
+	}
+
+	// Unknown plans fall back to the current period, or are skipped.
+	d.Subscriptions = append(d.Subscriptions, recurly.Subscription{
+		UUID: "7", Plan: recurly.NestedPlan{Code: "silver"}, Currency: "USD", UnitAmountInCents: 3000, Quantity: 1,
+		ActivatedAt:            recurly.NewTime(date(time.January, 1)),
+		CurrentPeriodStartedAt: recurly.NewTime(date(time.January, 31)),
+		CurrentPeriodEndsAt:    recurly.NewTime(date(time.April, 30)),
+	}, recurly.Subscription{
+		UUID: "8", Plan: recurly.NestedPlan{Code: "bronze"}, Currency: "USD", UnitAmountInCents: 3000, Quantity: 1,
+		ActivatedAt: recurly.NewTime(date(time.January, 1)),
+	})
+	snap = d.Snapshot(date(time.January, 1))
+	if mrr := snap.MRR(); mrr["USD"] != recurly.NewMoney(4500, "USD") {
+		t.Fatalf("unexpected mrr: %v", mrr)
+	} else if !reflect.DeepEqual(snap.Skipped, []string{"8"}) {
+		t.Fatalf("unexpected skipped: %v", snap.Skipped)
+	}
+}
+
+func TestMovements(t *testing.T) {
+	start := dataset().Snapshot(date(time.January, 1))
+
+	// Account a upgrades between the two datasets.
+	later := dataset()
+	later.Subscriptions[0].Quantity = 3
+	end := later.Snapshot(date(time.February, 1))
+
+	movements := Movements(start, end)
+	usd := movements["USD"]
+	if usd.Start != recurly.NewMoney(3500, "USD") || usd.End != recurly.NewMoney(4500, "USD") {
+		t.Fatalf("unexpected start and end: %v, %v", usd.Start, usd.End)
+	} else if usd.New != recurly.NewMoney(1000, "USD") || usd.NewAccounts != 1 {
+		t.Fatalf("unexpected new: %v, %d", usd.New, usd.NewAccounts)
+	} else if usd.Churned != recurly.NewMoney(1000, "USD") || usd.ChurnedAccounts != 1 {
+		t.Fatalf("unexpected churned: %v, %d", usd.Churned, usd.ChurnedAccounts)
+	} else if usd.Expansion != recurly.NewMoney(1000, "USD") || !usd.Contraction.IsZero() {
+		t.Fatalf("unexpected expansion and contraction: %v, %v", usd.Expansion, usd.Contraction)
+	} else if usd.Net() != recurly.NewMoney(1000, "USD") {
+		t.Fatalf("unexpected net: %v", usd.Net())
+	} else if eur := movements["EUR"]; eur.Net() != recurly.NewMoney(0, "EUR") || !eur.New.IsZero() || !eur.Churned.IsZero() {
+		t.Fatalf("unexpected movement: %#v", eur)
+	}
+
+	// Reversed, the upgrade is a contraction.
+	if usd := Movements(end, start)["USD"]; usd.Contraction != recurly.NewMoney(1000, "USD") || usd.New != recurly.NewMoney(1000, "USD") {
+		t.Fatalf("unexpected movement: %#v", usd)
+	}
+}
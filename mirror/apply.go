@@ -0,0 +1,211 @@
+package mirror
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/kmikiy/recurly"
+	"github.com/kmikiy/recurly/webhooks"
+)
+
+// Apply updates the mirror from a webhook notification, so it can be used
+// as, or called from, a webhooks.HandlerFunc:
+//   - The account of every notification is updated with the fields the
+//     notification holds, and canceled account notifications close it.
+//   - Subscription notifications update the stored subscription with the
+//     fields they hold. Fields they omit, such as custom fields, are kept,
+//     but the cancel and expiry times are always replaced, as every
+//     subscription notification holds them and a reactivation clears them.
+//   - Invoice and payment notifications update the stored invoice or
+//     transaction with the fields they hold.
+//
+// Resources that are not yet mirrored are stored with the fields the
+// notification holds, and are completed by the next pull. Notifications
+// that hold none of these resources are ignored.
+//
+// Notifications can arrive late or be replayed, so a notification older
+// than the stored resource is skipped rather than applied:
+//   - Subscriptions are stale if their current period started, or they
+//     were updated, canceled or activated, before the stored one. Changes
+//     within a period that do not update any of these times, such as a
+//     cancellation replayed after its reactivation in a notification
+//     without updated_at, cannot be ordered and are applied as they
+//     arrive.
+//   - Invoices are stale if they were updated before the stored invoice,
+//     or are open and the stored invoice is closed.
+//   - Transactions are stale if the stored transaction is void and the
+//     notification is not a void.
+//
+// Account notifications hold no times, so they are always applied.
+func (m *Mirror) Apply(notification interface{}) error {
+	v := reflect.Indirect(reflect.ValueOf(notification))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var accountCode string
+	if a, ok := field(v, "Account").(webhooks.Account); ok && a.Code != "" {
+		accountCode = a.Code
+		if err := m.applyAccount(a, notification); err != nil {
+			return err
+		}
+	}
+
+	if s, ok := field(v, "Subscription").(recurly.Subscription); ok && s.UUID != "" {
+		if s.AccountCode == "" {
+			s.AccountCode = accountCode
+		}
+		if err := m.applySubscription(s); err != nil {
+			return err
+		}
+	}
+
+	switch i := field(v, "Invoice").(type) {
+	case webhooks.Invoice:
+		if err := m.applyInvoice(i.InvoiceNumber, accountCode, i, recurly.NullTime{}, i.ClosedAt); err != nil {
+			return err
+		}
+	case webhooks.ChargeInvoice:
+		if err := m.applyInvoice(i.InvoiceNumber, accountCode, i, i.UpdatedAt, i.ClosedAt); err != nil {
+			return err
+		}
+	case webhooks.CreditInvoice:
+		if err := m.applyInvoice(i.InvoiceNumber, accountCode, i, i.UpdatedAt, i.ClosedAt); err != nil {
+			return err
+		}
+	}
+
+	if t, ok := field(v, "Transaction").(webhooks.Transaction); ok && t.UUID != "" {
+		if err := m.applyTransaction(t, accountCode, notification); err != nil {
+			return err
+		}
+	}
+	return m.store.Sync()
+}
+
+// applyAccount updates the stored account from a.
+func (m *Mirror) applyAccount(a webhooks.Account, notification interface{}) error {
+	var account recurly.Account
+	if _, err := m.get(Accounts, a.Code, &account); err != nil {
+		return err
+	}
+
+	patch(&account, a)
+	if a.Phone != "" {
+		account.Address.Phone = a.Phone
+	}
+	if _, ok := notification.(webhooks.AccountNotificationCanceled); ok {
+		account.State = "closed"
+	} else if _, ok := notification.(*webhooks.AccountNotificationCanceled); ok {
+		account.State = "closed"
+	}
+	return m.put(Accounts, account.Code, account)
+}
+
+// applySubscription updates the stored subscription from s, unless s is
+// older than the stored subscription.
+func (m *Mirror) applySubscription(s recurly.Subscription) error {
+	key := recurly.SanitizeUUID(s.UUID)
+	var stored recurly.Subscription
+	if ok, err := m.get(Subscriptions, key, &stored); err != nil {
+		return err
+	} else if ok && (before(s.CurrentPeriodStartedAt, stored.CurrentPeriodStartedAt) ||
+		before(s.UpdatedAt, stored.UpdatedAt) ||
+		before(s.CanceledAt, stored.CanceledAt) ||
+		before(s.ActivatedAt, stored.ActivatedAt)) {
+		return nil
+	}
+
+	patch(&stored, s)
+	stored.CanceledAt = s.CanceledAt
+	stored.ExpiresAt = s.ExpiresAt
+	return m.put(Subscriptions, key, stored)
+}
+
+// applyInvoice updates the stored invoice from a webhook invoice, unless
+// the webhook invoice is older than the stored one.
+func (m *Mirror) applyInvoice(invoiceNumber int, accountCode string, src interface{}, updatedAt, closedAt recurly.NullTime) error {
+	if invoiceNumber == 0 {
+		return nil
+	}
+
+	key := strconv.Itoa(invoiceNumber)
+	var invoice recurly.Invoice
+	if ok, err := m.get(Invoices, key, &invoice); err != nil {
+		return err
+	} else if ok && (before(updatedAt, invoice.UpdatedAt) || (closedAt.Time == nil && invoice.ClosedAt.Time != nil)) {
+		return nil
+	}
+
+	patch(&invoice, src)
+	if invoice.AccountCode == "" {
+		invoice.AccountCode = accountCode
+	}
+	return m.put(Invoices, key, invoice)
+}
+
+// applyTransaction updates the stored transaction from t.
+func (m *Mirror) applyTransaction(t webhooks.Transaction, accountCode string, notification interface{}) error {
+	key := recurly.SanitizeUUID(t.UUID)
+	var transaction recurly.Transaction
+	if _, err := m.get(Transactions, key, &transaction); err != nil {
+		return err
+	}
+
+	void := t.Status == recurly.TransactionStatusVoid
+	if _, ok := notification.(webhooks.PaymentNotificationVoid); ok {
+		void = true
+	} else if _, ok := notification.(*webhooks.PaymentNotificationVoid); ok {
+		void = true
+	}
+	if transaction.Status == recurly.TransactionStatusVoid && !void {
+		return nil
+	}
+
+	patch(&transaction, t)
+	transaction.UUID = key
+	if t.Test.Valid {
+		transaction.Test = t.Test.Bool
+	}
+	if transaction.Account.Code == "" {
+		transaction.Account.Code = accountCode
+	}
+	if void {
+		transaction.Status = recurly.TransactionStatusVoid
+	}
+	return m.put(Transactions, key, transaction)
+}
+
+// before reports whether a and b are both set and a is before b.
+func before(a, b recurly.NullTime) bool {
+	return a.Time != nil && b.Time != nil && a.Time.Before(*b.Time)
+}
+
+// field returns the named field of struct v, or nil if it has none.
+func field(v reflect.Value, name string) interface{} {
+	f := v.FieldByName(name)
+	if !f.IsValid() || !f.CanInterface() {
+		return nil
+	}
+	return f.Interface()
+}
+
+// patch copies the non-zero fields of src to the fields of dst with the
+// same name and type.
+func patch(dst interface{}, src interface{}) {
+	d := reflect.ValueOf(dst).Elem()
+	s := reflect.ValueOf(src)
+	for i := 0; i < s.NumField(); i++ {
+		sf := s.Type().Field(i)
+		if sf.PkgPath != "" || sf.Name == "XMLName" {
+			continue
+		}
+		df := d.FieldByName(sf.Name)
+		if !df.IsValid() || !df.CanSet() || df.Type() != sf.Type {
+			continue
+		}
+		if v := s.Field(i); !v.IsZero() {
+			df.Set(v)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package mirror
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/kmikiy/recurly"
+	"github.com/kmikiy/recurly/webhooks"
+)
+
+func TestMirror_Apply(t *testing.T) {
+	m, cleanup := newMirror(t, http.NewServeMux())
+	defer cleanup()
+
+	// Existing records are updated with the fields notifications hold.
+	if err := m.put(Accounts, "1", recurly.Account{Code: "1", Email: "old@example.com", CompanyName: "Acme"}); err != nil {
+		t.Fatal(err)
+	} else if err := m.put(Invoices, "1005", recurly.Invoice{InvoiceNumber: 1005, State: "pending", TotalInCents: 2500, AccountCode: "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	account := webhooks.Account{Code: "1", Email: "new@example.com", Phone: "555-0100"}
+	notifications := []interface{}{
+		webhooks.AccountNotificationUpdated{Account: account},
+		&webhooks.SubscriptionNotificationNew{
+			Account:      account,
+			Subscription: recurly.Subscription{UUID: "44f83d7cba354d5b84812419f923ea96", State: "active"},
+		},
+		webhooks.ChargeInvoiceNotificationClosed{
+			Account: account,
+			Invoice: webhooks.ChargeInvoice{InvoiceNumber: 1005, State: "paid"},
+		},
+		webhooks.PaymentNotificationSuccessful{
+			Account:     account,
+			Transaction: webhooks.Transaction{UUID: "t1", InvoiceNumber: 1005, AmountInCents: 2500, Status: "success", Test: recurly.NewBool(true)},
+		},
+		webhooks.PaymentNotificationVoid{
+			Account:     account,
+			Transaction: webhooks.Transaction{UUID: "t1"},
+		},
+		webhooks.AccountNotificationCanceled{Account: account},
+		"ignored",
+	}
+	for _, n := range notifications {
+		if err := m.Apply(n); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if a, err := m.Account("1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if a.Email != "new@example.com" || a.CompanyName != "Acme" || a.Address.Phone != "555-0100" || a.State != "closed" {
+		t.Fatalf("unexpected account: %#v", a)
+	} else if s, err := m.Subscription("44f83d7cba354d5b84812419f923ea96"); err != nil || s == nil || s.State != "active" || s.AccountCode != "1" {
+		t.Fatalf("unexpected subscription: %#v, %v", s, err)
+	} else if i, err := m.Invoice(1005); err != nil || i.State != "paid" || i.TotalInCents != 2500 || i.AccountCode != "1" {
+		t.Fatalf("unexpected invoice: %#v, %v", i, err)
+	} else if tr, err := m.Transaction("t1"); err != nil || tr == nil {
+		t.Fatalf("unexpected transaction: %#v, %v", tr, err)
+	} else if tr.Status != recurly.TransactionStatusVoid || tr.AmountInCents != 2500 || tr.InvoiceNumber != 1005 || !tr.Test || tr.Account.Code != "1" {
+		t.Fatalf("unexpected transaction: %#v", tr)
+	}
+}
+
+func TestMirror_Apply_Stale(t *testing.T) {
+	mux := http.NewServeMux()
+	m, cleanup := newMirror(t, mux)
+	defer cleanup()
+
+	mux.HandleFunc("/v2/accounts", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<accounts></accounts>`)
+	})
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<subscriptions><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid><state>canceled</state><current_period_started_at type="datetime">2018-05-01T00:00:00Z</current_period_started_at><collection_method>manual</collection_method><custom_fields type="array"><custom_field><name>region</name><value>emea</value></custom_field></custom_fields></subscription></subscriptions>`)
+	})
+	mux.HandleFunc("/v2/invoices", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<invoices><invoice><invoice_number type="integer">1005</invoice_number><state>paid</state><updated_at type="datetime">2018-05-01T00:00:00Z</updated_at><closed_at type="datetime">2018-05-01T00:00:00Z</closed_at></invoice></invoices>`)
+	})
+	mux.HandleFunc("/v2/transactions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<transactions><transaction><uuid>t1</uuid><status>void</status></transaction></transactions>`)
+	})
+	if _, err := m.Pull(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Notifications sent before the pull are skipped.
+	account := webhooks.Account{Code: "1"}
+	notifications := []interface{}{
+		webhooks.SubscriptionNotificationNew{
+			Account:      account,
+			Subscription: recurly.Subscription{UUID: "44f83d7cba354d5b84812419f923ea96", State: "active", CurrentPeriodStartedAt: recurly.NewTimeFromString("2018-04-01T00:00:00Z")},
+		},
+		webhooks.ChargeInvoiceNotificationNew{
+			Account: account,
+			Invoice: webhooks.ChargeInvoice{InvoiceNumber: 1005, State: "pending", UpdatedAt: recurly.NewTimeFromString("2018-04-01T00:00:00Z")},
+		},
+		webhooks.InvoiceNotificationPastDue{
+			Account: account,
+			Invoice: webhooks.Invoice{InvoiceNumber: 1005, State: "past_due"},
+		},
+		webhooks.PaymentNotificationSuccessful{
+			Account:     account,
+			Transaction: webhooks.Transaction{UUID: "t1", Status: "success"},
+		},
+	}
+	for _, n := range notifications {
+		if err := m.Apply(n); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if s, err := m.Subscription("44f83d7cba354d5b84812419f923ea96"); err != nil || s == nil || s.State != "canceled" {
+		t.Fatalf("unexpected subscription: %#v, %v", s, err)
+	} else if i, err := m.Invoice(1005); err != nil || i == nil || i.State != "paid" {
+		t.Fatalf("unexpected invoice: %#v, %v", i, err)
+	} else if tr, err := m.Transaction("t1"); err != nil || tr == nil || tr.Status != recurly.TransactionStatusVoid {
+		t.Fatalf("unexpected transaction: %#v, %v", tr, err)
+	}
+
+	// Notifications for a later period are applied, keeping the fields they
+	// omit.
+	if err := m.Apply(webhooks.SubscriptionNotificationRenewed{
+		Account:      account,
+		Subscription: recurly.Subscription{UUID: "44f83d7cba354d5b84812419f923ea96", State: "active", CurrentPeriodStartedAt: recurly.NewTimeFromString("2018-06-01T00:00:00Z")},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if s, err := m.Subscription("44f83d7cba354d5b84812419f923ea96"); err != nil || s == nil || s.State != "active" {
+		t.Fatalf("unexpected subscription: %#v, %v", s, err)
+	} else if s.CollectionMethod != "manual" || s.CustomFields == nil || (*s.CustomFields)["region"] != "emea" {
+		t.Fatalf("unexpected subscription: %#v", s)
+	}
+
+	// Within a period, notifications are ordered by when the subscription
+	// was updated or canceled.
+	period := recurly.NewTimeFromString("2018-06-01T00:00:00Z")
+	canceled := recurly.Subscription{UUID: "44f83d7cba354d5b84812419f923ea96", State: "canceled", CurrentPeriodStartedAt: period, CanceledAt: recurly.NewTimeFromString("2018-06-10T00:00:00Z"), UpdatedAt: recurly.NewTimeFromString("2018-06-10T00:00:00Z")}
+	reactivated := recurly.Subscription{UUID: "44f83d7cba354d5b84812419f923ea96", State: "active", CurrentPeriodStartedAt: period, UpdatedAt: recurly.NewTimeFromString("2018-06-11T00:00:00Z")}
+	for _, n := range []interface{}{
+		webhooks.SubscriptionNotificationCanceled{Account: account, Subscription: canceled},
+		webhooks.SubscriptionNotificationReactivated{Account: account, Subscription: reactivated},
+		webhooks.SubscriptionNotificationCanceled{Account: account, Subscription: canceled},
+	} {
+		if err := m.Apply(n); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if s, err := m.Subscription("44f83d7cba354d5b84812419f923ea96"); err != nil || s == nil || s.State != "active" || s.CanceledAt.Time != nil {
+		t.Fatalf("unexpected subscription: %#v, %v", s, err)
+	}
+
+	// A cancellation older than the stored one is skipped.
+	recanceled := canceled
+	recanceled.UpdatedAt = recurly.NullTime{}
+	recanceled.CanceledAt = recurly.NewTimeFromString("2018-06-20T00:00:00Z")
+	stale := canceled
+	stale.UpdatedAt = recurly.NullTime{}
+	for _, n := range []interface{}{
+		webhooks.SubscriptionNotificationCanceled{Account: account, Subscription: recanceled},
+		webhooks.SubscriptionNotificationCanceled{Account: account, Subscription: stale},
+	} {
+		if err := m.Apply(n); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if s, err := m.Subscription("44f83d7cba354d5b84812419f923ea96"); err != nil || s == nil || !s.CanceledAt.Time.Equal(*recanceled.CanceledAt.Time) {
+		t.Fatalf("unexpected subscription: %#v, %v", s, err)
+	}
+}
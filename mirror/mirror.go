@@ -0,0 +1,311 @@
+// Package mirror keeps a local copy of Recurly accounts, subscriptions,
+// invoices, transactions and adjustments, for dashboards and reports that
+// should not query the API.
+//
+// Resources are stored as JSON in a Store, such as a FileStore. Pull lists
+// the resources updated since the previous pull, using begin_time and
+// sort=updated_at, and Apply updates the mirror from webhook notifications
+// between pulls. Adjustments are mirrored from the line items of invoices,
+// as the API can only list adjustments by account.
+package mirror
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+// ErrList is returned when listing resources fails. It implements the
+// error interface.
+type ErrList struct {
+	Response *recurly.Response
+}
+
+// Error implements the error interface.
+func (e ErrList) Error() string {
+	return fmt.Sprintf("mirror: %s %s: %d", e.Response.Request.Method, e.Response.Request.URL.Path, e.Response.StatusCode)
+}
+
+// Mirror mirrors resources into a Store. It is safe for concurrent use,
+// though concurrent pulls of the same kind repeat work.
+type Mirror struct {
+	client *recurly.Client
+	store  Store
+
+	// PerPage is the page size used when listing resources. Defaults to 200.
+	PerPage int
+
+	// Overlap is subtracted from the time of the previous pull when listing
+	// updated resources, to allow for clock skew and for resources updated
+	// while the previous pull was running. Defaults to 5 minutes.
+	Overlap time.Duration
+
+	now func() time.Time
+}
+
+// New returns a Mirror that lists resources with client and stores them in
+// store.
+func New(client *recurly.Client, store Store) *Mirror {
+	return &Mirror{client: client, store: store, Overlap: 5 * time.Minute, now: time.Now}
+}
+
+// Pull mirrors the accounts, subscriptions, invoices and transactions
+// updated since the previous pull. It returns the number of resources
+// stored, and stops at the first error.
+func (m *Mirror) Pull() (int, error) {
+	total := 0
+	for _, kind := range []Kind{Accounts, Subscriptions, Invoices, Transactions} {
+		n, err := m.PullKind(kind)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// PullKind mirrors the resources of kind updated since its previous pull,
+// and returns the number of resources stored. Pulling invoices also stores
+// their line items as adjustments, and pulling Adjustments pulls invoices.
+//
+// The time of the pull is saved once every page has been stored, so a
+// failed pull is retried from the same point.
+func (m *Mirror) PullKind(kind Kind) (int, error) {
+	if kind == Adjustments {
+		kind = Invoices
+	}
+
+	var list func(recurly.Params) (*recurly.Response, int, error)
+	switch kind {
+	case Accounts:
+		list = func(params recurly.Params) (*recurly.Response, int, error) {
+			resp, accounts, err := m.client.Accounts.List(params)
+			for _, a := range accounts {
+				if err := m.put(Accounts, a.Code, a); err != nil {
+					return nil, 0, err
+				}
+			}
+			return resp, len(accounts), err
+		}
+	case Subscriptions:
+		list = func(params recurly.Params) (*recurly.Response, int, error) {
+			resp, subs, err := m.client.Subscriptions.List(params)
+			for _, s := range subs {
+				if err := m.put(Subscriptions, s.UUID, s); err != nil {
+					return nil, 0, err
+				}
+			}
+			return resp, len(subs), err
+		}
+	case Invoices:
+		list = func(params recurly.Params) (*recurly.Response, int, error) {
+			resp, invoices, err := m.client.Invoices.List(params)
+			n := 0
+			for _, i := range invoices {
+				if err := m.put(Invoices, strconv.Itoa(i.InvoiceNumber), i); err != nil {
+					return nil, 0, err
+				}
+				n++
+				for _, a := range i.LineItems {
+					if err := m.put(Adjustments, a.UUID, a); err != nil {
+						return nil, 0, err
+					}
+					n++
+				}
+			}
+			return resp, n, err
+		}
+	case Transactions:
+		list = func(params recurly.Params) (*recurly.Response, int, error) {
+			resp, transactions, err := m.client.Transactions.List(params)
+			for _, t := range transactions {
+				if err := m.put(Transactions, t.UUID, t); err != nil {
+					return nil, 0, err
+				}
+			}
+			return resp, len(transactions), err
+		}
+	default:
+		return 0, fmt.Errorf("mirror: unknown kind %q", kind)
+	}
+
+	start := m.now()
+	last, err := m.Checkpoint(kind)
+	if err != nil {
+		return 0, err
+	}
+
+	perPage := m.PerPage
+	if perPage <= 0 {
+		perPage = 200
+	}
+	params := recurly.Params{
+		"sort":     "updated_at",
+		"order":    "asc",
+		"per_page": perPage,
+	}
+	if !last.IsZero() {
+		params["begin_time"] = last.Add(-m.Overlap).UTC().Format(time.RFC3339)
+	}
+
+	total := 0
+	for {
+		resp, n, err := list(params)
+		total += n
+		if err != nil {
+			return total, err
+		} else if resp.IsError() {
+			return total, ErrList{Response: resp}
+		}
+
+		next := resp.Next()
+		if next == "" {
+			break
+		}
+		params["cursor"] = next
+	}
+
+	if err := m.put(kindCheckpoints, string(kind), start.UTC()); err != nil {
+		return total, err
+	}
+	return total, m.store.Sync()
+}
+
+// Checkpoint returns the time of the last complete pull of kind, or the
+// zero time if it has never been pulled.
+func (m *Mirror) Checkpoint(kind Kind) (time.Time, error) {
+	var t time.Time
+	_, err := m.get(kindCheckpoints, string(kind), &t)
+	return t, err
+}
+
+// put stores v as JSON.
+func (m *Mirror) put(kind Kind, key string, v interface{}) error {
+	if key == "" {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return m.store.Put(kind, key, b)
+}
+
+// get decodes the value stored for key into v, and reports whether there
+// was one.
+func (m *Mirror) get(kind Kind, key string, v interface{}) (bool, error) {
+	b, err := m.store.Get(kind, key)
+	if err != nil || b == nil {
+		return false, err
+	}
+	return true, json.Unmarshal(b, v)
+}
+
+// each decodes every value of kind with newValue and calls fn with it.
+func (m *Mirror) each(kind Kind, newValue func() interface{}, fn func(v interface{})) error {
+	return m.store.Each(kind, func(key string, b []byte) error {
+		v := newValue()
+		if err := json.Unmarshal(b, v); err != nil {
+			return fmt.Errorf("mirror: %s %s: %v", kind, key, err)
+		}
+		fn(v)
+		return nil
+	})
+}
+
+// Account returns the mirrored account, or nil if it is not mirrored.
+func (m *Mirror) Account(code string) (*recurly.Account, error) {
+	var a recurly.Account
+	if ok, err := m.get(Accounts, code, &a); !ok {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Subscription returns the mirrored subscription, or nil if it is not
+// mirrored.
+func (m *Mirror) Subscription(uuid string) (*recurly.Subscription, error) {
+	var s recurly.Subscription
+	if ok, err := m.get(Subscriptions, recurly.SanitizeUUID(uuid), &s); !ok {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Invoice returns the mirrored invoice, or nil if it is not mirrored.
+func (m *Mirror) Invoice(invoiceNumber int) (*recurly.Invoice, error) {
+	var i recurly.Invoice
+	if ok, err := m.get(Invoices, strconv.Itoa(invoiceNumber), &i); !ok {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// Transaction returns the mirrored transaction, or nil if it is not
+// mirrored.
+func (m *Mirror) Transaction(uuid string) (*recurly.Transaction, error) {
+	var t recurly.Transaction
+	if ok, err := m.get(Transactions, recurly.SanitizeUUID(uuid), &t); !ok {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Adjustment returns the mirrored adjustment, or nil if it is not
+// mirrored.
+func (m *Mirror) Adjustment(uuid string) (*recurly.Adjustment, error) {
+	var a recurly.Adjustment
+	if ok, err := m.get(Adjustments, recurly.SanitizeUUID(uuid), &a); !ok {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Accounts returns every mirrored account, ordered by account code.
+func (m *Mirror) Accounts() ([]recurly.Account, error) {
+	var accounts []recurly.Account
+	err := m.each(Accounts, func() interface{} { return &recurly.Account{} }, func(v interface{}) {
+		accounts = append(accounts, *v.(*recurly.Account))
+	})
+	return accounts, err
+}
+
+// Subscriptions returns every mirrored subscription, ordered by UUID.
+func (m *Mirror) Subscriptions() ([]recurly.Subscription, error) {
+	var subs []recurly.Subscription
+	err := m.each(Subscriptions, func() interface{} { return &recurly.Subscription{} }, func(v interface{}) {
+		subs = append(subs, *v.(*recurly.Subscription))
+	})
+	return subs, err
+}
+
+// Invoices returns every mirrored invoice, ordered by the string form of
+// the invoice number.
+func (m *Mirror) Invoices() ([]recurly.Invoice, error) {
+	var invoices []recurly.Invoice
+	err := m.each(Invoices, func() interface{} { return &recurly.Invoice{} }, func(v interface{}) {
+		invoices = append(invoices, *v.(*recurly.Invoice))
+	})
+	return invoices, err
+}
+
+// Transactions returns every mirrored transaction, ordered by UUID.
+func (m *Mirror) Transactions() ([]recurly.Transaction, error) {
+	var transactions []recurly.Transaction
+	err := m.each(Transactions, func() interface{} { return &recurly.Transaction{} }, func(v interface{}) {
+		transactions = append(transactions, *v.(*recurly.Transaction))
+	})
+	return transactions, err
+}
+
+// Adjustments returns every mirrored adjustment, ordered by UUID.
+func (m *Mirror) Adjustments() ([]recurly.Adjustment, error) {
+	var adjustments []recurly.Adjustment
+	err := m.each(Adjustments, func() interface{} { return &recurly.Adjustment{} }, func(v interface{}) {
+		adjustments = append(adjustments, *v.(*recurly.Adjustment))
+	})
+	return adjustments, err
+}
@@ -0,0 +1,129 @@
+package mirror
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+func newMirror(t *testing.T, mux *http.ServeMux) (*Mirror, func()) {
+	server := httptest.NewServer(mux)
+	path, cleanup := tempStore(t)
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := recurly.NewClient("test", "abc", nil, recurly.WithBaseURL(server.URL))
+	return New(client, store), func() {
+		store.Close()
+		server.Close()
+		cleanup()
+	}
+}
+
+func TestMirror_Pull(t *testing.T) {
+	mux := http.NewServeMux()
+	m, cleanup := newMirror(t, mux)
+	defer cleanup()
+
+	now := time.Date(2018, 5, 1, 12, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+
+	var beginTimes []string
+	mux.HandleFunc("/v2/accounts", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("sort") != "updated_at" || q.Get("order") != "asc" || q.Get("per_page") != "200" {
+			t.Fatalf("unexpected query: %s", r.URL.RawQuery)
+		}
+		beginTimes = append(beginTimes, q.Get("begin_time"))
+		switch q.Get("cursor") {
+		case "":
+			w.Header().Set("Link", `<https://test.recurly.com/v2/accounts?cursor=2>; rel="next"`)
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<accounts><account><account_code>1</account_code><email>a@example.com</email></account></accounts>`)
+		case "2":
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<accounts><account><account_code>2</account_code></account></accounts>`)
+		}
+	})
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<subscriptions><subscription><account href="https://test.recurly.com/v2/accounts/1"/><uuid>44f83d7cba354d5b84812419f923ea96</uuid><state>active</state></subscription></subscriptions>`)
+	})
+	mux.HandleFunc("/v2/invoices", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<invoices>
+			<invoice>
+				<account href="https://test.recurly.com/v2/accounts/1"/>
+				<invoice_number type="integer">1005</invoice_number>
+				<total_in_cents type="integer">2500</total_in_cents>
+				<line_items type="array">
+					<adjustment><uuid>a1</uuid><total_in_cents type="integer">2000</total_in_cents></adjustment>
+					<adjustment><uuid>a2</uuid><total_in_cents type="integer">500</total_in_cents></adjustment>
+				</line_items>
+			</invoice>
+		</invoices>`)
+	})
+	mux.HandleFunc("/v2/transactions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<transactions><transaction><uuid>t1</uuid><details><account><account_code>1</account_code></account></details><status>success</status></transaction></transactions>`)
+	})
+
+	if n, err := m.Pull(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if n != 7 {
+		t.Fatalf("unexpected count: %d", n)
+	} else if cp, err := m.Checkpoint(Accounts); err != nil || !cp.Equal(now) {
+		t.Fatalf("unexpected checkpoint: %v, %v", cp, err)
+	}
+
+	if a, err := m.Account("1"); err != nil || a == nil || a.Email != "a@example.com" {
+		t.Fatalf("unexpected account: %#v, %v", a, err)
+	} else if a, err := m.Account("3"); err != nil || a != nil {
+		t.Fatalf("unexpected account: %#v, %v", a, err)
+	} else if s, err := m.Subscription("44f83d7c-ba35-4d5b-8481-2419f923ea96"); err != nil || s == nil || s.AccountCode != "1" {
+		t.Fatalf("unexpected subscription: %#v, %v", s, err)
+	} else if i, err := m.Invoice(1005); err != nil || i == nil || i.TotalInCents != 2500 || len(i.LineItems) != 2 {
+		t.Fatalf("unexpected invoice: %#v, %v", i, err)
+	} else if a, err := m.Adjustment("a2"); err != nil || a == nil || a.TotalInCents != 500 {
+		t.Fatalf("unexpected adjustment: %#v, %v", a, err)
+	} else if tr, err := m.Transaction("t1"); err != nil || tr == nil || tr.Account.Code != "1" {
+		t.Fatalf("unexpected transaction: %#v, %v", tr, err)
+	} else if accounts, err := m.Accounts(); err != nil || len(accounts) != 2 || accounts[1].Code != "2" {
+		t.Fatalf("unexpected accounts: %#v, %v", accounts, err)
+	} else if adjustments, err := m.Adjustments(); err != nil || len(adjustments) != 2 {
+		t.Fatalf("unexpected adjustments: %#v, %v", adjustments, err)
+	}
+
+	// The next pull lists accounts updated since the previous pull, less
+	// the overlap.
+	now = now.Add(time.Hour)
+	if _, err := m.PullKind(Accounts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(beginTimes) != 4 || beginTimes[0] != "" || beginTimes[2] != "2018-05-01T11:55:00Z" {
+		t.Fatalf("unexpected begin times: %v", beginTimes)
+	}
+}
+
+func TestMirror_PullErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	m, cleanup := newMirror(t, mux)
+	defer cleanup()
+
+	mux.HandleFunc("/v2/transactions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	})
+
+	if _, err := m.PullKind(Transactions); err == nil || err.Error() != "mirror: GET /v2/transactions: 500" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if cp, err := m.Checkpoint(Transactions); err != nil || !cp.IsZero() {
+		t.Fatalf("unexpected checkpoint: %v, %v", cp, err)
+	} else if _, err := m.PullKind("plans"); err == nil || err.Error() != `mirror: unknown kind "plans"` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
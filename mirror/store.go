@@ -0,0 +1,323 @@
+package mirror
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Kind is a kind of mirrored resource.
+type Kind string
+
+// Kinds of mirrored resources.
+const (
+	Accounts      Kind = "accounts"
+	Subscriptions Kind = "subscriptions"
+	Invoices      Kind = "invoices"
+	Transactions  Kind = "transactions"
+	Adjustments   Kind = "adjustments"
+)
+
+// kindCheckpoints holds the time of the last complete pull of each kind.
+const kindCheckpoints Kind = "checkpoints"
+
+// Store persists mirrored resources as JSON, by kind and key. Keys are
+// account codes, invoice numbers and UUIDs. Implementations must be safe
+// for concurrent use.
+type Store interface {
+	// Get returns the value stored for key, or nil if there is none.
+	Get(kind Kind, key string) ([]byte, error)
+
+	// Put stores value for key, replacing any previous value.
+	Put(kind Kind, key string, value []byte) error
+
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(kind Kind, key string) error
+
+	// Each calls fn for every key of kind, in key order, and stops at the
+	// first error.
+	Each(kind Kind, fn func(key string, value []byte) error) error
+
+	// Sync makes previous writes durable.
+	Sync() error
+
+	// Close closes the store.
+	Close() error
+}
+
+// record is a line of the FileStore log. Deleted records have no value.
+type record struct {
+	Kind    Kind            `json:"kind"`
+	Key     string          `json:"key"`
+	Value   json.RawMessage `json:"value,omitempty"`
+	Deleted bool            `json:"deleted,omitempty"`
+}
+
+// FileStore is a Store backed by a single append-only file of JSON lines,
+// with every value held in memory. Writes are appended as they happen and
+// made durable by Sync. The file is compacted when it is opened if most of
+// its records have been superseded.
+type FileStore struct {
+	path string
+
+	mu      sync.RWMutex
+	f       storeFile
+	data    map[Kind]map[string][]byte
+	records int // records in the file, including superseded ones
+}
+
+// storeFile is the FileStore's log file. It is replaced in tests.
+type storeFile interface {
+	io.ReadWriteSeeker
+	Truncate(size int64) error
+	Sync() error
+	Close() error
+}
+
+// OpenFileStore opens the store at path, creating it if it does not exist.
+// A partially written last record, from a crash during a write, is
+// discarded.
+func OpenFileStore(path string) (*FileStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileStore{path: path, f: f, data: map[Kind]map[string][]byte{}}
+	offset, err := s.load()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Truncate(offset); err != nil {
+		f.Close()
+		return nil, err
+	} else if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if live := s.live(); s.records > 1000 && s.records > 2*live {
+		if err := s.Compact(); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// load reads the log and returns the offset after the last complete record.
+func (s *FileStore) load() (int64, error) {
+	var offset int64
+	r := bufio.NewReader(s.f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return offset, nil
+		} else if err != nil {
+			return 0, err
+		}
+
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return 0, fmt.Errorf("mirror: %s: record at offset %d: %v", s.path, offset, err)
+		}
+		s.apply(rec)
+		s.records++
+		offset += int64(len(line))
+	}
+}
+
+// apply updates the in-memory data with rec.
+func (s *FileStore) apply(rec record) {
+	m := s.data[rec.Kind]
+	if m == nil {
+		m = map[string][]byte{}
+		s.data[rec.Kind] = m
+	}
+	if rec.Deleted {
+		delete(m, rec.Key)
+	} else {
+		m[rec.Key] = []byte(rec.Value)
+	}
+}
+
+// live returns the number of stored values.
+func (s *FileStore) live() int {
+	n := 0
+	for _, m := range s.data {
+		n += len(m)
+	}
+	return n
+}
+
+// write appends rec to the file and applies it. If the write fails, the
+// file is truncated to drop any part of the record that was written, so the
+// next record is not appended to it.
+func (s *FileStore) write(rec record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return ErrClosed
+	}
+
+	offset, err := s.f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := s.f.Write(append(line, '\n')); err != nil {
+		if terr := s.f.Truncate(offset); terr != nil {
+			return fmt.Errorf("mirror: %v, and truncating the partial record failed: %v", err, terr)
+		} else if _, serr := s.f.Seek(offset, io.SeekStart); serr != nil {
+			return serr
+		}
+		return err
+	}
+	s.apply(rec)
+	s.records++
+	return nil
+}
+
+// ErrClosed is returned when using a closed FileStore.
+var ErrClosed = fmt.Errorf("mirror: store is closed")
+
+// Get implements Store.
+func (s *FileStore) Get(kind Kind, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.f == nil {
+		return nil, ErrClosed
+	}
+	return s.data[kind][key], nil
+}
+
+// Put implements Store.
+func (s *FileStore) Put(kind Kind, key string, value []byte) error {
+	if !json.Valid(value) {
+		return fmt.Errorf("mirror: invalid JSON for %s %s", kind, key)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, value); err != nil {
+		return err
+	}
+	return s.write(record{Kind: kind, Key: key, Value: compact.Bytes()})
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(kind Kind, key string) error {
+	s.mu.RLock()
+	_, ok := s.data[kind][key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return s.write(record{Kind: kind, Key: key, Deleted: true})
+}
+
+// Each implements Store. fn is called with a snapshot, so it may write to
+// the store.
+func (s *FileStore) Each(kind Kind, fn func(key string, value []byte) error) error {
+	s.mu.RLock()
+	if s.f == nil {
+		s.mu.RUnlock()
+		return ErrClosed
+	}
+	m := s.data[kind]
+	keys := make([]string, 0, len(m))
+	values := make(map[string][]byte, len(m))
+	for k, v := range m {
+		keys = append(keys, k)
+		values[k] = v
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := fn(k, values[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sync implements Store.
+func (s *FileStore) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return ErrClosed
+	}
+	return s.f.Sync()
+}
+
+// Compact rewrites the file with only the current values.
+func (s *FileStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return ErrClosed
+	}
+
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	records := 0
+	for kind, m := range s.data {
+		for key, value := range m {
+			line, err := json.Marshal(record{Kind: kind, Key: key, Value: value})
+			if err != nil {
+				f.Close()
+				return err
+			}
+			w.Write(line)
+			w.WriteByte('\n')
+			records++
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	} else if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	} else if err := os.Rename(tmp, s.path); err != nil {
+		f.Close()
+		return err
+	}
+
+	s.f.Close()
+	s.f = f
+	s.records = records
+	return nil
+}
+
+// Close implements Store. Writes are synced before closing.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return nil
+	}
+
+	err := s.f.Sync()
+	if cerr := s.f.Close(); err == nil {
+		err = cerr
+	}
+	s.f = nil
+	return err
+}
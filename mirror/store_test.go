@@ -0,0 +1,165 @@
+package mirror
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func tempStore(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "mirror")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, "mirror.jsonl"), func() { os.RemoveAll(dir) }
+}
+
+func TestFileStore(t *testing.T) {
+	path, cleanup := tempStore(t)
+	defer cleanup()
+
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(Accounts, "1", []byte(`{"account_code": "1"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := s.Put(Accounts, "2", []byte(`{"account_code":"2"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := s.Put(Accounts, "1", []byte(`{"account_code":"1","email":"a@example.com"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := s.Put(Invoices, "1001", []byte(`{"invoice_number":1001}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := s.Delete(Invoices, "1001"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := s.Delete(Invoices, "1002"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := s.Put(Accounts, "3", []byte(`{`)); err == nil {
+		t.Fatal("expected invalid JSON to be rejected")
+	}
+
+	if b, err := s.Get(Accounts, "1"); err != nil || string(b) != `{"account_code":"1","email":"a@example.com"}` {
+		t.Fatalf("unexpected value: %s, %v", b, err)
+	} else if b, err := s.Get(Invoices, "1001"); err != nil || b != nil {
+		t.Fatalf("unexpected value: %s, %v", b, err)
+	} else if err := s.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := s.Get(Accounts, "1"); err != ErrClosed {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A partially written record is discarded when reopening.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"kind":"accounts","key":"4","val`)
+	f.Close()
+
+	s, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer s.Close()
+
+	var keys []string
+	if err := s.Each(Accounts, func(key string, value []byte) error {
+		keys = append(keys, key)
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(keys, []string{"1", "2"}) {
+		t.Fatalf("unexpected keys: %v", keys)
+	} else if b, _ := s.Get(Invoices, "1001"); b != nil {
+		t.Fatalf("unexpected value: %s", b)
+	}
+
+	// Compacting keeps only the current values.
+	if err := s.Compact(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := s.Put(Accounts, "5", []byte(`{}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if b, err := ioutil.ReadFile(path); err != nil {
+		t.Fatal(err)
+	} else if lines := bytes.Count(b, []byte("\n")); lines != 3 {
+		t.Fatalf("unexpected lines: %d\n%s", lines, b)
+	}
+}
+
+func TestFileStore_Corrupt(t *testing.T) {
+	path, cleanup := tempStore(t)
+	defer cleanup()
+
+	if err := ioutil.WriteFile(path, []byte("{\n{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	} else if _, err := OpenFileStore(path); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// failingFile writes at most n bytes to the store before failing.
+type failingFile struct {
+	storeFile
+	n int
+}
+
+func (f *failingFile) Write(p []byte) (int, error) {
+	if len(p) <= f.n {
+		f.n -= len(p)
+		return f.storeFile.Write(p)
+	}
+	n, _ := f.storeFile.Write(p[:f.n])
+	f.n = 0
+	return n, errors.New("disk full")
+}
+
+func TestFileStore_WriteFailure(t *testing.T) {
+	path, cleanup := tempStore(t)
+	defer cleanup()
+
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(Accounts, "1", []byte(`{}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The record fails partway through being written.
+	f := s.f
+	s.f = &failingFile{storeFile: f, n: 10}
+	if err := s.Put(Accounts, "2", []byte(`{}`)); err == nil || err.Error() != "disk full" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if b, err := ioutil.ReadFile(path); err != nil {
+		t.Fatal(err)
+	} else if string(b) != `{"kind":"accounts","key":"1","value":{}}`+"\n" {
+		t.Fatalf("expected partial record to be truncated, given %q", b)
+	}
+	s.f = f
+
+	if err := s.Put(Accounts, "3", []byte(`{}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := s.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer s.Close()
+
+	var keys []string
+	if err := s.Each(Accounts, func(key string, value []byte) error {
+		keys = append(keys, key)
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(keys, []string{"1", "3"}) {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+}
@@ -0,0 +1,264 @@
+package recurly
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in a currency's minor unit, such as cents for USD or
+// yen for JPY, as used by the *InCents fields. Currencies are compared
+// case-insensitively, and amounts returned by Money's methods have
+// upper-case currencies, even if m was built with a lower-case one.
+type Money struct {
+	Amount   int    `json:"amount,omitempty"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// NewMoney returns amount, in minor units, of currency.
+func NewMoney(amount int, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// UnmarshalJSON unmarshals an amount, upper-casing its currency.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	var v struct {
+		Amount   int    `json:"amount"`
+		Currency string `json:"currency"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*m = NewMoney(v.Amount, v.Currency)
+	return nil
+}
+
+// ErrCurrencyMismatch is returned when combining amounts in different
+// currencies. It implements the error interface.
+type ErrCurrencyMismatch struct {
+	Expected string
+	Given    string
+}
+
+// Error implements the error interface.
+func (e ErrCurrencyMismatch) Error() string {
+	return fmt.Sprintf("recurly: currency mismatch: expected %s, given %s", e.Expected, e.Given)
+}
+
+// currencyDecimals lists currencies whose minor unit is not a hundredth.
+// All other currencies have two decimals.
+var currencyDecimals = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "MGA": 0, "PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "JOD": 3, "KWD": 3, "OMR": 3, "TND": 3,
+}
+
+// CurrencyDecimals returns the number of decimals in a currency's major
+// unit: 0 for zero-decimal currencies such as JPY and KRW, and 2 for most
+// others.
+func CurrencyDecimals(currency string) int {
+	if d, ok := currencyDecimals[strings.ToUpper(currency)]; ok {
+		return d
+	}
+	return 2
+}
+
+// check returns an ErrCurrencyMismatch if o is not in m's currency.
+func (m Money) check(o Money) error {
+	if !strings.EqualFold(m.Currency, o.Currency) {
+		return ErrCurrencyMismatch{Expected: strings.ToUpper(m.Currency), Given: strings.ToUpper(o.Currency)}
+	}
+	return nil
+}
+
+// Equal returns true if m and o are the same amount in the same currency.
+func (m Money) Equal(o Money) bool {
+	return m.Amount == o.Amount && strings.EqualFold(m.Currency, o.Currency)
+}
+
+// Add returns m plus o.
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.check(o); err != nil {
+		return Money{}, err
+	}
+	return NewMoney(m.Amount+o.Amount, m.Currency), nil
+}
+
+// Sub returns m minus o.
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.check(o); err != nil {
+		return Money{}, err
+	}
+	return NewMoney(m.Amount-o.Amount, m.Currency), nil
+}
+
+// Mul returns m multiplied by n, such as a unit amount by a quantity.
+func (m Money) Mul(n int) Money {
+	return NewMoney(m.Amount*n, m.Currency)
+}
+
+// Neg returns m with the sign reversed.
+func (m Money) Neg() Money {
+	return NewMoney(-m.Amount, m.Currency)
+}
+
+// Cmp compares m and o and returns -1, 0 or 1.
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.check(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// IsZero returns true if the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// SumMoney returns the total of amounts, which must share a currency. The
+// total of no amounts is the zero Money.
+func SumMoney(amounts ...Money) (Money, error) {
+	if len(amounts) == 0 {
+		return Money{}, nil
+	}
+
+	total := NewMoney(amounts[0].Amount, amounts[0].Currency)
+	for _, m := range amounts[1:] {
+		var err error
+		if total, err = total.Add(m); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Decimal returns the amount in major units, such as "12.34" for 1234 USD
+// cents or "1234" for 1234 JPY.
+func (m Money) Decimal() string {
+	return m.format(".", "")
+}
+
+// String returns the amount and currency, such as "12.34 USD".
+func (m Money) String() string {
+	return m.Decimal() + " " + strings.ToUpper(m.Currency)
+}
+
+// locale describes how a locale formats amounts. Spaces are non-breaking so
+// amounts are not wrapped across lines.
+type locale struct {
+	decimal     string
+	group       string
+	symbolAfter bool
+}
+
+// locales are the locales supported by Format, by language or language and
+// region.
+var locales = map[string]locale{
+	"en":    {decimal: ".", group: ","},
+	"en-IE": {decimal: ".", group: ","},
+	"ja":    {decimal: ".", group: ","},
+	"ko":    {decimal: ".", group: ","},
+	"zh":    {decimal: ".", group: ","},
+	"de":    {decimal: ",", group: ".", symbolAfter: true},
+	"de-CH": {decimal: ".", group: "'", symbolAfter: true},
+	"es":    {decimal: ",", group: ".", symbolAfter: true},
+	"it":    {decimal: ",", group: ".", symbolAfter: true},
+	"nl":    {decimal: ",", group: ".", symbolAfter: true},
+	"pt":    {decimal: ",", group: ".", symbolAfter: true},
+	"pt-BR": {decimal: ",", group: "."},
+	"fr":    {decimal: ",", group: "\u00a0", symbolAfter: true},
+	"sv":    {decimal: ",", group: "\u00a0", symbolAfter: true},
+}
+
+// currencySymbols are the symbols used by Format. Other currencies are
+// formatted with their code.
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"KRW": "₩",
+	"INR": "₹",
+	"CNY": "¥",
+	"BRL": "R$",
+	"AUD": "A$",
+	"CAD": "CA$",
+}
+
+// Format formats the amount with its currency symbol for a locale such as
+// "en-US" or "de-DE". Locales are matched by language and region, then by
+// language; unknown locales are formatted as "en".
+func (m Money) Format(localeName string) string {
+	localeName = strings.Replace(localeName, "_", "-", -1)
+	l, ok := locales[localeName]
+	if !ok {
+		if i := strings.Index(localeName, "-"); i > 0 {
+			l, ok = locales[strings.ToLower(localeName[:i])]
+		} else {
+			l, ok = locales[strings.ToLower(localeName)]
+		}
+	}
+	if !ok {
+		l = locales["en"]
+	}
+
+	currency := strings.ToUpper(m.Currency)
+	symbol, ok := currencySymbols[currency]
+	if !ok {
+		symbol = currency + "\u00a0"
+		if l.symbolAfter {
+			symbol = currency
+		}
+	}
+
+	sign, abs := "", m
+	if m.Amount < 0 {
+		sign, abs = "-", m.Neg()
+	}
+
+	number := abs.format(l.decimal, l.group)
+	if l.symbolAfter {
+		return sign + number + "\u00a0" + symbol
+	}
+	return sign + symbol + number
+}
+
+// format returns the amount in major units with the given separators.
+func (m Money) format(decimal, group string) string {
+	digits := strconv.Itoa(m.Amount)
+	sign := ""
+	if m.Amount < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	decimals := CurrencyDecimals(m.Currency)
+	for len(digits) <= decimals {
+		digits = "0" + digits
+	}
+	whole, fraction := digits[:len(digits)-decimals], digits[len(digits)-decimals:]
+
+	if group != "" {
+		var b bytes.Buffer
+		for i, c := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				b.WriteString(group)
+			}
+			b.WriteRune(c)
+		}
+		whole = b.String()
+	}
+
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + decimal + fraction
+}
@@ -0,0 +1,134 @@
+package recurly
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a, b := NewMoney(1050, "usd"), NewMoney(250, "USD")
+
+	if a.Currency != "USD" {
+		t.Fatalf("unexpected currency: %s", a.Currency)
+	} else if sum, err := a.Add(b); err != nil || sum != NewMoney(1300, "USD") {
+		t.Fatalf("unexpected sum: %v, %v", sum, err)
+	} else if diff, err := a.Sub(b); err != nil || diff != NewMoney(800, "USD") {
+		t.Fatalf("unexpected difference: %v, %v", diff, err)
+	} else if a.Mul(3) != NewMoney(3150, "USD") || a.Neg() != NewMoney(-1050, "USD") {
+		t.Fatalf("unexpected product or negation: %v %v", a.Mul(3), a.Neg())
+	} else if cmp, err := a.Cmp(b); err != nil || cmp != 1 {
+		t.Fatalf("unexpected comparison: %d, %v", cmp, err)
+	} else if cmp, err := b.Cmp(a); err != nil || cmp != -1 {
+		t.Fatalf("unexpected comparison: %d, %v", cmp, err)
+	} else if !NewMoney(0, "USD").IsZero() || a.IsZero() {
+		t.Fatal("unexpected IsZero")
+	}
+
+	if total, err := SumMoney(a, b, b); err != nil || total != NewMoney(1550, "USD") {
+		t.Fatalf("unexpected total: %v, %v", total, err)
+	} else if total, err := SumMoney(); err != nil || total != (Money{}) {
+		t.Fatalf("unexpected empty total: %v, %v", total, err)
+	}
+
+	eur := NewMoney(100, "EUR")
+	mismatch := ErrCurrencyMismatch{Expected: "USD", Given: "EUR"}
+	if _, err := a.Add(eur); err != mismatch {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := a.Sub(eur); err != mismatch {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := a.Cmp(eur); err != mismatch {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := SumMoney(a, b, eur); err != mismatch {
+		t.Fatalf("unexpected error: %v", err)
+	} else if mismatch.Error() != "recurly: currency mismatch: expected USD, given EUR" {
+		t.Fatalf("unexpected error message: %s", mismatch.Error())
+	}
+}
+
+func TestMoney_LowerCaseCurrency(t *testing.T) {
+	a, b := Money{Amount: 1050, Currency: "usd"}, NewMoney(250, "USD")
+
+	if sum, err := a.Add(b); err != nil || sum != NewMoney(1300, "USD") {
+		t.Fatalf("unexpected sum: %v, %v", sum, err)
+	} else if diff, err := b.Sub(a); err != nil || diff != NewMoney(-800, "USD") {
+		t.Fatalf("unexpected difference: %v, %v", diff, err)
+	} else if a.Mul(2) != NewMoney(2100, "USD") || a.Neg() != NewMoney(-1050, "USD") {
+		t.Fatalf("unexpected product or negation: %v %v", a.Mul(2), a.Neg())
+	} else if cmp, err := a.Cmp(b); err != nil || cmp != 1 {
+		t.Fatalf("unexpected comparison: %d, %v", cmp, err)
+	} else if total, err := SumMoney(a); err != nil || total != NewMoney(1050, "USD") {
+		t.Fatalf("unexpected total: %v, %v", total, err)
+	} else if !a.Equal(NewMoney(1050, "USD")) || a.Equal(b) || a.Equal(NewMoney(1050, "EUR")) {
+		t.Fatal("unexpected Equal")
+	} else if a.String() != "10.50 USD" || a.Format("en-US") != "$10.50" {
+		t.Fatalf("unexpected formatting: %s %s", a.String(), a.Format("en-US"))
+	} else if _, err := a.Add(Money{Amount: 1, Currency: "eur"}); err != (ErrCurrencyMismatch{Expected: "USD", Given: "EUR"}) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var m Money
+	if err := json.Unmarshal([]byte(`{"amount":1050,"currency":"usd"}`), &m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if m != NewMoney(1050, "USD") {
+		t.Fatalf("unexpected money: %v", m)
+	}
+}
+
+func TestMoney_Format(t *testing.T) {
+	tests := []struct {
+		money  Money
+		locale string
+		want   string
+	}{
+		{money: NewMoney(123456789, "USD"), locale: "en-US", want: "$1,234,567.89"},
+		{money: NewMoney(-5, "USD"), locale: "en-US", want: "-$0.05"},
+		{money: NewMoney(123456, "EUR"), locale: "de-DE", want: "1.234,56\u00a0€"},
+		{money: NewMoney(123456, "EUR"), locale: "fr_FR", want: "1\u00a0234,56\u00a0€"},
+		{money: NewMoney(123456, "GBP"), locale: "en-GB", want: "£1,234.56"},
+		{money: NewMoney(123456, "JPY"), locale: "ja-JP", want: "¥123,456"},
+		{money: NewMoney(-123456, "KRW"), locale: "ko-KR", want: "-₩123,456"},
+		{money: NewMoney(123456, "CHF"), locale: "de-CH", want: "1'234.56\u00a0CHF"},
+		{money: NewMoney(123456, "CHF"), locale: "en-US", want: "CHF\u00a01,234.56"},
+		{money: NewMoney(1234567, "KWD"), locale: "xx", want: "KWD\u00a01,234.567"},
+		{money: NewMoney(123456, "BRL"), locale: "pt-BR", want: "R$1.234,56"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Format(tt.locale); got != tt.want {
+			t.Fatalf("%v in %s: expected %q, given %q", tt.money, tt.locale, tt.want, got)
+		}
+	}
+
+	if s := NewMoney(5, "USD").String(); s != "0.05 USD" {
+		t.Fatalf("unexpected string: %s", s)
+	} else if s := NewMoney(-1234, "JPY").String(); s != "-1234 JPY" {
+		t.Fatalf("unexpected string: %s", s)
+	} else if d := NewMoney(1234, "EUR").Decimal(); d != "12.34" {
+		t.Fatalf("unexpected decimal: %s", d)
+	} else if CurrencyDecimals("jpy") != 0 || CurrencyDecimals("KWD") != 3 || CurrencyDecimals("USD") != 2 {
+		t.Fatal("unexpected currency decimals")
+	}
+}
+
+func TestMoney_Accessors(t *testing.T) {
+	i := Invoice{SubtotalInCents: 1000, DiscountInCents: 100, TaxInCents: 90, TotalInCents: 990, BalanceInCents: 490, Currency: "EUR"}
+	if i.Subtotal() != NewMoney(1000, "EUR") || i.Discount() != NewMoney(100, "EUR") || i.Tax() != NewMoney(90, "EUR") ||
+		i.Total() != NewMoney(990, "EUR") || i.Balance() != NewMoney(490, "EUR") {
+		t.Fatalf("unexpected invoice amounts: %v %v %v %v %v", i.Subtotal(), i.Discount(), i.Tax(), i.Total(), i.Balance())
+	}
+
+	a := Adjustment{UnitAmountInCents: 500, DiscountInCents: 50, TaxInCents: 45, TotalInCents: 995, Currency: "JPY"}
+	if a.UnitAmount() != NewMoney(500, "JPY") || a.Discount() != NewMoney(50, "JPY") || a.Tax() != NewMoney(45, "JPY") || a.Total() != NewMoney(995, "JPY") {
+		t.Fatalf("unexpected adjustment amounts: %v %v %v %v", a.UnitAmount(), a.Discount(), a.Tax(), a.Total())
+	}
+
+	tx := Transaction{AmountInCents: 1000, TaxInCents: 80, Currency: "USD"}
+	if tx.Amount() != NewMoney(1000, "USD") || tx.Tax() != NewMoney(80, "USD") {
+		t.Fatalf("unexpected transaction amounts: %v %v", tx.Amount(), tx.Tax())
+	}
+
+	s := Subscription{UnitAmountInCents: 800, TotalAmountInCents: 1200, CostInCents: 1200, TaxInCents: 96, Currency: "GBP"}
+	if s.UnitAmount() != NewMoney(800, "GBP") || s.TotalAmount() != NewMoney(1200, "GBP") || s.Cost() != NewMoney(1200, "GBP") || s.Tax() != NewMoney(96, "GBP") {
+		t.Fatalf("unexpected subscription amounts: %v %v %v %v", s.UnitAmount(), s.TotalAmount(), s.Cost(), s.Tax())
+	}
+}
@@ -4,27 +4,27 @@ import "encoding/xml"
 
 // Plan represents an individual plan on your site.
 type Plan struct {
-	XMLName                  xml.Name   `xml:"plan"`
-	Code                     string     `xml:"plan_code,omitempty"`
-	Name                     string     `xml:"name"`
-	Description              string     `xml:"description,omitempty"`
-	SuccessURL               string     `xml:"success_url,omitempty"`
-	CancelURL                string     `xml:"cancel_url,omitempty"`
-	DisplayDonationAmounts   NullBool   `xml:"display_donation_amounts,omitempty"`
-	DisplayQuantity          NullBool   `xml:"display_quantity,omitempty"`
-	DisplayPhoneNumber       NullBool   `xml:"display_phone_number,omitempty"`
-	BypassHostedConfirmation NullBool   `xml:"bypass_hosted_confirmation,omitempty"`
-	UnitName                 string     `xml:"unit_name,omitempty"`
-	PaymentPageTOSLink       string     `xml:"payment_page_tos_link,omitempty"`
-	IntervalUnit             string     `xml:"plan_interval_unit,omitempty"`
-	IntervalLength           int        `xml:"plan_interval_length,omitempty"`
-	TrialIntervalUnit        string     `xml:"trial_interval_unit,omitempty"`
-	TrialIntervalLength      int        `xml:"trial_interval_length,omitempty"`
-	TotalBillingCycles       NullInt    `xml:"total_billing_cycles,omitempty"`
-	AccountingCode           string     `xml:"accounting_code,omitempty"`
-	CreatedAt                NullTime   `xml:"created_at,omitempty"`
-	TaxExempt                NullBool   `xml:"tax_exempt,omitempty"`
-	TaxCode                  string     `xml:"tax_code,omitempty"`
-	UnitAmountInCents        UnitAmount `xml:"unit_amount_in_cents"`
-	SetupFeeInCents          UnitAmount `xml:"setup_fee_in_cents,omitempty"`
+	XMLName                  xml.Name   `xml:"plan" json:"-"`
+	Code                     string     `xml:"plan_code,omitempty" json:"plan_code,omitempty"`
+	Name                     string     `xml:"name" json:"name,omitempty"`
+	Description              string     `xml:"description,omitempty" json:"description,omitempty"`
+	SuccessURL               string     `xml:"success_url,omitempty" json:"success_url,omitempty"`
+	CancelURL                string     `xml:"cancel_url,omitempty" json:"cancel_url,omitempty"`
+	DisplayDonationAmounts   NullBool   `xml:"display_donation_amounts,omitempty" json:"display_donation_amounts"`
+	DisplayQuantity          NullBool   `xml:"display_quantity,omitempty" json:"display_quantity"`
+	DisplayPhoneNumber       NullBool   `xml:"display_phone_number,omitempty" json:"display_phone_number"`
+	BypassHostedConfirmation NullBool   `xml:"bypass_hosted_confirmation,omitempty" json:"bypass_hosted_confirmation"`
+	UnitName                 string     `xml:"unit_name,omitempty" json:"unit_name,omitempty"`
+	PaymentPageTOSLink       string     `xml:"payment_page_tos_link,omitempty" json:"payment_page_tos_link,omitempty"`
+	IntervalUnit             string     `xml:"plan_interval_unit,omitempty" json:"plan_interval_unit,omitempty"`
+	IntervalLength           int        `xml:"plan_interval_length,omitempty" json:"plan_interval_length,omitempty"`
+	TrialIntervalUnit        string     `xml:"trial_interval_unit,omitempty" json:"trial_interval_unit,omitempty"`
+	TrialIntervalLength      int        `xml:"trial_interval_length,omitempty" json:"trial_interval_length,omitempty"`
+	TotalBillingCycles       NullInt    `xml:"total_billing_cycles,omitempty" json:"total_billing_cycles"`
+	AccountingCode           string     `xml:"accounting_code,omitempty" json:"accounting_code,omitempty"`
+	CreatedAt                NullTime   `xml:"created_at,omitempty" json:"created_at"`
+	TaxExempt                NullBool   `xml:"tax_exempt,omitempty" json:"tax_exempt"`
+	TaxCode                  string     `xml:"tax_code,omitempty" json:"tax_code,omitempty"`
+	UnitAmountInCents        UnitAmount `xml:"unit_amount_in_cents" json:"unit_amount_in_cents"`
+	SetupFeeInCents          UnitAmount `xml:"setup_fee_in_cents,omitempty" json:"setup_fee_in_cents"`
 }
@@ -0,0 +1,137 @@
+package recurly
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Subscription change timeframes.
+const (
+	TimeframeNow      = "now"
+	TimeframeRenewal  = "renewal"
+	TimeframeBillDate = "bill_date"
+)
+
+// Adjustment origin constants.
+const (
+	AdjustmentOriginPlan   = "plan"
+	AdjustmentOriginAddOn  = "add_on"
+	AdjustmentOriginCredit = "credit"
+)
+
+var (
+	// ErrProrationPeriod is returned by ProrateChange when the subscription
+	// has no current billing period.
+	ErrProrationPeriod = errors.New("recurly: subscription has no current period to prorate")
+
+	// ErrProrationUnitAmount is returned by ProrateChange when the plan
+	// changes without a unit amount. The price of the new plan is not known
+	// locally, so it must be set on the update.
+	ErrProrationUnitAmount = errors.New("recurly: unit amount is required to prorate a plan change")
+
+	// ErrProrationTimeframe is returned by ProrateChange when the update has
+	// a timeframe other than now, renewal or bill_date.
+	ErrProrationTimeframe = errors.New("recurly: unknown timeframe to prorate")
+)
+
+// ProrateChange estimates the adjustments Recurly creates when sub is
+// updated with update at the given time, without calling PreviewChange.
+//
+// Changes with a renewal or bill_date timeframe take effect at the next
+// renewal or bill date and create no adjustments. Immediate changes, with a
+// now or empty timeframe, credit the unused part of the current period for
+// the plan and each add-on, and charge the new plan and add-ons for the
+// rest of the period. Each adjustment's total is the unit amount times the
+// quantity, prorated and then rounded to the nearest cent once, as on
+// Recurly's invoices; its UnitAmountInCents is the prorated unit amount,
+// rounded on its own. Subscriptions in a trial are not charged until the
+// trial ends, so changes during a trial also create no adjustments.
+//
+// The estimate excludes taxes, discounts and setup fees.
+func ProrateChange(sub Subscription, update UpdateSubscription, now time.Time) ([]Adjustment, error) {
+	switch update.Timeframe {
+	case "", TimeframeNow:
+	case TimeframeRenewal, TimeframeBillDate:
+		return nil, nil
+	default:
+		return nil, ErrProrationTimeframe
+	}
+	if sub.TrialEndsAt.Time != nil && now.Before(*sub.TrialEndsAt.Time) {
+		return nil, nil
+	}
+
+	start, end := sub.CurrentPeriodStartedAt.Time, sub.CurrentPeriodEndsAt.Time
+	if start == nil || end == nil || !end.After(*start) {
+		return nil, ErrProrationPeriod
+	}
+
+	planCode, unitAmount, quantity := sub.Plan.Code, sub.UnitAmountInCents, sub.Quantity
+	if update.PlanCode != "" && update.PlanCode != planCode {
+		if update.UnitAmountInCents == 0 {
+			return nil, ErrProrationUnitAmount
+		}
+		planCode = update.PlanCode
+	}
+	if update.UnitAmountInCents != 0 {
+		unitAmount = update.UnitAmountInCents
+	}
+	if update.Quantity != 0 {
+		quantity = update.Quantity
+	}
+	addOns := sub.SubscriptionAddOns
+	if update.SubscriptionAddOns != nil {
+		addOns = *update.SubscriptionAddOns
+	}
+
+	// The fraction of the current period remaining.
+	fraction := float64(end.Sub(now)) / float64(end.Sub(*start))
+	if fraction > 1 {
+		fraction = 1
+	} else if fraction <= 0 {
+		return nil, nil
+	}
+
+	adjustment := func(origin, code string, unitAmount, quantity int, credit bool) Adjustment {
+		if quantity == 0 {
+			quantity = 1
+		}
+		unit := int(math.Round(float64(unitAmount) * fraction))
+		total := int(math.Round(float64(unitAmount*quantity) * fraction))
+		if credit {
+			unit, total, origin = -unit, -total, AdjustmentOriginCredit
+		}
+		return Adjustment{
+			SubscriptionUUID:  sub.UUID,
+			Origin:            origin,
+			ProductCode:       code,
+			UnitAmountInCents: unit,
+			Quantity:          quantity,
+			TotalInCents:      total,
+			Currency:          sub.Currency,
+			StartDate:         NewTime(now),
+			EndDate:           NewTime(*end),
+		}
+	}
+
+	var adjustments []Adjustment
+	adjustments = append(adjustments, adjustment(AdjustmentOriginPlan, sub.Plan.Code, sub.UnitAmountInCents, sub.Quantity, true))
+	for _, a := range sub.SubscriptionAddOns {
+		adjustments = append(adjustments, adjustment(AdjustmentOriginAddOn, a.Code, a.UnitAmountInCents, a.Quantity, true))
+	}
+	adjustments = append(adjustments, adjustment(AdjustmentOriginPlan, planCode, unitAmount, quantity, false))
+	for _, a := range addOns {
+		adjustments = append(adjustments, adjustment(AdjustmentOriginAddOn, a.Code, a.UnitAmountInCents, a.Quantity, false))
+	}
+	return adjustments, nil
+}
+
+// ProrationTotal returns the net amount of adjustments, such as those
+// returned by ProrateChange. A negative total is a credit.
+func ProrationTotal(adjustments []Adjustment) int {
+	var total int
+	for _, a := range adjustments {
+		total += a.TotalInCents
+	}
+	return total
+}
@@ -0,0 +1,129 @@
+package recurly
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProrateChange(t *testing.T) {
+	start := time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 30)
+	now := start.AddDate(0, 0, 10)
+
+	sub := Subscription{
+		UUID:                   "44f83d7cba354d5b84812419f923ea96",
+		Plan:                   NestedPlan{Code: "gold"},
+		UnitAmountInCents:      3000,
+		Quantity:               1,
+		Currency:               "USD",
+		CurrentPeriodStartedAt: NewTime(start),
+		CurrentPeriodEndsAt:    NewTime(end),
+		SubscriptionAddOns: []SubscriptionAddOn{
+			{Code: "extra_users", UnitAmountInCents: 1000, Quantity: 2},
+		},
+	}
+
+	update := sub.MakeUpdate()
+	update.Timeframe = TimeframeNow
+	update.PlanCode = "platinum"
+	update.UnitAmountInCents = 6000
+
+	adjustments, err := ProrateChange(sub, update, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	adjustment := func(origin, code string, unit, quantity, total int) Adjustment {
+		return Adjustment{
+			SubscriptionUUID:  "44f83d7cba354d5b84812419f923ea96",
+			Origin:            origin,
+			ProductCode:       code,
+			UnitAmountInCents: unit,
+			Quantity:          quantity,
+			TotalInCents:      total,
+			Currency:          "USD",
+			StartDate:         NewTime(now),
+			EndDate:           NewTime(end),
+		}
+	}
+	if !reflect.DeepEqual(adjustments, []Adjustment{
+		adjustment(AdjustmentOriginCredit, "gold", -2000, 1, -2000),
+		adjustment(AdjustmentOriginCredit, "extra_users", -667, 2, -1333),
+		adjustment(AdjustmentOriginPlan, "platinum", 4000, 1, 4000),
+		adjustment(AdjustmentOriginAddOn, "extra_users", 667, 2, 1333),
+	}) {
+		t.Fatalf("unexpected adjustments: %#v", adjustments)
+	} else if total := ProrationTotal(adjustments); total != 2000 {
+		t.Fatalf("unexpected total: %d", total)
+	}
+
+	// Quantity changes keep the plan's unit amount.
+	update = UpdateSubscription{Quantity: 3}
+	if adjustments, err := ProrateChange(sub, update, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if total := ProrationTotal(adjustments); total != 4000 {
+		t.Fatalf("unexpected total: %d", total)
+	}
+
+	// Removing add-ons is credited.
+	update = UpdateSubscription{SubscriptionAddOns: &[]SubscriptionAddOn{}}
+	if adjustments, err := ProrateChange(sub, update, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(adjustments) != 3 || ProrationTotal(adjustments) != -1333 {
+		t.Fatalf("unexpected adjustments: %#v", adjustments)
+	}
+
+	// Changes at renewal are not prorated.
+	if adjustments, err := ProrateChange(sub, UpdateSubscription{Timeframe: TimeframeRenewal, Quantity: 3}, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if adjustments != nil {
+		t.Fatalf("unexpected adjustments: %#v", adjustments)
+	}
+
+	// Changes at the next bill date are not prorated either.
+	if adjustments, err := ProrateChange(sub, UpdateSubscription{Timeframe: TimeframeBillDate, Quantity: 3}, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if adjustments != nil {
+		t.Fatalf("unexpected adjustments: %#v", adjustments)
+	}
+
+	// Changes after the period ends are not prorated.
+	if adjustments, err := ProrateChange(sub, UpdateSubscription{Quantity: 3}, end.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if adjustments != nil {
+		t.Fatalf("unexpected adjustments: %#v", adjustments)
+	}
+
+	// Changes during a trial are not charged.
+	trial := sub
+	trial.TrialEndsAt = NewTime(end)
+	if adjustments, err := ProrateChange(trial, UpdateSubscription{Quantity: 3}, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if adjustments != nil {
+		t.Fatalf("unexpected adjustments: %#v", adjustments)
+	}
+}
+
+func TestProrateChange_Errors(t *testing.T) {
+	start := time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)
+	sub := Subscription{
+		Plan:                   NestedPlan{Code: "gold"},
+		UnitAmountInCents:      3000,
+		CurrentPeriodStartedAt: NewTime(start),
+		CurrentPeriodEndsAt:    NewTime(start.AddDate(0, 1, 0)),
+	}
+
+	if _, err := ProrateChange(sub, UpdateSubscription{PlanCode: "platinum"}, start); err != ErrProrationUnitAmount {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := ProrateChange(sub, UpdateSubscription{Timeframe: "later", Quantity: 2}, start); err != ErrProrationTimeframe {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sub.CurrentPeriodEndsAt = NullTime{}
+	if _, err := ProrateChange(sub, UpdateSubscription{Quantity: 2}, start); err != ErrProrationPeriod {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
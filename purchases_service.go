@@ -10,7 +10,13 @@ type purchasesImpl struct {
 	client *Client
 }
 
+// Create creates a purchase. It requires api version 2.10 or later, which
+// returns the invoice collection.
+// https://dev.recurly.com/docs/create-purchase
 func (s *purchasesImpl) Create(p Purchase) (*Response, *InvoiceCollection, error) {
+	if err := s.client.requireAPIVersion(creditInvoicesAPIVersion); err != nil {
+		return nil, nil, err
+	}
 	req, err := s.client.newRequest("POST", "purchases", nil, p)
 	if err != nil {
 		return nil, nil, err
@@ -25,7 +31,13 @@ func (s *purchasesImpl) Create(p Purchase) (*Response, *InvoiceCollection, error
 	return resp, &dst, err
 }
 
+// Preview previews a purchase without creating it. It requires api version
+// 2.10 or later, which returns the invoice collection.
+// https://dev.recurly.com/docs/preview-purchase
 func (s *purchasesImpl) Preview(p Purchase) (*Response, *InvoiceCollection, error) {
+	if err := s.client.requireAPIVersion(creditInvoicesAPIVersion); err != nil {
+		return nil, nil, err
+	}
 	req, err := s.client.newRequest("POST", "purchases/preview", nil, p)
 	if err != nil {
 		return nil, nil, err
@@ -0,0 +1,179 @@
+package reconcile
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+// ErrList is returned when listing resources fails. It implements the
+// error interface.
+type ErrList struct {
+	Response *recurly.Response
+}
+
+// Error implements the error interface.
+func (e ErrList) Error() string {
+	return fmt.Sprintf("reconcile: %s %s: %d", e.Response.Request.Method, e.Response.Request.URL.Path, e.Response.StatusCode)
+}
+
+// Reconciler fetches the Recurly items of a period and reconciles them
+// against ledger entries.
+type Reconciler struct {
+	client *recurly.Client
+
+	// Tolerance is how far apart the dates of an item and its entries may
+	// be, to allow for settlement and posting delays. Defaults to 3 days.
+	Tolerance time.Duration
+
+	// PerPage is the page size used when listing resources. Defaults to 200.
+	PerPage int
+}
+
+// NewReconciler returns a Reconciler that lists items with client.
+func NewReconciler(client *recurly.Client) *Reconciler {
+	return &Reconciler{client: client, Tolerance: 3 * 24 * time.Hour}
+}
+
+// Reconcile fetches the items created from begin until end and reconciles
+// them against entries.
+func (r *Reconciler) Reconcile(begin, end time.Time, entries []Entry) (*Report, error) {
+	items, err := r.Fetch(begin, end)
+	if err != nil {
+		return nil, err
+	}
+	return Reconcile(items, entries, r.Tolerance), nil
+}
+
+// Fetch returns the invoices created from begin until end, followed by
+// each of their successful transactions and applied credit payments. Credit
+// payments created in the period but not listed with an invoice, such as
+// refunds of credit, follow. Refunds have negative amounts.
+func (r *Reconciler) Fetch(begin, end time.Time) ([]Item, error) {
+	var items []Item
+	seen := map[string]bool{}
+
+	if err := r.list(begin, end, func(params recurly.Params) (*recurly.Response, error) {
+		resp, invoices, err := r.client.Invoices.List(params)
+		for _, i := range invoices {
+			items = append(items, invoiceItem(i))
+			for _, t := range i.Transactions {
+				if t.Status != recurly.TransactionStatusSuccess {
+					continue
+				}
+				items = append(items, transactionItem(t, i.InvoiceNumber))
+			}
+			for _, c := range i.CreditPayments {
+				if c.VoidedAt.Time != nil {
+					continue
+				}
+				seen[c.UUID] = true
+				items = append(items, creditPaymentItem(c, i.InvoiceNumber))
+			}
+		}
+		return resp, err
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := r.list(begin, end, func(params recurly.Params) (*recurly.Response, error) {
+		resp, payments, err := r.client.CreditPayments.List(params)
+		for _, c := range payments {
+			if seen[c.UUID] || c.VoidedAt.Time != nil {
+				continue
+			}
+			seen[c.UUID] = true
+			invoiceNumber := c.AppliedToInvoice
+			if invoiceNumber == 0 {
+				invoiceNumber = c.OriginalInvoiceNumber
+			}
+			items = append(items, creditPaymentItem(c, invoiceNumber))
+		}
+		return resp, err
+	}); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// list calls fn for each page of resources created between begin and end.
+func (r *Reconciler) list(begin, end time.Time, fn func(recurly.Params) (*recurly.Response, error)) error {
+	perPage := r.PerPage
+	if perPage <= 0 {
+		perPage = 200
+	}
+
+	params := recurly.Params{
+		"begin_time": begin.UTC().Format(time.RFC3339),
+		"end_time":   end.UTC().Format(time.RFC3339),
+		"per_page":   perPage,
+	}
+	for {
+		resp, err := fn(params)
+		if err != nil {
+			return err
+		} else if resp.IsError() {
+			return ErrList{Response: resp}
+		}
+
+		next := resp.Next()
+		if next == "" {
+			return nil
+		}
+		params["cursor"] = next
+	}
+}
+
+func invoiceItem(i recurly.Invoice) Item {
+	item := Item{
+		Kind:          Invoice,
+		ID:            invoiceID(i),
+		References:    []string{invoiceID(i), strconv.Itoa(i.InvoiceNumber), i.UUID},
+		InvoiceNumber: i.InvoiceNumber,
+		Amount:        i.Total(),
+	}
+	if i.CreatedAt.Time != nil {
+		item.Date = *i.CreatedAt.Time
+	}
+	return item
+}
+
+func transactionItem(t recurly.Transaction, invoiceNumber int) Item {
+	amount := t.Amount()
+	if t.Action == "refund" {
+		amount = amount.Neg()
+	}
+
+	item := Item{
+		Kind:          Transaction,
+		ID:            t.UUID,
+		References:    []string{t.UUID, t.Reference},
+		InvoiceNumber: invoiceNumber,
+		Amount:        amount,
+	}
+	if t.CreatedAt.Time != nil {
+		item.Date = *t.CreatedAt.Time
+	}
+	return item
+}
+
+func creditPaymentItem(c recurly.CreditPayment, invoiceNumber int) Item {
+	amount := recurly.NewMoney(c.AmountInCents, c.Currency)
+	if c.Action == recurly.CreditPaymentActionRefund {
+		amount = amount.Neg()
+	}
+
+	item := Item{
+		Kind:          CreditPayment,
+		ID:            c.UUID,
+		References:    []string{c.UUID},
+		InvoiceNumber: invoiceNumber,
+		Amount:        amount,
+	}
+	if c.CreatedAt.Time != nil {
+		item.Date = *c.CreatedAt.Time
+	}
+	return item
+}
@@ -0,0 +1,105 @@
+package reconcile
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+func TestReconciler_Reconcile(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := recurly.NewClient("test", "abc", nil, recurly.WithBaseURL(server.URL))
+
+	begin := time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := begin.AddDate(0, 1, 0)
+	check := func(r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("begin_time") != "2018-03-01T00:00:00Z" || q.Get("end_time") != "2018-04-01T00:00:00Z" || q.Get("per_page") != "200" {
+			t.Fatalf("unexpected query: %s", r.URL.RawQuery)
+		}
+	}
+
+	mux.HandleFunc("/v2/invoices", func(w http.ResponseWriter, r *http.Request) {
+		check(r)
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<invoices>
+			<invoice>
+				<invoice_number_prefix>CN</invoice_number_prefix>
+				<invoice_number type="integer">1005</invoice_number>
+				<uuid>i1</uuid>
+				<currency>USD</currency>
+				<total_in_cents type="integer">2500</total_in_cents>
+				<created_at type="datetime">2018-03-02T10:00:00Z</created_at>
+				<transactions type="array">
+					<transaction><uuid>t1</uuid><action>purchase</action><amount_in_cents type="integer">2000</amount_in_cents><currency>USD</currency><status>success</status><reference>ch_123</reference><created_at type="datetime">2018-03-02T10:00:00Z</created_at></transaction>
+					<transaction><uuid>t0</uuid><action>purchase</action><amount_in_cents type="integer">2000</amount_in_cents><currency>USD</currency><status>declined</status></transaction>
+				</transactions>
+				<credit_payments type="array">
+					<credit_payment><uuid>c1</uuid><action>payment</action><currency>USD</currency><amount_in_cents type="integer">500</amount_in_cents><created_at type="datetime">2018-03-02T10:00:00Z</created_at></credit_payment>
+				</credit_payments>
+			</invoice>
+		</invoices>`)
+	})
+	mux.HandleFunc("/v2/credit_payments", func(w http.ResponseWriter, r *http.Request) {
+		check(r)
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<credit_payments>
+			<credit_payment><uuid>c1</uuid><action>payment</action><currency>USD</currency><amount_in_cents type="integer">500</amount_in_cents></credit_payment>
+			<credit_payment><uuid>c2</uuid><action>refund</action><currency>USD</currency><amount_in_cents type="integer">300</amount_in_cents><original_invoice href="https://test.recurly.com/v2/invoices/1004"/></credit_payment>
+			<credit_payment><uuid>c3</uuid><action>payment</action><currency>USD</currency><amount_in_cents type="integer">100</amount_in_cents><voided_at type="datetime">2018-03-03T10:00:00Z</voided_at></credit_payment>
+		</credit_payments>`)
+	})
+
+	r := NewReconciler(client)
+	items, err := r.Fetch(begin, end)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	created := time.Date(2018, 3, 2, 10, 0, 0, 0, time.UTC)
+	if !reflect.DeepEqual(items, []Item{
+		{Kind: Invoice, ID: "CN1005", References: []string{"CN1005", "1005", "i1"}, InvoiceNumber: 1005, Amount: recurly.NewMoney(2500, "USD"), Date: created},
+		{Kind: Transaction, ID: "t1", References: []string{"t1", "ch_123"}, InvoiceNumber: 1005, Amount: recurly.NewMoney(2000, "USD"), Date: created},
+		{Kind: CreditPayment, ID: "c1", References: []string{"c1"}, InvoiceNumber: 1005, Amount: recurly.NewMoney(500, "USD"), Date: created},
+		{Kind: CreditPayment, ID: "c2", References: []string{"c2"}, InvoiceNumber: 1004, Amount: recurly.NewMoney(-300, "USD")},
+	}) {
+		t.Fatalf("unexpected items: %#v", items)
+	}
+
+	report, err := r.Reconcile(begin, end, []Entry{
+		{ID: "e1", Reference: "CN1005", Amount: recurly.NewMoney(2500, "USD"), Date: created.AddDate(0, 0, 1)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if m := report.Filter(Matched); len(m) != 1 || m[0].Item.ID != "CN1005" {
+		t.Fatalf("unexpected matches: %#v", m)
+	} else if m := report.Filter(Unmatched); len(m) != 3 {
+		t.Fatalf("unexpected unmatched: %#v", m)
+	}
+}
+
+func TestReconciler_Errors(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := recurly.NewClient("test", "abc", nil, recurly.WithBaseURL(server.URL))
+
+	mux.HandleFunc("/v2/invoices", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<invoices></invoices>`)
+	})
+	mux.HandleFunc("/v2/credit_payments", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	})
+
+	if _, err := NewReconciler(client).Reconcile(time.Now(), time.Now(), nil); err == nil || err.Error() != "reconcile: GET /v2/credit_payments: 500" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
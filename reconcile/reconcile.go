@@ -0,0 +1,283 @@
+// Package reconcile matches Recurly invoices, transactions and credit
+// payments against the entries of an external general ledger.
+//
+// Each Recurly item is matched to the ledger entries that reference it, by
+// invoice number or UUID. Items that no entry references are then matched
+// to an unreferenced entry of the same amount within a date tolerance.
+// Ledgers often book both the receivable and the receipt of an invoice
+// against its invoice number, so an entry referencing an invoice whose
+// entries already total its amount is matched to a transaction of that
+// invoice for the entry's amount instead, if no entry references the
+// transaction. As the entry may instead be a duplicate of the receivable,
+// such matches have the Inferred status rather than Matched. The Report
+// lists every item with its status and Recurly invoice number, and the
+// ledger entries that matched nothing.
+package reconcile
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+// Kind is a kind of Recurly item.
+type Kind string
+
+// Kinds of Recurly items.
+const (
+	Invoice       Kind = "invoice"
+	Transaction   Kind = "transaction"
+	CreditPayment Kind = "credit_payment"
+)
+
+// Item is a Recurly invoice, transaction or credit payment to reconcile.
+type Item struct {
+	Kind Kind
+
+	// ID is the invoice number, with its prefix, or the UUID.
+	ID string
+
+	// References are the values a ledger entry may reference the item by,
+	// such as the invoice number with and without its prefix, or the
+	// transaction's UUID and gateway reference.
+	References []string
+
+	// InvoiceNumber is the invoice the item belongs to, or for credit
+	// payments the invoice the credit was applied to.
+	InvoiceNumber int
+
+	Amount recurly.Money
+	Date   time.Time
+}
+
+// Entry is a general ledger entry.
+type Entry struct {
+	ID string
+
+	// Reference is the Recurly invoice number or UUID the entry was booked
+	// against, or empty if unknown.
+	Reference string
+
+	Amount recurly.Money
+	Date   time.Time
+}
+
+// Status is the outcome of reconciling an item.
+type Status string
+
+// Statuses of reconciled items.
+const (
+	// Matched items have entries totalling their amount, dated within the
+	// tolerance.
+	Matched Status = "matched"
+
+	// Inferred items are transactions matched to an entry that referenced
+	// their invoice after the invoice's own entries totalled its amount.
+	// The entry is usually the receipt of the invoice, but may be a
+	// duplicate of its receivable, so these matches should be checked.
+	Inferred Status = "inferred"
+
+	// Partial items have entries whose total or dates differ.
+	Partial Status = "partial"
+
+	// Duplicate items have several entries each for their full amount.
+	Duplicate Status = "duplicate"
+
+	// Unmatched items have no entries.
+	Unmatched Status = "unmatched"
+)
+
+// Match is the result of reconciling an item.
+type Match struct {
+	Status  Status
+	Item    Item
+	Entries []Entry
+
+	// Difference is the item's amount less the total of its entries in
+	// the same currency.
+	Difference recurly.Money
+}
+
+// Report is the result of reconciling items against ledger entries.
+type Report struct {
+	// Matches holds every item, in the order given.
+	Matches []Match
+
+	// UnmatchedEntries are the ledger entries that matched no item.
+	UnmatchedEntries []Entry
+}
+
+// Filter returns the matches with status.
+func (r *Report) Filter(status Status) []Match {
+	var matches []Match
+	for _, m := range r.Matches {
+		if m.Status == status {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// Reconcile matches items to entries. Entries are dated within tolerance
+// of an item if they are no more than tolerance before or after it.
+func Reconcile(items []Item, entries []Entry, tolerance time.Duration) *Report {
+	index := map[string]int{}
+	for i, item := range items {
+		for _, ref := range item.References {
+			if key := normalize(ref); key != "" {
+				if _, ok := index[key]; !ok {
+					index[key] = i
+				}
+			}
+		}
+	}
+
+	report := &Report{Matches: make([]Match, len(items))}
+	var unreferenced []Entry
+	var overflow []int
+	for j, e := range entries {
+		i, ok := index[normalize(e.Reference)]
+		switch {
+		case !ok || e.Reference == "":
+			unreferenced = append(unreferenced, e)
+		case items[i].Kind == Invoice && booked(items[i], report.Matches[i].Entries):
+			overflow = append(overflow, j)
+		default:
+			report.Matches[i].Entries = append(report.Matches[i].Entries, e)
+		}
+	}
+
+	// Entries referencing an invoice that is already booked are matched to
+	// a transaction of the invoice for the same amount, or else to the
+	// invoice.
+	inferred := make([]bool, len(items))
+	for _, j := range overflow {
+		e := entries[j]
+		i := index[normalize(e.Reference)]
+		for k, item := range items {
+			if item.Kind == Transaction && item.InvoiceNumber == items[i].InvoiceNumber && item.InvoiceNumber != 0 &&
+				len(report.Matches[k].Entries) == 0 && e.Amount.Equal(item.Amount) {
+				i = k
+				inferred[k] = true
+				break
+			}
+		}
+		report.Matches[i].Entries = append(report.Matches[i].Entries, e)
+	}
+
+	// Items no entry references are matched by amount to the closest
+	// unreferenced entry within the tolerance.
+	used := make([]bool, len(unreferenced))
+	for i, item := range items {
+		if len(report.Matches[i].Entries) > 0 {
+			continue
+		}
+
+		best := -1
+		for j, e := range unreferenced {
+			if used[j] || !e.Amount.Equal(item.Amount) || !within(e.Date, item.Date, tolerance) {
+				continue
+			}
+			if best == -1 || distance(e.Date, item.Date) < distance(unreferenced[best].Date, item.Date) {
+				best = j
+			}
+		}
+		if best != -1 {
+			used[best] = true
+			report.Matches[i].Entries = []Entry{unreferenced[best]}
+		}
+	}
+	for j, e := range unreferenced {
+		if !used[j] {
+			report.UnmatchedEntries = append(report.UnmatchedEntries, e)
+		}
+	}
+
+	for i, item := range items {
+		m := &report.Matches[i]
+		m.Item = item
+		m.Status, m.Difference = status(item, m.Entries, tolerance)
+		if inferred[i] && m.Status == Matched {
+			m.Status = Inferred
+		}
+	}
+	return report
+}
+
+// status returns the status of an item matched to entries, and the
+// difference between its amount and theirs.
+func status(item Item, entries []Entry, tolerance time.Duration) (Status, recurly.Money) {
+	total := 0
+	duplicate := len(entries) > 1
+	dated := true
+	for _, e := range entries {
+		if strings.EqualFold(e.Amount.Currency, item.Amount.Currency) {
+			total += e.Amount.Amount
+		}
+		if !e.Amount.Equal(item.Amount) {
+			duplicate = false
+		}
+		if !within(e.Date, item.Date, tolerance) {
+			dated = false
+		}
+	}
+
+	diff := recurly.NewMoney(item.Amount.Amount-total, item.Amount.Currency)
+	switch {
+	case len(entries) == 0:
+		return Unmatched, diff
+	case duplicate:
+		return Duplicate, diff
+	case diff.IsZero() && dated:
+		return Matched, diff
+	}
+	return Partial, diff
+}
+
+// booked reports whether entries total at least the amount of item, or at
+// most for credits.
+func booked(item Item, entries []Entry) bool {
+	if len(entries) == 0 {
+		return false
+	}
+
+	total := 0
+	for _, e := range entries {
+		if strings.EqualFold(e.Amount.Currency, item.Amount.Currency) {
+			total += e.Amount.Amount
+		}
+	}
+	if item.Amount.Amount < 0 {
+		return total <= item.Amount.Amount
+	}
+	return total >= item.Amount.Amount
+}
+
+// within reports whether a and b are no more than tolerance apart. Unknown
+// dates are within any tolerance.
+func within(a, b time.Time, tolerance time.Duration) bool {
+	if a.IsZero() || b.IsZero() {
+		return true
+	}
+	return distance(a, b) <= tolerance
+}
+
+func distance(a, b time.Time) time.Duration {
+	if d := a.Sub(b); d >= 0 {
+		return d
+	}
+	return b.Sub(a)
+}
+
+// normalize returns ref in the form references are compared in: without
+// surrounding space or dashes, in lower case.
+func normalize(ref string) string {
+	return strings.ToLower(recurly.SanitizeUUID(ref))
+}
+
+// invoiceID returns the invoice number of i with its prefix.
+func invoiceID(i recurly.Invoice) string {
+	return i.InvoiceNumberPrefix + strconv.Itoa(i.InvoiceNumber)
+}
@@ -0,0 +1,114 @@
+package reconcile
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+func TestReconcile(t *testing.T) {
+	day := time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)
+	usd := func(amount int) recurly.Money { return recurly.NewMoney(amount, "USD") }
+
+	items := []Item{
+		{Kind: Invoice, ID: "CN1005", References: []string{"CN1005", "1005"}, InvoiceNumber: 1005, Amount: usd(2500), Date: day},
+		{Kind: Transaction, ID: "t1", References: []string{"t1", "ch_123"}, InvoiceNumber: 1005, Amount: usd(2500), Date: day},
+		{Kind: CreditPayment, ID: "c1", References: []string{"c1"}, InvoiceNumber: 1005, Amount: usd(500), Date: day},
+		{Kind: Invoice, ID: "1006", References: []string{"1006"}, InvoiceNumber: 1006, Amount: usd(1000), Date: day},
+		{Kind: Transaction, ID: "t2", References: []string{"t2"}, InvoiceNumber: 1006, Amount: usd(1000), Date: day},
+		{Kind: Invoice, ID: "1007", References: []string{"1007"}, InvoiceNumber: 1007, Amount: usd(700), Date: day},
+	}
+	entries := []Entry{
+		{ID: "e1", Reference: " cn1005", Amount: usd(2500), Date: day},
+		{ID: "e2", Reference: "CH_123", Amount: usd(2000), Date: day},
+		{ID: "e3", Reference: "c1", Amount: usd(500), Date: day},
+		{ID: "e4", Reference: "c1", Amount: usd(500), Date: day.Add(time.Hour)},
+		{ID: "e5", Reference: "1006", Amount: usd(1000), Date: day.AddDate(0, 0, 5)},
+		{ID: "e6", Amount: usd(1000), Date: day.AddDate(0, 0, 4)},
+		{ID: "e7", Amount: usd(1000), Date: day.AddDate(0, 0, 2)},
+		{ID: "e8", Reference: "unknown", Amount: usd(100), Date: day},
+	}
+
+	report := Reconcile(items, entries, 3*24*time.Hour)
+
+	var statuses []Status
+	for _, m := range report.Matches {
+		statuses = append(statuses, m.Status)
+	}
+	if !reflect.DeepEqual(statuses, []Status{Matched, Partial, Duplicate, Partial, Matched, Unmatched}) {
+		t.Fatalf("unexpected statuses: %v", statuses)
+	}
+
+	if m := report.Matches[1]; m.Difference != usd(500) || len(m.Entries) != 1 || m.Entries[0].ID != "e2" {
+		t.Fatalf("unexpected partial match: %#v", m)
+	} else if m := report.Matches[2]; m.Difference != usd(-500) || len(m.Entries) != 2 {
+		t.Fatalf("unexpected duplicate match: %#v", m)
+	} else if m := report.Matches[3]; !m.Difference.IsZero() || m.Entries[0].ID != "e5" {
+		t.Fatalf("unexpected partial match: %#v", m)
+	} else if m := report.Matches[4]; len(m.Entries) != 1 || m.Entries[0].ID != "e7" {
+		t.Fatalf("unexpected amount match: %#v", m)
+	} else if m := report.Matches[5]; m.Difference != usd(700) || m.Item.InvoiceNumber != 1007 {
+		t.Fatalf("unexpected unmatched item: %#v", m)
+	}
+
+	var unmatched []string
+	for _, e := range report.UnmatchedEntries {
+		unmatched = append(unmatched, e.ID)
+	}
+	if !reflect.DeepEqual(unmatched, []string{"e6", "e8"}) {
+		t.Fatalf("unexpected unmatched entries: %v", unmatched)
+	} else if partial := report.Filter(Partial); len(partial) != 2 || partial[0].Item.ID != "t1" {
+		t.Fatalf("unexpected partial matches: %#v", partial)
+	}
+}
+
+func TestReconcile_Currency(t *testing.T) {
+	items := []Item{{Kind: Invoice, ID: "1001", References: []string{"1001"}, Amount: recurly.NewMoney(1000, "EUR")}}
+	entries := []Entry{{Reference: "1001", Amount: recurly.NewMoney(1000, "USD")}}
+
+	if m := Reconcile(items, entries, 0).Matches[0]; m.Status != Partial || m.Difference != recurly.NewMoney(1000, "EUR") {
+		t.Fatalf("unexpected match: %#v", m)
+	}
+	// Currencies are compared case-insensitively.
+	entries = []Entry{{Amount: recurly.Money{Amount: 1000, Currency: "eur"}}}
+	if m := Reconcile(items, entries, 0).Matches[0]; m.Status != Matched || !m.Difference.IsZero() {
+		t.Fatalf("unexpected match: %#v", m)
+	}
+}
+
+func TestReconcile_PaidInvoice(t *testing.T) {
+	day := time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)
+	usd := func(amount int) recurly.Money { return recurly.NewMoney(amount, "USD") }
+
+	// The receivable and the receipt are both booked against the invoice
+	// number.
+	items := []Item{
+		{Kind: Invoice, ID: "INV-1001", References: []string{"INV-1001", "1001"}, InvoiceNumber: 1001, Amount: usd(2500), Date: day},
+		{Kind: Transaction, ID: "t1", References: []string{"t1"}, InvoiceNumber: 1001, Amount: usd(2500), Date: day},
+		{Kind: Invoice, ID: "INV-1002", References: []string{"INV-1002", "1002"}, InvoiceNumber: 1002, Amount: usd(1000), Date: day},
+		{Kind: Transaction, ID: "t2", References: []string{"t2", "ch_456"}, InvoiceNumber: 1002, Amount: usd(1000), Date: day},
+	}
+	entries := []Entry{
+		{ID: "receipt", Reference: "INV-1001", Amount: usd(2500), Date: day},
+		{ID: "ar", Reference: "INV-1001", Amount: usd(2500), Date: day},
+		{ID: "e1", Reference: "1002", Amount: usd(1000), Date: day},
+		{ID: "e2", Reference: "INV-1002", Amount: usd(1000), Date: day},
+		{ID: "e3", Reference: "ch_456", Amount: usd(1000), Date: day},
+	}
+
+	report := Reconcile(items, entries, 0)
+
+	var statuses []Status
+	for _, m := range report.Matches {
+		statuses = append(statuses, m.Status)
+	}
+	if !reflect.DeepEqual(statuses, []Status{Matched, Inferred, Duplicate, Matched}) {
+		t.Fatalf("unexpected statuses: %v", statuses)
+	} else if m := report.Matches[1]; len(m.Entries) != 1 || m.Entries[0].ID != "ar" {
+		t.Fatalf("unexpected transaction match: %#v", m)
+	} else if len(report.UnmatchedEntries) != 0 {
+		t.Fatalf("unexpected unmatched entries: %#v", report.UnmatchedEntries)
+	}
+}
@@ -9,34 +9,22 @@ const (
 
 // Redemption holds redeemed coupons for an account or invoice.
 type Redemption struct {
-	UUID                   string
-	SubscriptionUUID       string
-	AccountCode            string
-	CouponCode             string
-	SingleUse              bool
-	TotalDiscountedInCents int
-	Currency               string
-	State                  string
-	CreatedAt              NullTime
-	UpdatedAt              NullTime
+	UUID                   string   `json:"uuid,omitempty"`
+	SubscriptionUUID       string   `json:"subscription_uuid,omitempty"`
+	AccountCode            string   `json:"account_code,omitempty"`
+	CouponCode             string   `json:"coupon_code,omitempty"`
+	SingleUse              bool     `json:"single_use,omitempty"`
+	TotalDiscountedInCents int      `json:"total_discounted_in_cents,omitempty"`
+	Currency               string   `json:"currency,omitempty"`
+	State                  string   `json:"state,omitempty"`
+	CreatedAt              NullTime `json:"created_at"`
+	UpdatedAt              NullTime `json:"updated_at"`
 }
 
 // UnmarshalXML unmarshal a coupon redemption object. Minaly converts href links
 // for coupons and accounts to CouponCode and AccountCodes.
 func (r *Redemption) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	var v struct {
-		XMLName                xml.Name   `xml:"redemption"`
-		AccountCode            hrefString `xml:"account"`
-		SubscriptionUUID       hrefString `xml:"subscription"`
-		UUID                   string     `xml:"uuid"`
-		SingleUse              bool       `xml:"single_use"`
-		CouponCode             string     `xml:"coupon_code"`
-		TotalDiscountedInCents int        `xml:"total_discounted_in_cents"`
-		Currency               string     `xml:"currency,omitempty"`
-		State                  string     `xml:"state,omitempty"`
-		CreatedAt              NullTime   `xml:"created_at,omitempty"`
-		UpdatedAt              NullTime   `xml:"updated_at,omitempty"`
-	}
+	var v redemptionFields
 	if err := d.DecodeElement(&v, &start); err != nil {
 		return err
 	}
@@ -54,3 +42,18 @@ func (r *Redemption) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 	}
 	return nil
 }
+
+// redemptionFields is used by the custom unmarshal function.
+type redemptionFields struct {
+	XMLName                xml.Name   `xml:"redemption"`
+	AccountCode            hrefString `xml:"account"`
+	SubscriptionUUID       hrefString `xml:"subscription"`
+	UUID                   string     `xml:"uuid"`
+	SingleUse              bool       `xml:"single_use"`
+	CouponCode             string     `xml:"coupon_code"`
+	TotalDiscountedInCents int        `xml:"total_discounted_in_cents"`
+	Currency               string     `xml:"currency,omitempty"`
+	State                  string     `xml:"state,omitempty"`
+	CreatedAt              NullTime   `xml:"created_at,omitempty"`
+	UpdatedAt              NullTime   `xml:"updated_at,omitempty"`
+}
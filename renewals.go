@@ -0,0 +1,168 @@
+package recurly
+
+import (
+	"errors"
+	"time"
+)
+
+// Plan interval units.
+const (
+	IntervalUnitDays   = "days"
+	IntervalUnitMonths = "months"
+)
+
+// ErrProjectionInterval is returned by ProjectRenewals when the plan has no
+// interval or an unknown interval unit.
+var ErrProjectionInterval = errors.New("recurly: plan interval is required to project renewals")
+
+// ErrProjectionPeriod is returned by ProjectRenewals when the subscription
+// has no next billing date.
+var ErrProjectionPeriod = errors.New("recurly: subscription has no current period to project renewals from")
+
+// Renewal is a projected invoice for a subscription.
+type Renewal struct {
+	// Date is when the invoice is created. It is also the start of the
+	// billing period it covers, as subscriptions are billed in advance.
+	Date      time.Time
+	PeriodEnd time.Time
+
+	PlanCode          string
+	UnitAmountInCents int
+	Quantity          int
+	AddOns            []SubscriptionAddOn
+
+	// AmountInCents is the plan and add-on total, before taxes and
+	// discounts.
+	AmountInCents int
+	Currency      string
+}
+
+// ProjectRenewals returns the next n invoices for sub. plan must be the plan
+// the subscription renews on, which is the pending plan if a change is
+// scheduled for renewal, and is used for the billing interval.
+//
+// Renewals start at the end of the current period, or trial. A pending
+// subscription change applies from the first renewal. Billing cycles
+// remaining in a pause are skipped. Subscriptions that are canceled or
+// expired, and those that do not auto renew once the current term ends,
+// have no further renewals, so fewer than n may be returned.
+//
+// The current term ends at CurrentTermEndsAt. If it is not set, the term
+// is the subscription's RenewalBillingCycles, or the plan's
+// TotalBillingCycles if that is not set either, counted from
+// CurrentTermStartedAt or, without it, from the first renewal. A
+// subscription that does not auto renew and has no term is projected to end
+// with its current period. Subscriptions that auto renew start a new term
+// of RenewalBillingCycles when the current one ends, which does not change
+// their invoices, so their renewals are not capped.
+func ProjectRenewals(sub Subscription, plan Plan, n int) ([]Renewal, error) {
+	if n <= 0 || sub.State == SubscriptionStateCanceled || sub.State == SubscriptionStateExpired {
+		return nil, nil
+	}
+	if plan.IntervalLength <= 0 || (plan.IntervalUnit != IntervalUnitDays && plan.IntervalUnit != IntervalUnitMonths) {
+		return nil, ErrProjectionInterval
+	}
+
+	var anchor time.Time
+	if sub.CurrentPeriodEndsAt.Time != nil {
+		anchor = *sub.CurrentPeriodEndsAt.Time
+	} else if sub.TrialEndsAt.Time != nil {
+		anchor = *sub.TrialEndsAt.Time
+	} else {
+		return nil, ErrProjectionPeriod
+	}
+
+	planCode, unitAmount, quantity, addOns := sub.Plan.Code, sub.UnitAmountInCents, sub.Quantity, sub.SubscriptionAddOns
+	if p := sub.PendingSubscription; p != nil {
+		if p.Plan.Code != "" {
+			planCode = p.Plan.Code
+		}
+		if p.UnitAmountInCents != 0 {
+			unitAmount = p.UnitAmountInCents
+		}
+		if p.Quantity != 0 {
+			quantity = p.Quantity
+		}
+		if p.SubscriptionAddOns != nil {
+			addOns = p.SubscriptionAddOns
+		}
+	}
+	if quantity == 0 {
+		quantity = 1
+	}
+
+	amount := unitAmount * quantity
+	for _, a := range addOns {
+		q := a.Quantity
+		if q == 0 {
+			q = 1
+		}
+		amount += a.UnitAmountInCents * q
+	}
+
+	var termEnd *time.Time
+	if !sub.AutoRenew {
+		termEnd = currentTermEnd(sub, plan, anchor)
+	}
+
+	var renewals []Renewal
+	for cycle := sub.RemainingPauseCycles; len(renewals) < n; cycle++ {
+		date := addInterval(anchor, plan, cycle)
+		if termEnd != nil && !date.Before(*termEnd) {
+			break
+		}
+
+		renewals = append(renewals, Renewal{
+			Date:              date,
+			PeriodEnd:         addInterval(anchor, plan, cycle+1),
+			PlanCode:          planCode,
+			UnitAmountInCents: unitAmount,
+			Quantity:          quantity,
+			AddOns:            addOns,
+			AmountInCents:     amount,
+			Currency:          sub.Currency,
+		})
+	}
+	return renewals, nil
+}
+
+// currentTermEnd returns when the subscription's current term ends. The
+// current period's end is returned if the term's length is not known.
+func currentTermEnd(sub Subscription, plan Plan, anchor time.Time) *time.Time {
+	if sub.CurrentTermEndsAt.Time != nil {
+		return sub.CurrentTermEndsAt.Time
+	}
+
+	cycles := sub.RenewalBillingCycles
+	if !cycles.Valid {
+		cycles = plan.TotalBillingCycles
+	}
+	if !cycles.Valid {
+		return &anchor
+	}
+
+	start := anchor
+	if sub.CurrentTermStartedAt.Time != nil {
+		start = *sub.CurrentTermStartedAt.Time
+	}
+	end := addInterval(start, plan, cycles.Int)
+	return &end
+}
+
+// addInterval returns the date cycles billing intervals after anchor.
+// Monthly intervals are added to the anchor's day of the month, clamped to
+// the end of shorter months, so January 31 renews on February 28 and then
+// March 31.
+func addInterval(anchor time.Time, plan Plan, cycles int) time.Time {
+	if plan.IntervalUnit == IntervalUnitDays {
+		return anchor.AddDate(0, 0, cycles*plan.IntervalLength)
+	}
+
+	months := cycles * plan.IntervalLength
+	first := time.Date(anchor.Year(), anchor.Month()+time.Month(months), 1, anchor.Hour(), anchor.Minute(), anchor.Second(), anchor.Nanosecond(), anchor.Location())
+	day := anchor.Day()
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
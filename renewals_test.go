@@ -0,0 +1,155 @@
+package recurly
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProjectRenewals(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+	}
+
+	sub := Subscription{
+		Plan:                   NestedPlan{Code: "gold"},
+		State:                  SubscriptionStateActive,
+		UnitAmountInCents:      1000,
+		Quantity:               2,
+		Currency:               "USD",
+		CurrentPeriodStartedAt: NewTime(date(2017, 12, 31)),
+		CurrentPeriodEndsAt:    NewTime(date(2018, 1, 31)),
+		SubscriptionAddOns: []SubscriptionAddOn{
+			{Code: "extra_users", UnitAmountInCents: 500, Quantity: 3},
+		},
+		AutoRenew: true,
+	}
+	plan := Plan{Code: "gold", IntervalUnit: IntervalUnitMonths, IntervalLength: 1}
+
+	renewals, err := ProjectRenewals(sub, plan, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	renewal := func(start, end time.Time) Renewal {
+		return Renewal{
+			Date:              start,
+			PeriodEnd:         end,
+			PlanCode:          "gold",
+			UnitAmountInCents: 1000,
+			Quantity:          2,
+			AddOns:            sub.SubscriptionAddOns,
+			AmountInCents:     3500,
+			Currency:          "USD",
+		}
+	}
+	if !reflect.DeepEqual(renewals, []Renewal{
+		renewal(date(2018, 1, 31), date(2018, 2, 28)),
+		renewal(date(2018, 2, 28), date(2018, 3, 31)),
+		renewal(date(2018, 3, 31), date(2018, 4, 30)),
+	}) {
+		t.Fatalf("unexpected renewals: %#v", renewals)
+	}
+
+	// Pending changes apply from the first renewal, and paused cycles are
+	// skipped.
+	pending := sub
+	pending.RemainingPauseCycles = 2
+	pending.PendingSubscription = &PendingSubscription{
+		Plan:               NestedPlan{Code: "weekly"},
+		UnitAmountInCents:  300,
+		SubscriptionAddOns: []SubscriptionAddOn{},
+	}
+	weekly := Plan{Code: "weekly", IntervalUnit: IntervalUnitDays, IntervalLength: 7}
+	if renewals, err := ProjectRenewals(pending, weekly, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(renewals, []Renewal{
+		{Date: date(2018, 2, 14), PeriodEnd: date(2018, 2, 21), PlanCode: "weekly", UnitAmountInCents: 300, Quantity: 2, AddOns: []SubscriptionAddOn{}, AmountInCents: 600, Currency: "USD"},
+		{Date: date(2018, 2, 21), PeriodEnd: date(2018, 2, 28), PlanCode: "weekly", UnitAmountInCents: 300, Quantity: 2, AddOns: []SubscriptionAddOn{}, AmountInCents: 600, Currency: "USD"},
+	}) {
+		t.Fatalf("unexpected renewals: %#v", renewals)
+	}
+
+	// Subscriptions that do not auto renew end with their term.
+	term := sub
+	term.AutoRenew = false
+	term.CurrentTermEndsAt = NewTime(date(2018, 3, 31))
+	if renewals, err := ProjectRenewals(term, plan, 12); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(renewals) != 2 || !renewals[1].Date.Equal(date(2018, 2, 28)) {
+		t.Fatalf("unexpected renewals: %#v", renewals)
+	}
+
+	// Without a term end, the term is counted from its start by
+	// RenewalBillingCycles, or by the plan's TotalBillingCycles.
+	cycles := sub
+	cycles.AutoRenew = false
+	cycles.CurrentTermStartedAt = NewTime(date(2017, 12, 31))
+	cycles.RenewalBillingCycles = NewInt(3)
+	if renewals, err := ProjectRenewals(cycles, plan, 12); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(renewals) != 2 || !renewals[1].Date.Equal(date(2018, 2, 28)) {
+		t.Fatalf("unexpected renewals: %#v", renewals)
+	}
+
+	cycles.RenewalBillingCycles = NullInt{}
+	termPlan := plan
+	termPlan.TotalBillingCycles = NewInt(6)
+	if renewals, err := ProjectRenewals(cycles, termPlan, 12); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(renewals) != 5 || !renewals[4].Date.Equal(date(2018, 5, 31)) {
+		t.Fatalf("unexpected renewals: %#v", renewals)
+	}
+
+	// Subscriptions that do not auto renew and have no term end with the
+	// current period.
+	if renewals, err := ProjectRenewals(cycles, plan, 12); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if renewals != nil {
+		t.Fatalf("unexpected renewals: %#v", renewals)
+	}
+
+	// Auto renewing subscriptions are not capped by their term.
+	renewing := cycles
+	renewing.AutoRenew = true
+	renewing.RenewalBillingCycles = NewInt(3)
+	if renewals, err := ProjectRenewals(renewing, plan, 12); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(renewals) != 12 {
+		t.Fatalf("unexpected renewals: %#v", renewals)
+	}
+
+	// Canceled subscriptions do not renew.
+	canceled := sub
+	canceled.State = SubscriptionStateCanceled
+	if renewals, err := ProjectRenewals(canceled, plan, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if renewals != nil {
+		t.Fatalf("unexpected renewals: %#v", renewals)
+	}
+
+	// Trials are billed when they end.
+	trial := sub
+	trial.CurrentPeriodEndsAt = NullTime{}
+	trial.TrialEndsAt = NewTime(date(2018, 1, 15))
+	if renewals, err := ProjectRenewals(trial, plan, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(renewals) != 1 || !renewals[0].Date.Equal(date(2018, 1, 15)) || !renewals[0].PeriodEnd.Equal(date(2018, 2, 15)) {
+		t.Fatalf("unexpected renewals: %#v", renewals)
+	}
+}
+
+func TestProjectRenewals_Errors(t *testing.T) {
+	sub := Subscription{State: SubscriptionStateActive, CurrentPeriodEndsAt: NewTime(time.Now())}
+
+	if _, err := ProjectRenewals(sub, Plan{IntervalUnit: "years", IntervalLength: 1}, 1); err != ErrProjectionInterval {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := ProjectRenewals(sub, Plan{IntervalUnit: IntervalUnitMonths}, 1); err != ErrProjectionInterval {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sub.CurrentPeriodEndsAt = NullTime{}
+	if _, err := ProjectRenewals(sub, Plan{IntervalUnit: IntervalUnitMonths, IntervalLength: 1}, 1); err != ErrProjectionPeriod {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
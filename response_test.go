@@ -3,6 +3,7 @@ package recurly
 import (
 	"net/http"
 	"testing"
+	"time"
 )
 
 func TestResponse_ConvenienceMethods(t *testing.T) {
@@ -98,3 +99,26 @@ func TestResponse_CursorLinkParsing(t *testing.T) {
 		t.Fatalf("unexpected next: %s", resp2.Next())
 	}
 }
+
+func TestResponse_RateLimit(t *testing.T) {
+	resp0 := &Response{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"X-Ratelimit-Limit":     {"2000"},
+				"X-Ratelimit-Remaining": {"1998"},
+				"X-Ratelimit-Reset":     {"1510617600"},
+			},
+		},
+	}
+	if rl, ok := resp0.RateLimit(); !ok {
+		t.Fatal("expected rate limit")
+	} else if rl.Limit != 2000 || rl.Remaining != 1998 || !rl.Reset.Equal(time.Unix(1510617600, 0)) {
+		t.Fatalf("unexpected rate limit: %#v", rl)
+	}
+
+	resp1 := &Response{Response: &http.Response{StatusCode: http.StatusOK}}
+	if rl, ok := resp1.RateLimit(); ok {
+		t.Fatalf("unexpected rate limit: %#v", rl)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Response is returned for each API call.
@@ -17,6 +19,10 @@ type Response struct {
 
 	// transaction holds the transaction returned with a transaction error.
 	transaction *Transaction
+
+	// UnknownElements holds the elements that could not be mapped to a
+	// field when the client was created with WithStrictDecoding.
+	UnknownElements UnknownElements
 }
 
 var (
@@ -93,6 +99,37 @@ func (r *Response) Next() string {
 	return ""
 }
 
+// RateLimit describes the API rate limit returned with a response.
+type RateLimit struct {
+	// Limit is the number of requests allowed in the current window.
+	Limit int
+
+	// Remaining is the number of requests left in the current window.
+	Remaining int
+
+	// Reset is when the current window ends.
+	Reset time.Time
+}
+
+// RateLimit parses the X-RateLimit-* headers. ok is false if the response
+// does not include them.
+func (r *Response) RateLimit() (rl RateLimit, ok bool) {
+	if r.Response == nil || r.Header.Get("X-RateLimit-Remaining") == "" {
+		return rl, false
+	}
+
+	remaining, err := strconv.Atoi(r.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return rl, false
+	}
+	rl.Remaining = remaining
+	rl.Limit, _ = strconv.Atoi(r.Header.Get("X-RateLimit-Limit"))
+	if reset, err := strconv.ParseInt(r.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rl.Reset = time.Unix(reset, 0)
+	}
+	return rl, true
+}
+
 // Error is an individual validation error
 type Error struct {
 	XMLName xml.Name `xml:"error"`
@@ -6,35 +6,30 @@ import (
 
 // ShippingAddress represents a shipping address
 type ShippingAddress struct {
-	XMLName     xml.Name `xml:"shipping_address"`
-	AccountCode string   `xml:"account,omitempty"`
-	ID          int64    `xml:"id,omitempty"`
-	FirstName   string   `xml:"first_name"`
-	LastName    string   `xml:"last_name"`
-	Nickname    string   `xml:"nickname,omitempty"`
-	Address     string   `xml:"address1"`
-	Address2    string   `xml:"address2"`
-	Company     string   `xml:"company,omitempty"`
-	City        string   `xml:"city"`
-	State       string   `xml:"state"`
-	Zip         string   `xml:"zip"`
-	Country     string   `xml:"country"`
-	Phone       string   `xml:"phone,omitempty"`
-	Email       string   `xml:"email,omitempty"`
-	VATNumber   string   `xml:"vat_number,omitempty"`
-	CreatedAt   NullTime `xml:"created_at,omitempty"`
-	UpdatedAt   NullTime `xml:"updated_at,omitempty"`
+	XMLName     xml.Name `xml:"shipping_address" json:"-"`
+	AccountCode string   `xml:"account,omitempty" json:"account,omitempty"`
+	ID          int64    `xml:"id,omitempty" json:"id,omitempty"`
+	FirstName   string   `xml:"first_name" json:"first_name,omitempty"`
+	LastName    string   `xml:"last_name" json:"last_name,omitempty"`
+	Nickname    string   `xml:"nickname,omitempty" json:"nickname,omitempty"`
+	Address     string   `xml:"address1" json:"address1,omitempty"`
+	Address2    string   `xml:"address2" json:"address2,omitempty"`
+	Company     string   `xml:"company,omitempty" json:"company,omitempty"`
+	City        string   `xml:"city" json:"city,omitempty"`
+	State       string   `xml:"state" json:"state,omitempty"`
+	Zip         string   `xml:"zip" json:"zip,omitempty"`
+	Country     string   `xml:"country" json:"country,omitempty"`
+	Phone       string   `xml:"phone,omitempty" json:"phone,omitempty"`
+	Email       string   `xml:"email,omitempty" json:"email,omitempty"`
+	VATNumber   string   `xml:"vat_number,omitempty" json:"vat_number,omitempty"`
+	CreatedAt   NullTime `xml:"created_at,omitempty" json:"created_at"`
+	UpdatedAt   NullTime `xml:"updated_at,omitempty" json:"updated_at"`
 }
 
 // UnmarshalXML unmarshals shipping addresses and handles intermediary state during unmarshaling
 // for types like href.
 func (s *ShippingAddress) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	type shippingAddressAlias ShippingAddress
-	var a struct {
-		shippingAddressAlias
-		XMLName xml.Name   `xml:"shipping_address"`
-		Account hrefString `xml:"account,omitempty"`
-	}
+	var a shippingAddressFields
 	if err := d.DecodeElement(&a, &start); err != nil {
 		return err
 	}
@@ -42,3 +37,12 @@ func (s *ShippingAddress) UnmarshalXML(d *xml.Decoder, start xml.StartElement) e
 	s.AccountCode = string(a.Account)
 	return nil
 }
+
+type shippingAddressAlias ShippingAddress
+
+// shippingAddressFields is used by the custom unmarshal function.
+type shippingAddressFields struct {
+	shippingAddressAlias
+	XMLName xml.Name   `xml:"shipping_address"`
+	Account hrefString `xml:"account,omitempty"`
+}
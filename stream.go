@@ -0,0 +1,325 @@
+package recurly
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// elementStream walks a response body token by token and stops at each
+// element accepted by match so it can be decoded on its own. Only the
+// element being decoded is held in memory.
+type elementStream struct {
+	body    io.ReadCloser
+	decoder *xml.Decoder
+	match   func(parent, name string) bool
+	path    []string
+	start   xml.StartElement
+	err     error
+}
+
+// matchChild returns a matcher for elements named name whose parent
+// element is named parent. For example, matchChild("invoices", "invoice")
+// matches each invoice in a list response.
+func matchChild(parent, name string) func(string, string) bool {
+	return func(p, n string) bool {
+		return p == parent && n == name
+	}
+}
+
+// next advances to the next matching element. It returns false when the
+// body is exhausted or an error occurred.
+func (s *elementStream) next() bool {
+	if s.body == nil || s.err != nil {
+		return false
+	}
+
+	for {
+		tok, err := s.decoder.Token()
+		if err == io.EOF {
+			return false
+		} else if err != nil {
+			s.err = err
+			return false
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			var parent string
+			if len(s.path) > 0 {
+				parent = s.path[len(s.path)-1]
+			}
+			if s.match(parent, t.Name.Local) {
+				s.start = t.Copy()
+				return true
+			}
+			s.path = append(s.path, t.Name.Local)
+		case xml.EndElement:
+			if len(s.path) > 0 {
+				s.path = s.path[:len(s.path)-1]
+			}
+		}
+	}
+}
+
+// decode decodes the current element into v using v's UnmarshalXML
+// implementation if it has one.
+func (s *elementStream) decode(v interface{}) bool {
+	if err := s.decoder.DecodeElement(v, &s.start); err != nil {
+		s.err = err
+		return false
+	}
+	return true
+}
+
+// close closes the response body.
+func (s *elementStream) close() error {
+	if s.body == nil {
+		return nil
+	}
+	return s.body.Close()
+}
+
+// stream takes a prepared API request and makes the API call to Recurly
+// like do, but instead of decoding the body it returns a stream over the
+// elements accepted by match. The caller must close the stream.
+// If the request was not successful the errors are parsed onto the Response
+// and an empty stream is returned.
+func (c *Client) stream(req *http.Request, match func(parent, name string) bool) (*Response, *elementStream, error) {
+	req.Close = true
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, &elementStream{}, err
+	}
+
+	response := &Response{Response: resp}
+	if response.IsError() || resp.StatusCode == http.StatusNoContent {
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusNoContent {
+			return response, &elementStream{}, nil
+		}
+		return response, &elementStream{}, decodeErrors(response, xml.NewDecoder(resp.Body))
+	}
+
+	return response, &elementStream{
+		body:    resp.Body,
+		decoder: xml.NewDecoder(resp.Body),
+		match:   match,
+	}, nil
+}
+
+// get makes a GET request for action and returns a stream over the
+// elements accepted by match. The stream is never nil, so it can be closed
+// even if an error is returned.
+func (c *Client) get(action string, params Params, match func(parent, name string) bool) (*Response, *elementStream, error) {
+	req, err := c.newRequest("GET", action, params, nil)
+	if err != nil {
+		return nil, &elementStream{}, err
+	}
+	return c.stream(req, match)
+}
+
+// StreamInvoices is like Invoices.List but decodes invoices one at a time as
+// they are read from the response body, keeping memory flat for large
+// pages. The stream is never nil and the caller must close it.
+func (c *Client) StreamInvoices(params Params) (*Response, *InvoiceStream, error) {
+	resp, stream, err := c.get("invoices", params, matchChild("invoices", "invoice"))
+	return resp, &InvoiceStream{stream: stream}, err
+}
+
+// StreamAccountInvoices is like Invoices.ListAccount but decodes invoices
+// one at a time as they are read from the response body. The stream is
+// never nil and the caller must close it.
+func (c *Client) StreamAccountInvoices(accountCode string, params Params) (*Response, *InvoiceStream, error) {
+	action := fmt.Sprintf("accounts/%s/invoices", accountCode)
+	resp, stream, err := c.get(action, params, matchChild("invoices", "invoice"))
+	return resp, &InvoiceStream{stream: stream}, err
+}
+
+// StreamInvoiceDetails decodes the line items and transactions of an
+// invoice one at a time instead of holding the whole invoice in memory like
+// Invoices.Get does. The stream is never nil and the caller must close it.
+func (c *Client) StreamInvoiceDetails(invoiceNumber int) (*Response, *InvoiceDetailsStream, error) {
+	action := fmt.Sprintf("invoices/%d", invoiceNumber)
+	resp, stream, err := c.get(action, nil, matchInvoiceDetails)
+	return resp, &InvoiceDetailsStream{stream: stream}, err
+}
+
+// StreamTransactions is like Transactions.List but decodes transactions one
+// at a time as they are read from the response body. The stream is never
+// nil and the caller must close it.
+func (c *Client) StreamTransactions(params Params) (*Response, *TransactionStream, error) {
+	resp, stream, err := c.get("transactions", params, matchChild("transactions", "transaction"))
+	return resp, &TransactionStream{stream: stream}, err
+}
+
+// StreamAccountTransactions is like Transactions.ListAccount but decodes
+// transactions one at a time as they are read from the response body. The
+// stream is never nil and the caller must close it.
+func (c *Client) StreamAccountTransactions(accountCode string, params Params) (*Response, *TransactionStream, error) {
+	action := fmt.Sprintf("accounts/%s/transactions", accountCode)
+	resp, stream, err := c.get(action, params, matchChild("transactions", "transaction"))
+	return resp, &TransactionStream{stream: stream}, err
+}
+
+// StreamAdjustments is like Adjustments.List but decodes adjustments one at
+// a time as they are read from the response body. The stream is never nil
+// and the caller must close it.
+func (c *Client) StreamAdjustments(accountCode string, params Params) (*Response, *AdjustmentStream, error) {
+	action := fmt.Sprintf("accounts/%s/adjustments", accountCode)
+	resp, stream, err := c.get(action, params, matchChild("adjustments", "adjustment"))
+	return resp, &AdjustmentStream{stream: stream}, err
+}
+
+// InvoiceStream iterates over the invoices of a single page of a list
+// response, decoding one invoice at a time.
+//
+//	resp, stream, err := client.StreamInvoices(recurly.Params{"per_page": 200})
+//	defer stream.Close()
+//	for stream.Next() {
+//		invoice := stream.Invoice()
+//	}
+//	if err := stream.Err(); err != nil {
+//		// Handle error
+//	}
+type InvoiceStream struct {
+	stream  *elementStream
+	invoice Invoice
+}
+
+// Next decodes the next invoice. It returns false when there are no more
+// invoices or an error occurred.
+func (s *InvoiceStream) Next() bool {
+	s.invoice = Invoice{}
+	return s.stream.next() && s.stream.decode(&s.invoice)
+}
+
+// Invoice returns the current invoice. It is only valid until the next
+// call to Next.
+func (s *InvoiceStream) Invoice() *Invoice {
+	return &s.invoice
+}
+
+// Err returns the first error encountered while streaming.
+func (s *InvoiceStream) Err() error {
+	return s.stream.err
+}
+
+// Close closes the underlying response body.
+func (s *InvoiceStream) Close() error {
+	return s.stream.close()
+}
+
+// InvoiceDetailsStream iterates over the line items and transactions of a
+// single invoice, decoding one at a time. Unlike InvoicesService.Get,
+// transactions are returned in the order Recurly sends them.
+type InvoiceDetailsStream struct {
+	stream      *elementStream
+	lineItem    *Adjustment
+	transaction *Transaction
+}
+
+// Next decodes the next line item or transaction. It returns false when
+// there are no more elements or an error occurred.
+func (s *InvoiceDetailsStream) Next() bool {
+	s.lineItem, s.transaction = nil, nil
+	if !s.stream.next() {
+		return false
+	}
+
+	if s.stream.start.Name.Local == "adjustment" {
+		s.lineItem = &Adjustment{}
+		return s.stream.decode(s.lineItem)
+	}
+	s.transaction = &Transaction{}
+	return s.stream.decode(s.transaction)
+}
+
+// LineItem returns the current line item, or nil if the current element
+// is a transaction.
+func (s *InvoiceDetailsStream) LineItem() *Adjustment {
+	return s.lineItem
+}
+
+// Transaction returns the current transaction, or nil if the current
+// element is a line item.
+func (s *InvoiceDetailsStream) Transaction() *Transaction {
+	return s.transaction
+}
+
+// Err returns the first error encountered while streaming.
+func (s *InvoiceDetailsStream) Err() error {
+	return s.stream.err
+}
+
+// Close closes the underlying response body.
+func (s *InvoiceDetailsStream) Close() error {
+	return s.stream.close()
+}
+
+// matchInvoiceDetails matches the line items and transactions of an invoice.
+func matchInvoiceDetails(parent, name string) bool {
+	return (parent == "line_items" && name == "adjustment") ||
+		(parent == "transactions" && name == "transaction")
+}
+
+// TransactionStream iterates over the transactions of a single page of a
+// list response, decoding one transaction at a time.
+type TransactionStream struct {
+	stream      *elementStream
+	transaction Transaction
+}
+
+// Next decodes the next transaction. It returns false when there are no
+// more transactions or an error occurred.
+func (s *TransactionStream) Next() bool {
+	s.transaction = Transaction{}
+	return s.stream.next() && s.stream.decode(&s.transaction)
+}
+
+// Transaction returns the current transaction. It is only valid until the
+// next call to Next.
+func (s *TransactionStream) Transaction() *Transaction {
+	return &s.transaction
+}
+
+// Err returns the first error encountered while streaming.
+func (s *TransactionStream) Err() error {
+	return s.stream.err
+}
+
+// Close closes the underlying response body.
+func (s *TransactionStream) Close() error {
+	return s.stream.close()
+}
+
+// AdjustmentStream iterates over the adjustments of a single page of a
+// list response, decoding one adjustment at a time.
+type AdjustmentStream struct {
+	stream     *elementStream
+	adjustment Adjustment
+}
+
+// Next decodes the next adjustment. It returns false when there are no
+// more adjustments or an error occurred.
+func (s *AdjustmentStream) Next() bool {
+	s.adjustment = Adjustment{}
+	return s.stream.next() && s.stream.decode(&s.adjustment)
+}
+
+// Adjustment returns the current adjustment. It is only valid until the
+// next call to Next.
+func (s *AdjustmentStream) Adjustment() *Adjustment {
+	return &s.adjustment
+}
+
+// Err returns the first error encountered while streaming.
+func (s *AdjustmentStream) Err() error {
+	return s.stream.err
+}
+
+// Close closes the underlying response body.
+func (s *AdjustmentStream) Close() error {
+	return s.stream.close()
+}
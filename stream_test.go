@@ -0,0 +1,391 @@
+package recurly
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// invoicesXML generates a list response with n invoices, each with the
+// given number of line items and transactions.
+func invoicesXML(n, lineItems, transactions int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?><invoices type="array">`)
+	for i := 0; i < n; i++ {
+		writeInvoiceXML(&buf, 1000+i, lineItems, transactions)
+	}
+	buf.WriteString(`</invoices>`)
+	return buf.Bytes()
+}
+
+func writeInvoiceXML(buf *bytes.Buffer, number, lineItems, transactions int) {
+	fmt.Fprintf(buf, `<invoice href="https://your-subdomain.recurly.com/v2/invoices/%d">
+		<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+		<uuid>invoice-%d</uuid>
+		<state>paid</state>
+		<invoice_number type="integer">%d</invoice_number>
+		<total_in_cents type="integer">%d</total_in_cents>
+		<currency>USD</currency>
+		<created_at type="datetime">2018-06-05T15:44:57Z</created_at>
+		<line_items type="array">`, number, number, number, lineItems*1500)
+	for j := 0; j < lineItems; j++ {
+		writeAdjustmentXML(buf, number, j)
+	}
+	buf.WriteString(`</line_items><transactions type="array">`)
+	for j := 0; j < transactions; j++ {
+		writeTransactionXML(buf, number, j)
+	}
+	buf.WriteString(`</transactions></invoice>`)
+}
+
+func writeAdjustmentXML(buf *bytes.Buffer, number, i int) {
+	fmt.Fprintf(buf, `<adjustment href="https://your-subdomain.recurly.com/v2/adjustments/adj-%d-%d" type="charge">
+		<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+		<invoice href="https://your-subdomain.recurly.com/v2/invoices/%d"/>
+		<uuid>adj-%d-%d</uuid>
+		<state>invoiced</state>
+		<description>Domains</description>
+		<unit_amount_in_cents type="integer">1500</unit_amount_in_cents>
+		<quantity type="integer">1</quantity>
+		<total_in_cents type="integer">1500</total_in_cents>
+		<currency>USD</currency>
+		<start_date type="datetime">2018-06-05T15:44:56Z</start_date>
+	</adjustment>`, number, i, number, number, i)
+}
+
+func writeTransactionXML(buf *bytes.Buffer, number, i int) {
+	fmt.Fprintf(buf, `<transaction href="https://your-subdomain.recurly.com/v2/transactions/txn-%d-%d" type="credit_card">
+		<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+		<invoice href="https://your-subdomain.recurly.com/v2/invoices/%d"/>
+		<uuid>txn-%d-%d</uuid>
+		<action>purchase</action>
+		<amount_in_cents type="integer">1500</amount_in_cents>
+		<currency>USD</currency>
+		<status>success</status>
+		<created_at type="datetime">2018-06-05T15:4%d:57Z</created_at>
+		<details>
+			<account>
+				<account_code>1</account_code>
+			</account>
+		</details>
+	</transaction>`, number, i, number, number, i, 9-i%10)
+}
+
+func TestClient_StreamInvoices(t *testing.T) {
+	setup()
+	defer teardown()
+
+	body := invoicesXML(3, 2, 1)
+	mux.HandleFunc("/v2/invoices", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if r.URL.Query().Get("per_page") != "200" {
+			t.Fatalf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Link", `<https://your-subdomain.recurly.com/v2/invoices?cursor=1304958672>; rel="next"`)
+		w.WriteHeader(200)
+		w.Write(body)
+	})
+
+	_, expected, err := client.Invoices.List(Params{"per_page": 200})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, stream, err := client.StreamInvoices(Params{"per_page": 200})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+
+	var given []Invoice
+	for stream.Next() {
+		given = append(given, *stream.Invoice())
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.IsError() {
+		t.Fatal("expected stream invoices to return OK")
+	} else if resp.Next() != "1304958672" {
+		t.Fatalf("unexpected cursor: %s", resp.Next())
+	} else if len(given) != 3 {
+		t.Fatalf("unexpected length: %d", len(given))
+	} else if !reflect.DeepEqual(expected, given) {
+		t.Fatalf("unexpected invoices: %#v", given)
+	}
+}
+
+func TestClient_StreamInvoices_ErrNotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/accounts/1/invoices", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<error>
+				<symbol>not_found</symbol>
+				<description lang="en-US">Couldn't find Account with account_code = 1</description>
+			</error>`)
+	})
+
+	resp, stream, err := client.StreamAccountInvoices("1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unexpected status: %d", resp.StatusCode)
+	} else if len(resp.Errors) != 1 || resp.Errors[0].Symbol != "not_found" {
+		t.Fatalf("unexpected errors: %#v", resp.Errors)
+	} else if stream.Next() {
+		t.Fatal("expected empty stream")
+	} else if stream.Err() != nil {
+		t.Fatalf("unexpected error: %v", stream.Err())
+	} else if err := stream.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestClient_StreamInvoices_DecodeError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/invoices", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<invoices type="array">
+				<invoice><invoice_number type="integer">1000</invoice_number></invoice>
+				<invoice><created_at type="datetime">ABC</created_at></invoice>
+				<invoice><invoice_number type="integer">1002</invoice_number></invoice>
+			</invoices>`)
+	})
+
+	_, stream, err := client.StreamInvoices(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+
+	var count int
+	for stream.Next() {
+		count++
+	}
+	if count != 1 {
+		t.Fatalf("expected stream to stop at the invalid invoice, given %d", count)
+	} else if stream.Err() == nil {
+		t.Fatal("expected time.Parse error. None given.")
+	}
+}
+
+// TestClient_Stream_RequestError tests that streams can be closed when the
+// request could not be made.
+func TestClient_Stream_RequestError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	for name, c := range map[string]*Client{
+		"invalid url":   NewClient("test", "abc", nil, WithBaseURL("://invalid")),
+		"closed server": NewClient("test", "abc", nil, WithBaseURL(server.URL)),
+	} {
+		_, invoices, err := c.StreamInvoices(nil)
+		if err == nil {
+			t.Fatalf("%s: expected error", name)
+		} else if invoices.Next() {
+			t.Fatalf("%s: expected empty stream", name)
+		} else if err := invoices.Close(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		_, details, _ := c.StreamInvoiceDetails(1005)
+		_, transactions, _ := c.StreamTransactions(nil)
+		_, accountTransactions, _ := c.StreamAccountTransactions("1", nil)
+		_, adjustments, _ := c.StreamAdjustments("1", nil)
+		if details.Next() || transactions.Next() || accountTransactions.Next() || adjustments.Next() {
+			t.Fatalf("%s: expected empty streams", name)
+		}
+		details.Close()
+		transactions.Close()
+		accountTransactions.Close()
+		adjustments.Close()
+	}
+}
+
+func TestClient_StreamInvoiceDetails(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
+	writeInvoiceXML(&buf, 1005, 3, 2)
+	mux.HandleFunc("/v2/invoices/1005", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		w.WriteHeader(200)
+		w.Write(buf.Bytes())
+	})
+
+	_, invoice, err := client.Invoices.Get(1005)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, stream, err := client.StreamInvoiceDetails(1005)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+
+	var lineItems []Adjustment
+	var transactions []Transaction
+	for stream.Next() {
+		if a := stream.LineItem(); a != nil {
+			lineItems = append(lineItems, *a)
+		} else if txn := stream.Transaction(); txn != nil {
+			transactions = append(transactions, *txn)
+		} else {
+			t.Fatal("expected line item or transaction")
+		}
+	}
+
+	// Get sorts transactions, the stream returns them in document order.
+	sort.Sort(Transactions(transactions))
+	if err := stream.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(invoice.LineItems, lineItems) {
+		t.Fatalf("unexpected line items: %#v", lineItems)
+	} else if !reflect.DeepEqual(invoice.Transactions, transactions) {
+		t.Fatalf("unexpected transactions: %#v", transactions)
+	}
+}
+
+func TestClient_StreamAccountTransactions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?><transactions type="array">`)
+	for i := 0; i < 4; i++ {
+		writeTransactionXML(&buf, 1000, i)
+	}
+	buf.WriteString(`</transactions>`)
+
+	mux.HandleFunc("/v2/accounts/1/transactions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.Write(buf.Bytes())
+	})
+
+	_, expected, err := client.Transactions.ListAccount("1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, stream, err := client.StreamAccountTransactions("1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+
+	var given []Transaction
+	for stream.Next() {
+		given = append(given, *stream.Transaction())
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(given) != 4 {
+		t.Fatalf("unexpected length: %d", len(given))
+	} else if !reflect.DeepEqual(expected, given) {
+		t.Fatalf("unexpected transactions: %#v", given)
+	}
+}
+
+func TestClient_StreamAdjustments(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?><adjustments type="array">`)
+	for i := 0; i < 5; i++ {
+		writeAdjustmentXML(&buf, 1000, i)
+	}
+	buf.WriteString(`</adjustments>`)
+
+	mux.HandleFunc("/v2/accounts/1/adjustments", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.Write(buf.Bytes())
+	})
+
+	_, expected, err := client.Adjustments.List("1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, stream, err := client.StreamAdjustments("1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+
+	var given []Adjustment
+	for stream.Next() {
+		given = append(given, *stream.Adjustment())
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(given) != 5 {
+		t.Fatalf("unexpected length: %d", len(given))
+	} else if !reflect.DeepEqual(expected, given) {
+		t.Fatalf("unexpected adjustments: %#v", given)
+	}
+}
+
+// benchmarkInvoices serves a page of 200 invoices with 50 line items each.
+func benchmarkInvoices(b *testing.B) {
+	body := invoicesXML(200, 50, 2)
+	setup()
+	mux.HandleFunc("/v2/invoices", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.Write(body)
+	})
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	b.ResetTimer()
+}
+
+func BenchmarkInvoices_List(b *testing.B) {
+	benchmarkInvoices(b)
+	defer teardown()
+
+	for i := 0; i < b.N; i++ {
+		_, invoices, err := client.Invoices.List(Params{"per_page": 200})
+		if err != nil {
+			b.Fatal(err)
+		}
+		var total int
+		for _, invoice := range invoices {
+			total += invoice.TotalInCents
+		}
+	}
+}
+
+func BenchmarkInvoices_Stream(b *testing.B) {
+	benchmarkInvoices(b)
+	defer teardown()
+
+	for i := 0; i < b.N; i++ {
+		_, stream, err := client.StreamInvoices(Params{"per_page": 200})
+		if err != nil {
+			b.Fatal(err)
+		}
+		var total int
+		for stream.Next() {
+			total += stream.Invoice().TotalInCents
+		}
+		if err := stream.Err(); err != nil {
+			b.Fatal(err)
+		}
+		stream.Close()
+	}
+}
@@ -0,0 +1,281 @@
+package recurly
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// UnknownElements maps a type name (such as "Account" or "Invoice") to the
+// element paths found in a response that could not be mapped to a field of
+// that type. Paths are relative to the type's element, for example
+// "address/street3" for an unknown element inside an account's address.
+type UnknownElements map[string][]string
+
+// WithStrictDecoding enables a diagnostic mode that records any elements
+// in successful responses that could not be mapped to a field. The result is
+// available on Response.UnknownElements. If fn is not nil it is called with
+// each response that contained unknown elements.
+// Streamed responses are not inspected.
+func WithStrictDecoding(fn func(*Response)) Option {
+	return func(c *Client) {
+		c.strict = true
+		c.onUnknownElements = fn
+	}
+}
+
+// FindUnknownElements decodes the XML document in r and returns the element
+// paths that could not be mapped to a field of v, which should be the same
+// type passed when decoding the document. It is useful for detecting API
+// drift against recorded responses. A nil map is returned if every element
+// could be mapped.
+func FindUnknownElements(r io.Reader, v interface{}) (UnknownElements, error) {
+	d := xml.NewDecoder(r)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if start, ok := tok.(xml.StartElement); ok {
+			w := unknownWalker{}
+			t := reflect.TypeOf(v)
+			owner := start.Name.Local
+			if name := indirectType(t).Name(); name != "" {
+				owner = name
+			}
+			if err := w.walk(d, t, owner, ""); err != nil {
+				return nil, err
+			}
+			return w.result(), nil
+		}
+	}
+}
+
+// decodeStrict decodes body into v and records unknown elements on the response.
+func (c *Client) decodeStrict(response *Response, body io.Reader, v interface{}) error {
+	var buf bytes.Buffer
+	if err := xml.NewDecoder(io.TeeReader(body, &buf)).Decode(&v); err != nil {
+		return err
+	}
+
+	unknown, err := FindUnknownElements(&buf, v)
+	if err != nil {
+		return err
+	}
+
+	response.UnknownElements = unknown
+	if len(unknown) > 0 && c.onUnknownElements != nil {
+		c.onUnknownElements(response)
+	}
+	return nil
+}
+
+// decodeSchemas maps types with custom UnmarshalXML functions to the
+// struct they decode into, so their fields can be inspected.
+var decodeSchemas = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(Adjustment{}):        reflect.TypeOf(adjustmentFields{}),
+	reflect.TypeOf(Billing{}):           reflect.TypeOf(billingFields{}),
+	reflect.TypeOf(CreditPayment{}):     reflect.TypeOf(creditPaymentFields{}),
+	reflect.TypeOf(Invoice{}):           reflect.TypeOf(invoiceFields{}),
+	reflect.TypeOf(InvoiceCollection{}): reflect.TypeOf(invoiceCollectionSchema{}),
+	reflect.TypeOf(Redemption{}):        reflect.TypeOf(redemptionFields{}),
+	reflect.TypeOf(ShippingAddress{}):   reflect.TypeOf(shippingAddressFields{}),
+	reflect.TypeOf(Subscription{}):      reflect.TypeOf(subscriptionFields{}),
+	reflect.TypeOf(Transaction{}):       reflect.TypeOf(transactionFields{}),
+}
+
+// invoiceCollectionSchema describes the elements InvoiceCollection.UnmarshalXML reads.
+type invoiceCollectionSchema struct {
+	ChargeInvoice Invoice `xml:"charge_invoice"`
+}
+
+var unmarshalerType = reflect.TypeOf((*xml.Unmarshaler)(nil)).Elem()
+
+// schemaNode is an element that can be mapped. Nodes created from paths
+// such as "line_items>adjustment" have children but no type.
+type schemaNode struct {
+	typ      reflect.Type
+	children map[string]*schemaNode
+}
+
+var schemaCache sync.Map // map[reflect.Type]*schemaNode
+
+// schemaFor returns the child elements a struct type can map.
+func schemaFor(t reflect.Type) *schemaNode {
+	if n, ok := schemaCache.Load(t); ok {
+		return n.(*schemaNode)
+	}
+
+	n := &schemaNode{children: map[string]*schemaNode{}}
+	addFields(n, t)
+	schemaCache.Store(t, n)
+	return n
+}
+
+func addFields(n *schemaNode, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+
+		tag := f.Tag.Get("xml")
+		if tag == "-" || f.Name == "XMLName" {
+			continue
+		}
+
+		name, flags := tag, ""
+		if i := strings.Index(tag, ","); i >= 0 {
+			name, flags = tag[:i], tag[i+1:]
+		}
+		if flags != "" && !strings.HasPrefix(flags, "omitempty") {
+			continue // attr, chardata, innerxml, comment, any
+		}
+
+		if f.Anonymous && name == "" && indirectType(f.Type).Kind() == reflect.Struct {
+			addFields(n, indirectType(f.Type))
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		parent := n
+		parts := strings.Split(name, ">")
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := parent.children[part]
+			if !ok {
+				child = &schemaNode{children: map[string]*schemaNode{}}
+				parent.children[part] = child
+			}
+			parent = child
+		}
+		parent.children[parts[len(parts)-1]] = &schemaNode{typ: f.Type}
+	}
+}
+
+// indirectType dereferences pointer and slice types.
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		if t.Kind() != reflect.Ptr && t.Elem().Kind() == reflect.Uint8 {
+			return t // []byte
+		}
+		t = t.Elem()
+	}
+	return t
+}
+
+// unknownWalker walks a document alongside the types it decodes into.
+type unknownWalker struct {
+	found map[string]map[string]struct{}
+}
+
+// walk walks the children of the current element, which decodes into t.
+// owner is the name of the closest named type and path the element path
+// relative to it.
+func (w *unknownWalker) walk(d *xml.Decoder, t reflect.Type, owner, path string) error {
+	t = indirectType(t)
+	if schema, ok := decodeSchemas[t]; ok {
+		owner, path, t = t.Name(), "", schema
+	} else if t.Kind() != reflect.Struct || reflect.PtrTo(t).Implements(unmarshalerType) || hasInnerXML(t) {
+		return d.Skip() // leaf value
+	} else if t.Name() != "" && path != "" {
+		owner, path = t.Name(), ""
+	}
+
+	return w.walkNode(d, schemaFor(t), owner, path)
+}
+
+func (w *unknownWalker) walkNode(d *xml.Decoder, n *schemaNode, owner, path string) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			child, ok := n.children[t.Name.Local]
+			if !ok {
+				if !isLink(t) {
+					w.add(owner, path+t.Name.Local)
+				}
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+
+			childPath := path + t.Name.Local + "/"
+			if child.typ == nil {
+				err = w.walkNode(d, child, owner, childPath)
+			} else {
+				err = w.walk(d, child.typ, owner, childPath)
+			}
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func (w *unknownWalker) add(owner, path string) {
+	if w.found == nil {
+		w.found = map[string]map[string]struct{}{}
+	}
+	if w.found[owner] == nil {
+		w.found[owner] = map[string]struct{}{}
+	}
+	w.found[owner][path] = struct{}{}
+}
+
+func (w *unknownWalker) result() UnknownElements {
+	if len(w.found) == 0 {
+		return nil
+	}
+
+	u := make(UnknownElements, len(w.found))
+	for owner, paths := range w.found {
+		for p := range paths {
+			u[owner] = append(u[owner], p)
+		}
+		sort.Strings(u[owner])
+	}
+	return u
+}
+
+// isLink returns true for elements that only link to another resource,
+// such as <invoices href="..."/>. These are never mapped.
+func isLink(start xml.StartElement) bool {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "href" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasInnerXML returns true if the struct captures its inner xml, meaning
+// every child element is consumed.
+func hasInnerXML(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if strings.Contains(f.Tag.Get("xml"), ",innerxml") {
+			return true
+		}
+		if f.Anonymous && indirectType(f.Type).Kind() == reflect.Struct && hasInnerXML(indirectType(f.Type)) {
+			return true
+		}
+	}
+	return false
+}
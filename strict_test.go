@@ -0,0 +1,158 @@
+package recurly
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_StrictDecoding(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/v2/accounts/1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<account href="https://your-subdomain.recurly.com/v2/accounts/1">
+				<adjustments href="https://your-subdomain.recurly.com/v2/accounts/1/adjustments"/>
+				<account_code>1</account_code>
+				<state>active</state>
+				<cc_emails>bob@example.com,susan@example.com</cc_emails>
+				<address>
+					<address1>123 Main St.</address1>
+					<address3>Floor 2</address3>
+					<city>San Francisco</city>
+				</address>
+				<billing_info>
+					<first_name>Verena</first_name>
+					<card_network>visa</card_network>
+				</billing_info>
+				<shipping_addresses>
+					<shipping_address>
+						<first_name>Verena</first_name>
+						<geo_code>1</geo_code>
+					</shipping_address>
+					<shipping_address>
+						<first_name>Bob</first_name>
+						<geo_code>2</geo_code>
+					</shipping_address>
+				</shipping_addresses>
+			</account>`)
+	})
+
+	var called []*Response
+	client := NewClient("test", "abc", nil, WithBaseURL(server.URL), WithStrictDecoding(func(resp *Response) {
+		called = append(called, resp)
+	}))
+
+	resp, a, err := client.Accounts.Get("1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if a.Code != "1" || a.Address.City != "San Francisco" || len(*a.ShippingAddresses) != 2 {
+		t.Fatalf("unexpected account: %#v", a)
+	} else if len(called) != 1 || called[0] != resp {
+		t.Fatalf("expected callback to be called once with the response, given %v", called)
+	} else if !reflect.DeepEqual(resp.UnknownElements, UnknownElements{
+		"Account":         {"cc_emails"},
+		"Address":         {"address3"},
+		"Billing":         {"card_network"},
+		"ShippingAddress": {"geo_code"},
+	}) {
+		t.Fatalf("unexpected unknown elements: %#v", resp.UnknownElements)
+	}
+
+	// Without strict decoding nothing is recorded.
+	client = NewClient("test", "abc", nil, WithBaseURL(server.URL))
+	if resp, _, err := client.Accounts.Get("1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.UnknownElements != nil {
+		t.Fatalf("unexpected unknown elements: %#v", resp.UnknownElements)
+	}
+}
+
+func TestClient_StrictDecoding_NoUnknownElements(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/v2/invoices", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.Write(invoicesXML(2, 2, 2))
+	})
+
+	var called bool
+	client := NewClient("test", "abc", nil, WithBaseURL(server.URL), WithStrictDecoding(func(resp *Response) {
+		called = true
+	}))
+
+	resp, invoices, err := client.Invoices.List(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(invoices) != 2 || len(invoices[1].LineItems) != 2 || len(invoices[1].Transactions) != 2 {
+		t.Fatalf("unexpected invoices: %#v", invoices)
+	} else if called {
+		t.Fatal("expected callback not to be called")
+	} else if resp.UnknownElements != nil {
+		t.Fatalf("unexpected unknown elements: %#v", resp.UnknownElements)
+	}
+}
+
+func TestFindUnknownElements(t *testing.T) {
+	body := bytes.NewBufferString(`<?xml version="1.0" encoding="UTF-8"?>
+		<subscription href="https://your-subdomain.recurly.com/v2/subscriptions/44f83d7cba354d5b84812419f923ea96">
+			<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+			<plan href="https://your-subdomain.recurly.com/v2/plans/gold">
+				<plan_code>gold</plan_code>
+				<name>Gold plan</name>
+				<plan_version>2</plan_version>
+			</plan>
+			<uuid>44f83d7cba354d5b84812419f923ea96</uuid>
+			<state>active</state>
+			<converted_at nil="nil"></converted_at>
+			<subscription_add_ons type="array">
+				<subscription_add_on>
+					<add_on_code>extra_users</add_on_code>
+					<unit_amount_in_cents type="integer">1000</unit_amount_in_cents>
+					<quantity type="integer">2</quantity>
+					<revenue_schedule_type>evenly</revenue_schedule_type>
+				</subscription_add_on>
+			</subscription_add_ons>
+			<invoice_collection>
+				<charge_invoice>
+					<uuid>421f7b7d414e4c6792938e7c49d552e9</uuid>
+					<dunning_campaign_id>1</dunning_campaign_id>
+					<line_items type="array">
+						<adjustment>
+							<uuid>626db120a84102b1809909071c701c60</uuid>
+							<proration_rate>0.5</proration_rate>
+						</adjustment>
+					</line_items>
+				</charge_invoice>
+				<credit_invoices type="array"></credit_invoices>
+			</invoice_collection>
+		</subscription>`)
+
+	var s Subscription
+	unknown, err := FindUnknownElements(body, &s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(unknown, UnknownElements{
+		"Adjustment":        {"proration_rate"},
+		"Invoice":           {"dunning_campaign_id"},
+		"InvoiceCollection": {"credit_invoices"},
+		"NestedPlan":        {"plan_version"},
+		"Subscription":      {"converted_at"},
+		"SubscriptionAddOn": {"revenue_schedule_type"},
+	}) {
+		t.Fatalf("unexpected unknown elements: %#v", unknown)
+	}
+
+	// Invalid XML returns an error.
+	if _, err := FindUnknownElements(bytes.NewBufferString("<account><account_code>"), &Account{}); err == nil {
+		t.Fatal("expected error. None given.")
+	}
+}
@@ -45,53 +45,49 @@ const (
 
 // Subscription represents an individual subscription.
 type Subscription struct {
-	XMLName                xml.Name             `xml:"subscription"`
-	Plan                   NestedPlan           `xml:"plan,omitempty"`
-	AccountCode            string               `xml:"-"`
-	InvoiceNumber          int                  `xml:"-"`
-	UUID                   string               `xml:"uuid,omitempty"`
-	State                  string               `xml:"state,omitempty"`
-	UnitAmountInCents      int                  `xml:"unit_amount_in_cents,omitempty"`
-	Currency               string               `xml:"currency,omitempty"`
-	Quantity               int                  `xml:"quantity,omitempty"`
-	TotalAmountInCents     int                  `xml:"total_amount_in_cents,omitempty"`
-	ActivatedAt            NullTime             `xml:"activated_at,omitempty"`
-	CanceledAt             NullTime             `xml:"canceled_at,omitempty"`
-	ExpiresAt              NullTime             `xml:"expires_at,omitempty"`
-	CurrentPeriodStartedAt NullTime             `xml:"current_period_started_at,omitempty"`
-	CurrentPeriodEndsAt    NullTime             `xml:"current_period_ends_at,omitempty"`
-	TrialStartedAt         NullTime             `xml:"trial_started_at,omitempty"`
-	TrialEndsAt            NullTime             `xml:"trial_ends_at,omitempty"`
-	CostInCents            int                  `xml:"cost_in_cents,omitempty"`
-	TaxInCents             int                  `xml:"tax_in_cents,omitempty"`
-	TaxType                string               `xml:"tax_type,omitempty"`
-	TaxRegion              string               `xml:"tax_region,omitempty"`
-	TaxRate                float64              `xml:"tax_rate,omitempty"`
-	PONumber               string               `xml:"po_number,omitempty"`
-	NetTerms               NullInt              `xml:"net_terms,omitempty"`
-	SubscriptionAddOns     []SubscriptionAddOn  `xml:"subscription_add_ons>subscription_add_on,omitempty"`
-	CurrentTermStartedAt   NullTime             `xml:"current_term_started_at,omitempty"`
-	CurrentTermEndsAt      NullTime             `xml:"current_term_ends_at,omitempty"`
-	PendingSubscription    *PendingSubscription `xml:"pending_subscription,omitempty"`
-	Invoice                *Invoice             `xml:"-"`
-	RemainingPauseCycles   int                  `xml:"remaining_pause_cycles,omitempty"`
-	CollectionMethod       string               `xml:"collection_method"`
-	AutoRenew              bool                 `xml:"auto_renew,omitempty"`
-	RenewalBillingCycles   NullInt              `xml:"renewal_billing_cycles"`
-	CustomFields           *CustomFields        `xml:"custom_fields,omitempty"`
+	XMLName                xml.Name             `xml:"subscription" json:"-"`
+	Plan                   NestedPlan           `xml:"plan,omitempty" json:"plan"`
+	AccountCode            string               `xml:"-" json:"account_code,omitempty"`
+	InvoiceNumber          int                  `xml:"-" json:"invoice_number,omitempty"`
+	UUID                   string               `xml:"uuid,omitempty" json:"uuid,omitempty"`
+	State                  string               `xml:"state,omitempty" json:"state,omitempty"`
+	UnitAmountInCents      int                  `xml:"unit_amount_in_cents,omitempty" json:"unit_amount_in_cents,omitempty"`
+	Currency               string               `xml:"currency,omitempty" json:"currency,omitempty"`
+	Quantity               int                  `xml:"quantity,omitempty" json:"quantity,omitempty"`
+	TotalAmountInCents     int                  `xml:"total_amount_in_cents,omitempty" json:"total_amount_in_cents,omitempty"`
+	ActivatedAt            NullTime             `xml:"activated_at,omitempty" json:"activated_at"`
+	CanceledAt             NullTime             `xml:"canceled_at,omitempty" json:"canceled_at"`
+	ExpiresAt              NullTime             `xml:"expires_at,omitempty" json:"expires_at"`
+	UpdatedAt              NullTime             `xml:"updated_at,omitempty" json:"updated_at"`
+	CurrentPeriodStartedAt NullTime             `xml:"current_period_started_at,omitempty" json:"current_period_started_at"`
+	CurrentPeriodEndsAt    NullTime             `xml:"current_period_ends_at,omitempty" json:"current_period_ends_at"`
+	TrialStartedAt         NullTime             `xml:"trial_started_at,omitempty" json:"trial_started_at"`
+	TrialEndsAt            NullTime             `xml:"trial_ends_at,omitempty" json:"trial_ends_at"`
+	CostInCents            int                  `xml:"cost_in_cents,omitempty" json:"cost_in_cents,omitempty"`
+	TaxInCents             int                  `xml:"tax_in_cents,omitempty" json:"tax_in_cents,omitempty"`
+	TaxType                string               `xml:"tax_type,omitempty" json:"tax_type,omitempty"`
+	TaxRegion              string               `xml:"tax_region,omitempty" json:"tax_region,omitempty"`
+	TaxRate                float64              `xml:"tax_rate,omitempty" json:"tax_rate,omitempty"`
+	PONumber               string               `xml:"po_number,omitempty" json:"po_number,omitempty"`
+	NetTerms               NullInt              `xml:"net_terms,omitempty" json:"net_terms"`
+	SubscriptionAddOns     []SubscriptionAddOn  `xml:"subscription_add_ons>subscription_add_on,omitempty" json:"subscription_add_ons,omitempty"`
+	CurrentTermStartedAt   NullTime             `xml:"current_term_started_at,omitempty" json:"current_term_started_at"`
+	CurrentTermEndsAt      NullTime             `xml:"current_term_ends_at,omitempty" json:"current_term_ends_at"`
+	PendingSubscription    *PendingSubscription `xml:"pending_subscription,omitempty" json:"pending_subscription,omitempty"`
+	Invoice                *Invoice             `xml:"-" json:"invoice,omitempty"`
+	RemainingPauseCycles   int                  `xml:"remaining_pause_cycles,omitempty" json:"remaining_pause_cycles,omitempty"`
+	PausedAt               NullTime             `xml:"paused_at,omitempty" json:"paused_at"`
+	ResumeAt               NullTime             `xml:"resume_at,omitempty" json:"resume_at"`
+	CollectionMethod       string               `xml:"collection_method" json:"collection_method,omitempty"`
+	AutoRenew              bool                 `xml:"auto_renew,omitempty" json:"auto_renew,omitempty"`
+	RenewalBillingCycles   NullInt              `xml:"renewal_billing_cycles" json:"renewal_billing_cycles"`
+	CustomFields           *CustomFields        `xml:"custom_fields,omitempty" json:"custom_fields,omitempty"`
 }
 
 // UnmarshalXML unmarshals transactions and handles intermediary state during unmarshaling
 // for types like href.
 func (s *Subscription) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	type subscriptionAlias Subscription
-	var v struct {
-		subscriptionAlias
-		XMLName           xml.Name           `xml:"subscription"`
-		AccountCode       hrefString         `xml:"account"`
-		InvoiceNumber     hrefInt            `xml:"invoice"`
-		InvoiceCollection *InvoiceCollection `xml:"invoice_collection"`
-	}
+	var v subscriptionFields
 	if err := d.DecodeElement(&v, &start); err != nil {
 		return err
 	}
@@ -107,6 +103,17 @@ func (s *Subscription) UnmarshalXML(d *xml.Decoder, start xml.StartElement) erro
 	return nil
 }
 
+type subscriptionAlias Subscription
+
+// subscriptionFields is used by the custom unmarshal function.
+type subscriptionFields struct {
+	subscriptionAlias
+	XMLName           xml.Name           `xml:"subscription"`
+	AccountCode       hrefString         `xml:"account"`
+	InvoiceNumber     hrefInt            `xml:"invoice"`
+	InvoiceCollection *InvoiceCollection `xml:"invoice_collection"`
+}
+
 // MakeUpdate creates an UpdateSubscription with values that need to be passed
 // on update to be retained (meaning nil/zero values will delete that value).
 // After calling MakeUpdate you should modify the struct with your updates.
@@ -120,86 +127,107 @@ func (s Subscription) MakeUpdate() UpdateSubscription {
 	}
 }
 
+// UnitAmount returns the subscription's plan unit amount.
+func (s Subscription) UnitAmount() Money {
+	return NewMoney(s.UnitAmountInCents, s.Currency)
+}
+
+// TotalAmount returns the subscription's total amount per billing cycle,
+// including add-ons.
+func (s Subscription) TotalAmount() Money {
+	return NewMoney(s.TotalAmountInCents, s.Currency)
+}
+
+// Cost returns the subscription's cost.
+func (s Subscription) Cost() Money {
+	return NewMoney(s.CostInCents, s.Currency)
+}
+
+// Tax returns the subscription's tax.
+func (s Subscription) Tax() Money {
+	return NewMoney(s.TaxInCents, s.Currency)
+}
+
 type NestedPlan struct {
-	Code string `xml:"plan_code,omitempty"`
-	Name string `xml:"name,omitempty"`
+	Code string `xml:"plan_code,omitempty" json:"plan_code,omitempty"`
+	Name string `xml:"name,omitempty" json:"name,omitempty"`
 }
 
 // SubscriptionAddOn are add ons to subscriptions.
 // https://docs.com/api/subscriptions/subscription-add-ons
 type SubscriptionAddOn struct {
-	XMLName           xml.Name `xml:"subscription_add_on"`
-	Type              string   `xml:"add_on_type,omitempty"`
-	Code              string   `xml:"add_on_code"`
-	UnitAmountInCents int      `xml:"unit_amount_in_cents"`
-	Quantity          int      `xml:"quantity,omitempty"`
+	XMLName           xml.Name `xml:"subscription_add_on" json:"-"`
+	Type              string   `xml:"add_on_type,omitempty" json:"add_on_type,omitempty"`
+	Code              string   `xml:"add_on_code" json:"add_on_code,omitempty"`
+	UnitAmountInCents int      `xml:"unit_amount_in_cents" json:"unit_amount_in_cents,omitempty"`
+	Quantity          int      `xml:"quantity,omitempty" json:"quantity,omitempty"`
 }
 
 // PendingSubscription are updates to the subscription or subscription add ons that
 // will be made on the next renewal.
 type PendingSubscription struct {
-	XMLName            xml.Name            `xml:"pending_subscription"`
-	Plan               NestedPlan          `xml:"plan,omitempty"`
-	UnitAmountInCents  int                 `xml:"unit_amount_in_cents,omitempty"`
-	Quantity           int                 `xml:"quantity,omitempty"` // Quantity of subscriptions
-	SubscriptionAddOns []SubscriptionAddOn `xml:"subscription_add_ons>subscription_add_on,omitempty"`
+	XMLName            xml.Name            `xml:"pending_subscription" json:"-"`
+	Plan               NestedPlan          `xml:"plan,omitempty" json:"plan"`
+	UnitAmountInCents  int                 `xml:"unit_amount_in_cents,omitempty" json:"unit_amount_in_cents,omitempty"`
+	Quantity           int                 `xml:"quantity,omitempty" json:"quantity,omitempty"` // Quantity of subscriptions
+	SubscriptionAddOns []SubscriptionAddOn `xml:"subscription_add_ons>subscription_add_on,omitempty" json:"subscription_add_ons,omitempty"`
 }
 
 // NewSubscription is used to create new subscriptions.
 type NewSubscription struct {
-	XMLName                 xml.Name             `xml:"subscription"`
-	PlanCode                string               `xml:"plan_code"`
-	Account                 Account              `xml:"account"`
-	SubscriptionAddOns      *[]SubscriptionAddOn `xml:"subscription_add_ons>subscription_add_on,omitempty"`
-	CouponCode              string               `xml:"coupon_code,omitempty"`
-	UnitAmountInCents       int                  `xml:"unit_amount_in_cents,omitempty"`
-	Currency                string               `xml:"currency"`
-	Quantity                int                  `xml:"quantity,omitempty"`
-	TrialEndsAt             NullTime             `xml:"trial_ends_at,omitempty"`
-	StartsAt                NullTime             `xml:"starts_at,omitempty"`
-	TotalBillingCycles      int                  `xml:"total_billing_cycles,omitempty"`
-	RenewalBillingCycles    NullInt              `xml:"renewal_billing_cycles"`
-	FirstRenewalDate        NullTime             `xml:"first_renewal_date,omitempty"`
-	CollectionMethod        string               `xml:"collection_method,omitempty"`
-	AutoRenew               bool                 `xml:"auto_renew,omitempty"`
-	NetTerms                NullInt              `xml:"net_terms,omitempty"`
-	PONumber                string               `xml:"po_number,omitempty"`
-	Bulk                    bool                 `xml:"bulk,omitempty"`
-	TermsAndConditions      string               `xml:"terms_and_conditions,omitempty"`
-	CustomerNotes           string               `xml:"customer_notes,omitempty"`
-	VATReverseChargeNotes   string               `xml:"vat_reverse_charge_notes,omitempty"`
-	BankAccountAuthorizedAt NullTime             `xml:"bank_account_authorized_at,omitempty"`
-	CustomFields            *CustomFields        `xml:"custom_fields,omitempty"`
+	XMLName                 xml.Name             `xml:"subscription" json:"-"`
+	PlanCode                string               `xml:"plan_code" json:"plan_code,omitempty"`
+	Account                 Account              `xml:"account" json:"account"`
+	SubscriptionAddOns      *[]SubscriptionAddOn `xml:"subscription_add_ons>subscription_add_on,omitempty" json:"subscription_add_ons,omitempty"`
+	CouponCode              string               `xml:"coupon_code,omitempty" json:"coupon_code,omitempty"`
+	UnitAmountInCents       int                  `xml:"unit_amount_in_cents,omitempty" json:"unit_amount_in_cents,omitempty"`
+	Currency                string               `xml:"currency" json:"currency,omitempty"`
+	Quantity                int                  `xml:"quantity,omitempty" json:"quantity,omitempty"`
+	TrialEndsAt             NullTime             `xml:"trial_ends_at,omitempty" json:"trial_ends_at"`
+	StartsAt                NullTime             `xml:"starts_at,omitempty" json:"starts_at"`
+	TotalBillingCycles      int                  `xml:"total_billing_cycles,omitempty" json:"total_billing_cycles,omitempty"`
+	RenewalBillingCycles    NullInt              `xml:"renewal_billing_cycles" json:"renewal_billing_cycles"`
+	FirstRenewalDate        NullTime             `xml:"first_renewal_date,omitempty" json:"first_renewal_date"`
+	CollectionMethod        string               `xml:"collection_method,omitempty" json:"collection_method,omitempty"`
+	AutoRenew               bool                 `xml:"auto_renew,omitempty" json:"auto_renew,omitempty"`
+	NetTerms                NullInt              `xml:"net_terms,omitempty" json:"net_terms"`
+	PONumber                string               `xml:"po_number,omitempty" json:"po_number,omitempty"`
+	Bulk                    bool                 `xml:"bulk,omitempty" json:"bulk,omitempty"`
+	TermsAndConditions      string               `xml:"terms_and_conditions,omitempty" json:"terms_and_conditions,omitempty"`
+	CustomerNotes           string               `xml:"customer_notes,omitempty" json:"customer_notes,omitempty"`
+	VATReverseChargeNotes   string               `xml:"vat_reverse_charge_notes,omitempty" json:"vat_reverse_charge_notes,omitempty"`
+	BankAccountAuthorizedAt NullTime             `xml:"bank_account_authorized_at,omitempty" json:"bank_account_authorized_at"`
+	CustomFields            *CustomFields        `xml:"custom_fields,omitempty" json:"custom_fields,omitempty"`
 }
 
 // NewSubscriptionResponse is used to unmarshal either the subscription or the transaction.
 type NewSubscriptionResponse struct {
-	Subscription *Subscription
-	Transaction  *Transaction // UnprocessableEntity errors return only the transaction
+	Subscription *Subscription `json:"subscription,omitempty"`
+	Transaction  *Transaction  `json:"transaction,omitempty"` // UnprocessableEntity errors return only the transaction
 }
 
 // UpdateSubscription is used to update subscriptions
 type UpdateSubscription struct {
-	XMLName              xml.Name             `xml:"subscription"`
-	Timeframe            string               `xml:"timeframe,omitempty"`
-	PlanCode             string               `xml:"plan_code,omitempty"`
-	Quantity             int                  `xml:"quantity,omitempty"`
-	UnitAmountInCents    int                  `xml:"unit_amount_in_cents,omitempty"`
-	RenewalBillingCycles NullInt              `xml:"renewal_billing_cycles"`
-	CollectionMethod     string               `xml:"collection_method,omitempty"`
-	AutoRenew            bool                 `xml:"auto_renew,omitempty"`
-	NetTerms             NullInt              `xml:"net_terms,omitempty"`
-	PONumber             string               `xml:"po_number,omitempty"`
-	SubscriptionAddOns   *[]SubscriptionAddOn `xml:"subscription_add_ons>subscription_add_on,omitempty"`
-	CouponCode           string               `xml:"coupon_code,omitempty"`
+	XMLName              xml.Name             `xml:"subscription" json:"-"`
+	Timeframe            string               `xml:"timeframe,omitempty" json:"timeframe,omitempty"`
+	PlanCode             string               `xml:"plan_code,omitempty" json:"plan_code,omitempty"`
+	Quantity             int                  `xml:"quantity,omitempty" json:"quantity,omitempty"`
+	UnitAmountInCents    int                  `xml:"unit_amount_in_cents,omitempty" json:"unit_amount_in_cents,omitempty"`
+	RenewalBillingCycles NullInt              `xml:"renewal_billing_cycles" json:"renewal_billing_cycles"`
+	CollectionMethod     string               `xml:"collection_method,omitempty" json:"collection_method,omitempty"`
+	AutoRenew            bool                 `xml:"auto_renew,omitempty" json:"auto_renew,omitempty"`
+	NetTerms             NullInt              `xml:"net_terms,omitempty" json:"net_terms"`
+	PONumber             string               `xml:"po_number,omitempty" json:"po_number,omitempty"`
+	SubscriptionAddOns   *[]SubscriptionAddOn `xml:"subscription_add_ons>subscription_add_on,omitempty" json:"subscription_add_ons,omitempty"`
+	CouponCode           string               `xml:"coupon_code,omitempty" json:"coupon_code,omitempty"`
 }
 
 // SubscriptionNotes is used to update a subscription's notes.
 type SubscriptionNotes struct {
-	XMLName               xml.Name `xml:"subscription"`
-	TermsAndConditions    string   `xml:"terms_and_conditions,omitempty"`
-	CustomerNotes         string   `xml:"customer_notes,omitempty"`
-	VATReverseChargeNotes string   `xml:"vat_reverse_charge_notes,omitempty"`
+	XMLName               xml.Name `xml:"subscription" json:"-"`
+	TermsAndConditions    string   `xml:"terms_and_conditions,omitempty" json:"terms_and_conditions,omitempty"`
+	CustomerNotes         string   `xml:"customer_notes,omitempty" json:"customer_notes,omitempty"`
+	VATReverseChargeNotes string   `xml:"vat_reverse_charge_notes,omitempty" json:"vat_reverse_charge_notes,omitempty"`
 }
 
 // CustomFields represents custom key value pairs.
@@ -157,7 +157,13 @@ func (s *subscriptionsImpl) PreviewChange(uuid string, sub UpdateSubscription) (
 // Cancel cancels a subscription so it remains active and then expires at the
 // end of the current bill cycle.
 // https://docs.recurly.com/api/subscriptions#cancel-subscription
+// With WithTransitionCheck, the subscription is fetched first to check the
+// change is valid for its state.
 func (s *subscriptionsImpl) Cancel(uuid string) (*Response, *Subscription, error) {
+	if err := s.checkTransition(uuid, SubscriptionActionCancel); err != nil {
+		return nil, nil, err
+	}
+
 	action := fmt.Sprintf("subscriptions/%s/cancel", SanitizeUUID(uuid))
 	req, err := s.client.newRequest("PUT", action, nil, nil)
 	if err != nil {
@@ -173,7 +179,13 @@ func (s *subscriptionsImpl) Cancel(uuid string) (*Response, *Subscription, error
 // Reactivate will reactivate a canceled subscription so it renews at the end
 // of the current bill cycle.
 // https://docs.recurly.com/api/subscriptions#reactivate-subscription
+// With WithTransitionCheck, the subscription is fetched first to check the
+// change is valid for its state.
 func (s *subscriptionsImpl) Reactivate(uuid string) (*Response, *Subscription, error) {
+	if err := s.checkTransition(uuid, SubscriptionActionReactivate); err != nil {
+		return nil, nil, err
+	}
+
 	action := fmt.Sprintf("subscriptions/%s/reactivate", SanitizeUUID(uuid))
 	req, err := s.client.newRequest("PUT", action, nil, nil)
 	if err != nil {
@@ -189,7 +201,13 @@ func (s *subscriptionsImpl) Reactivate(uuid string) (*Response, *Subscription, e
 // TerminateWithPartialRefund will terminate the active subscription
 // immediately with a full refund.
 // https://docs.recurly.com/api/subscriptions#terminate-subscription
+// With WithTransitionCheck, the subscription is fetched first to check the
+// change is valid for its state.
 func (s *subscriptionsImpl) TerminateWithPartialRefund(uuid string) (*Response, *Subscription, error) {
+	if err := s.checkTransition(uuid, SubscriptionActionTerminate); err != nil {
+		return nil, nil, err
+	}
+
 	action := fmt.Sprintf("subscriptions/%s/terminate", SanitizeUUID(uuid))
 	req, err := s.client.newRequest("PUT", action, Params{"refund_type": "partial"}, nil)
 	if err != nil {
@@ -205,7 +223,13 @@ func (s *subscriptionsImpl) TerminateWithPartialRefund(uuid string) (*Response,
 // TerminateWithFullRefund will terminate the active subscription
 // immediately with a full refund.
 // https://docs.recurly.com/api/subscriptions#terminate-subscription
+// With WithTransitionCheck, the subscription is fetched first to check the
+// change is valid for its state.
 func (s *subscriptionsImpl) TerminateWithFullRefund(uuid string) (*Response, *Subscription, error) {
+	if err := s.checkTransition(uuid, SubscriptionActionTerminate); err != nil {
+		return nil, nil, err
+	}
+
 	action := fmt.Sprintf("subscriptions/%s/terminate", SanitizeUUID(uuid))
 	req, err := s.client.newRequest("PUT", action, Params{"refund_type": "full"}, nil)
 	if err != nil {
@@ -221,7 +245,13 @@ func (s *subscriptionsImpl) TerminateWithFullRefund(uuid string) (*Response, *Su
 // TerminateWithoutRefund will terminate the active subscription
 // immediately with no refund.
 // https://docs.recurly.com/api/subscriptions#terminate-subscription
+// With WithTransitionCheck, the subscription is fetched first to check the
+// change is valid for its state.
 func (s *subscriptionsImpl) TerminateWithoutRefund(uuid string) (*Response, *Subscription, error) {
+	if err := s.checkTransition(uuid, SubscriptionActionTerminate); err != nil {
+		return nil, nil, err
+	}
+
 	action := fmt.Sprintf("subscriptions/%s/terminate", SanitizeUUID(uuid))
 	req, err := s.client.newRequest("PUT", action, Params{"refund_type": "none"}, nil)
 	if err != nil {
@@ -238,7 +268,13 @@ func (s *subscriptionsImpl) TerminateWithoutRefund(uuid string) (*Response, *Sub
 // The subscription will not be prorated. For a subscription in a trial period,
 // modifying the renewal date will modify when the trial expires.
 // https://docs.recurly.com/api/subscriptions#postpone-subscription
+// With WithTransitionCheck, the subscription is fetched first to check the
+// change is valid for its state.
 func (s *subscriptionsImpl) Postpone(uuid string, dt time.Time, bulk bool) (*Response, *Subscription, error) {
+	if err := s.checkTransition(uuid, SubscriptionActionPostpone); err != nil {
+		return nil, nil, err
+	}
+
 	action := fmt.Sprintf("subscriptions/%s/postpone", SanitizeUUID(uuid))
 	req, err := s.client.newRequest("PUT", action, Params{
 		"bulk":              bulk,
@@ -256,7 +292,13 @@ func (s *subscriptionsImpl) Postpone(uuid string, dt time.Time, bulk bool) (*Res
 
 // Pause will pause an active subscription for the specified number of billing cycles.
 // The pause takes effect at the beginning of the next billing cycle.
+// With WithTransitionCheck, the subscription is fetched first to check the
+// change is valid for its state.
 func (s *subscriptionsImpl) Pause(uuid string, cycles int) (*Response, *Subscription, error) {
+	if err := s.checkTransition(uuid, SubscriptionActionPause); err != nil {
+		return nil, nil, err
+	}
+
 	action := fmt.Sprintf("subscriptions/%s/pause", SanitizeUUID(uuid))
 	type subscription struct {
 		RemainingPauseCycles int `xml:"remaining_pause_cycles"`
@@ -271,7 +313,13 @@ func (s *subscriptionsImpl) Pause(uuid string, cycles int) (*Response, *Subscrip
 }
 
 // Resume will immediately resume a paused subscription.
+// With WithTransitionCheck, the subscription is fetched first to check the
+// change is valid for its state.
 func (s *subscriptionsImpl) Resume(uuid string) (*Response, *Subscription, error) {
+	if err := s.checkTransition(uuid, SubscriptionActionResume); err != nil {
+		return nil, nil, err
+	}
+
 	action := fmt.Sprintf("subscriptions/%s/resume", SanitizeUUID(uuid))
 	req, err := s.client.newRequest("PUT", action, nil, nil)
 
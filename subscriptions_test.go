@@ -2,6 +2,7 @@ package recurly
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"net/http"
@@ -1209,3 +1210,42 @@ func TestSubscriptions_Resume(t *testing.T) {
 		t.Fatal("expected pause subscription change to return OK")
 	}
 }
+
+func TestSubscription_JSON(t *testing.T) {
+	sub := Subscription{
+		Plan:                 NestedPlan{Code: "gold", Name: "Gold plan"},
+		AccountCode:          "1",
+		UUID:                 "44f83d7cba354d5b84812419f923ea96",
+		State:                SubscriptionStateActive,
+		UnitAmountInCents:    800,
+		Currency:             "EUR",
+		Quantity:             1,
+		ActivatedAt:          NewTimeFromString("2011-05-27T07:00:00Z"),
+		CurrentPeriodEndsAt:  NewTimeFromString("2011-06-27T07:00:00Z"),
+		TaxRate:              0.0875,
+		SubscriptionAddOns:   []SubscriptionAddOn{{Code: "extra_users", UnitAmountInCents: 1000, Quantity: 2}},
+		PendingSubscription:  &PendingSubscription{Plan: NestedPlan{Code: "silver"}, UnitAmountInCents: 600},
+		CollectionMethod:     "automatic",
+		AutoRenew:            true,
+		RenewalBillingCycles: NewInt(0),
+		CustomFields:         &CustomFields{"device_id": "KIWTL-WER-ZXMRD"},
+	}
+
+	b, err := json.Marshal(sub)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !bytes.Contains(b, []byte(`"plan":{"plan_code":"gold","name":"Gold plan"}`)) {
+		t.Fatalf("unexpected json: %s", b)
+	} else if !bytes.Contains(b, []byte(`"custom_fields":{"device_id":"KIWTL-WER-ZXMRD"}`)) {
+		t.Fatalf("unexpected json: %s", b)
+	} else if !bytes.Contains(b, []byte(`"renewal_billing_cycles":0`)) || !bytes.Contains(b, []byte(`"canceled_at":null`)) {
+		t.Fatalf("unexpected json: %s", b)
+	}
+
+	var given Subscription
+	if err := json.Unmarshal(b, &given); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if diff := cmp.Diff(sub, given); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -18,42 +18,42 @@ const (
 
 // Transaction represents an individual transaction.
 type Transaction struct {
-	InvoiceNumber    int    // Read only
-	SubscriptionUUID string // Read only
-	UUID             string // Read only
-	Action           string
-	AmountInCents    int
-	TaxInCents       int
-	Currency         string
-	Description      string
-	Status           string
-	PaymentMethod    string
-	Reference        string
-	Source           string
-	Recurring        NullBool
-	Test             bool
-	Voidable         NullBool
-	Refundable       NullBool
-	IPAddress        net.IP
-	TransactionError *TransactionError // Read only
-	CVVResult        CVVResult         // Read only
-	AVSResult        AVSResult         // Read only
-	AVSResultStreet  string            // Read only
-	AVSResultPostal  string            // Read only
-	CreatedAt        NullTime          // Read only
-	Account          Account
+	InvoiceNumber    int               `json:"invoice_number,omitempty"`    // Read only
+	SubscriptionUUID string            `json:"subscription_uuid,omitempty"` // Read only
+	UUID             string            `json:"uuid,omitempty"`              // Read only
+	Action           string            `json:"action,omitempty"`
+	AmountInCents    int               `json:"amount_in_cents,omitempty"`
+	TaxInCents       int               `json:"tax_in_cents,omitempty"`
+	Currency         string            `json:"currency,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	Status           string            `json:"status,omitempty"`
+	PaymentMethod    string            `json:"payment_method,omitempty"`
+	Reference        string            `json:"reference,omitempty"`
+	Source           string            `json:"source,omitempty"`
+	Recurring        NullBool          `json:"recurring"`
+	Test             bool              `json:"test,omitempty"`
+	Voidable         NullBool          `json:"voidable"`
+	Refundable       NullBool          `json:"refundable"`
+	IPAddress        net.IP            `json:"ip_address,omitempty"`
+	TransactionError *TransactionError `json:"transaction_error,omitempty"` // Read only
+	CVVResult        CVVResult         `json:"cvv_result"`                  // Read only
+	AVSResult        AVSResult         `json:"avs_result"`                  // Read only
+	AVSResultStreet  string            `json:"avs_result_street,omitempty"` // Read only
+	AVSResultPostal  string            `json:"avs_result_postal,omitempty"` // Read only
+	CreatedAt        NullTime          `json:"created_at"`                  // Read only
+	Account          Account           `json:"account"`
 }
 
 // TransactionError is an error encounted from your payment gateway that
 // recurly has standardized.
 // https://recurly.readme.io/v2.0/page/transaction-errors
 type TransactionError struct {
-	XMLName          xml.Name `xml:"transaction_error"`
-	ErrorCode        string   `xml:"error_code,omitempty"`
-	ErrorCategory    string   `xml:"error_category,omitempty"`
-	MerchantMessage  string   `xml:"merchant_message,omitempty"`
-	CustomerMessage  string   `xml:"customer_message,omitempty"`
-	GatewayErrorCode string   `xml:"gateway_error_code,omitempty"`
+	XMLName          xml.Name `xml:"transaction_error" json:"-"`
+	ErrorCode        string   `xml:"error_code,omitempty" json:"error_code,omitempty"`
+	ErrorCategory    string   `xml:"error_category,omitempty" json:"error_category,omitempty"`
+	MerchantMessage  string   `xml:"merchant_message,omitempty" json:"merchant_message,omitempty"`
+	CustomerMessage  string   `xml:"customer_message,omitempty" json:"customer_message,omitempty"`
+	GatewayErrorCode string   `xml:"gateway_error_code,omitempty" json:"gateway_error_code,omitempty"`
 }
 
 // MarshalXML marshals a transaction sending only the fields recurly allows for writes.
@@ -101,33 +101,7 @@ func (t Transaction) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 // UnmarshalXML unmarshals transactions and handles intermediary state during unmarshaling
 // for types like href.
 func (t *Transaction) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	var v struct {
-		XMLName          xml.Name          `xml:"transaction"`
-		InvoiceNumber    hrefInt           `xml:"invoice"`      // use hrefInt for parsing
-		SubscriptionUUID hrefString        `xml:"subscription"` // use hrefString for parsing
-		UUID             string            `xml:"uuid,omitempty"`
-		Action           string            `xml:"action,omitempty"`
-		AmountInCents    int               `xml:"amount_in_cents"`
-		TaxInCents       int               `xml:"tax_in_cents,omitempty"`
-		Currency         string            `xml:"currency"`
-		Description      string            `xml:"description,omitempty"`
-		Status           string            `xml:"status,omitempty"`
-		PaymentMethod    string            `xml:"payment_method,omitempty"`
-		Reference        string            `xml:"reference,omitempty"`
-		Source           string            `xml:"source,omitempty"`
-		Recurring        NullBool          `xml:"recurring,omitempty"`
-		Test             bool              `xml:"test,omitempty"`
-		Voidable         NullBool          `xml:"voidable,omitempty"`
-		Refundable       NullBool          `xml:"refundable,omitempty"`
-		IPAddress        net.IP            `xml:"ip_address,omitempty"`
-		TransactionError *TransactionError `xml:"transaction_error,omitempty"`
-		CVVResult        CVVResult         `xml:"cvv_result"`
-		AVSResult        AVSResult         `xml:"avs_result"`
-		AVSResultStreet  string            `xml:"avs_result_street,omitempty"`
-		AVSResultPostal  string            `xml:"avs_result_postal,omitempty"`
-		CreatedAt        NullTime          `xml:"created_at,omitempty"`
-		Account          Account           `xml:"details>account"`
-	}
+	var v transactionFields
 	if err := d.DecodeElement(&v, &start); err != nil {
 		return err
 	}
@@ -164,10 +138,49 @@ func (t *Transaction) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 	return nil
 }
 
+// Amount returns the transaction amount.
+func (t Transaction) Amount() Money {
+	return NewMoney(t.AmountInCents, t.Currency)
+}
+
+// Tax returns the tax included in the transaction amount.
+func (t Transaction) Tax() Money {
+	return NewMoney(t.TaxInCents, t.Currency)
+}
+
+// transactionFields is used by the custom unmarshal function.
+type transactionFields struct {
+	XMLName          xml.Name          `xml:"transaction"`
+	InvoiceNumber    hrefInt           `xml:"invoice"`      // use hrefInt for parsing
+	SubscriptionUUID hrefString        `xml:"subscription"` // use hrefString for parsing
+	UUID             string            `xml:"uuid,omitempty"`
+	Action           string            `xml:"action,omitempty"`
+	AmountInCents    int               `xml:"amount_in_cents"`
+	TaxInCents       int               `xml:"tax_in_cents,omitempty"`
+	Currency         string            `xml:"currency"`
+	Description      string            `xml:"description,omitempty"`
+	Status           string            `xml:"status,omitempty"`
+	PaymentMethod    string            `xml:"payment_method,omitempty"`
+	Reference        string            `xml:"reference,omitempty"`
+	Source           string            `xml:"source,omitempty"`
+	Recurring        NullBool          `xml:"recurring,omitempty"`
+	Test             bool              `xml:"test,omitempty"`
+	Voidable         NullBool          `xml:"voidable,omitempty"`
+	Refundable       NullBool          `xml:"refundable,omitempty"`
+	IPAddress        net.IP            `xml:"ip_address,omitempty"`
+	TransactionError *TransactionError `xml:"transaction_error,omitempty"`
+	CVVResult        CVVResult         `xml:"cvv_result"`
+	AVSResult        AVSResult         `xml:"avs_result"`
+	AVSResultStreet  string            `xml:"avs_result_street,omitempty"`
+	AVSResultPostal  string            `xml:"avs_result_postal,omitempty"`
+	CreatedAt        NullTime          `xml:"created_at,omitempty"`
+	Account          Account           `xml:"details>account"`
+}
+
 type TransactionResult struct {
 	NullMarshal
-	Code    string `xml:"code,attr"`
-	Message string `xml:",innerxml"`
+	Code    string `xml:"code,attr" json:"code,omitempty"`
+	Message string `xml:",innerxml" json:"message,omitempty"`
 }
 
 // CVVResult holds transaction results for CVV fields.
@@ -0,0 +1,98 @@
+package recurly
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SubscriptionAction is an operation that changes a subscription's state.
+type SubscriptionAction string
+
+// Subscription actions.
+const (
+	SubscriptionActionCancel     SubscriptionAction = "cancel"
+	SubscriptionActionReactivate SubscriptionAction = "reactivate"
+	SubscriptionActionTerminate  SubscriptionAction = "terminate"
+	SubscriptionActionPostpone   SubscriptionAction = "postpone"
+	SubscriptionActionPause      SubscriptionAction = "pause"
+	SubscriptionActionResume     SubscriptionAction = "resume"
+)
+
+// subscriptionTransitions maps each action to the states it is valid from.
+var subscriptionTransitions = map[SubscriptionAction][]string{
+	SubscriptionActionCancel:     {SubscriptionStateActive, SubscriptionStateFuture},
+	SubscriptionActionReactivate: {SubscriptionStateCanceled},
+	SubscriptionActionTerminate:  {SubscriptionStateActive, SubscriptionStateCanceled, SubscriptionStateFuture, SubscriptionStatePaused},
+	SubscriptionActionPostpone:   {SubscriptionStateActive},
+	SubscriptionActionPause:      {SubscriptionStateActive},
+	SubscriptionActionResume:     {SubscriptionStatePaused},
+}
+
+// ValidStates returns the subscription states the action is valid from.
+func (a SubscriptionAction) ValidStates() []string {
+	return append([]string(nil), subscriptionTransitions[a]...)
+}
+
+// Allows returns true if the action is valid for a subscription in state.
+func (a SubscriptionAction) Allows(state string) bool {
+	for _, s := range subscriptionTransitions[a] {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrInvalidTransition is returned when an action is not valid for the
+// subscription's current state. It implements the error interface.
+type ErrInvalidTransition struct {
+	UUID   string
+	State  string
+	Action SubscriptionAction
+}
+
+// Error implements the error interface.
+func (e ErrInvalidTransition) Error() string {
+	return fmt.Sprintf("recurly: cannot %s subscription %s in state %q, must be %s",
+		e.Action, e.UUID, e.State, strings.Join(e.Action.ValidStates(), " or "))
+}
+
+// CheckTransition returns an ErrInvalidTransition if the action is not
+// valid for the subscription's state.
+func (s Subscription) CheckTransition(a SubscriptionAction) error {
+	if !a.Allows(s.State) {
+		return ErrInvalidTransition{UUID: s.UUID, State: s.State, Action: a}
+	}
+	return nil
+}
+
+// WithTransitionCheck makes the subscription methods that change state
+// (Cancel, Reactivate, Terminate*, Postpone, Pause and Resume) fetch the
+// subscription first and return an ErrInvalidTransition, without sending
+// the change, if the action is not valid for its current state. This costs
+// an additional request per call, which doubles the requests a Bulk change
+// makes. If the subscription cannot be fetched, for example because of a
+// network error, the error is returned and the change is not sent. If the
+// API responds with an error status instead, such as 404 Not Found, the
+// change is sent and the API reports the error.
+func WithTransitionCheck() Option {
+	return func(c *Client) {
+		c.checkTransitions = true
+	}
+}
+
+// checkTransition fetches the subscription and checks the action is valid
+// for its state if transition checks are enabled.
+func (s *subscriptionsImpl) checkTransition(uuid string, a SubscriptionAction) error {
+	if !s.client.checkTransitions {
+		return nil
+	}
+
+	resp, sub, err := s.Get(uuid)
+	if err != nil {
+		return err
+	} else if sub == nil || resp.IsError() {
+		return nil
+	}
+	return sub.CheckTransition(a)
+}
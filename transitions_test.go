@@ -0,0 +1,135 @@
+package recurly
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestSubscription_CheckTransition(t *testing.T) {
+	tests := []struct {
+		action SubscriptionAction
+		valid  []string
+	}{
+		{action: SubscriptionActionCancel, valid: []string{SubscriptionStateActive, SubscriptionStateFuture}},
+		{action: SubscriptionActionReactivate, valid: []string{SubscriptionStateCanceled}},
+		{action: SubscriptionActionTerminate, valid: []string{SubscriptionStateActive, SubscriptionStateCanceled, SubscriptionStateFuture, SubscriptionStatePaused}},
+		{action: SubscriptionActionPostpone, valid: []string{SubscriptionStateActive}},
+		{action: SubscriptionActionPause, valid: []string{SubscriptionStateActive}},
+		{action: SubscriptionActionResume, valid: []string{SubscriptionStatePaused}},
+	}
+
+	states := []string{
+		SubscriptionStateActive,
+		SubscriptionStateCanceled,
+		SubscriptionStateExpired,
+		SubscriptionStateFuture,
+		SubscriptionStatePaused,
+	}
+
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.action.ValidStates(), tt.valid) {
+			t.Fatalf("%s: unexpected valid states: %v", tt.action, tt.action.ValidStates())
+		}
+
+		for _, state := range states {
+			valid := false
+			for _, s := range tt.valid {
+				valid = valid || s == state
+			}
+
+			sub := Subscription{UUID: "44f83d7cba354d5b84812419f923ea96", State: state}
+			err := sub.CheckTransition(tt.action)
+			if valid && err != nil {
+				t.Fatalf("%s from %s: unexpected error: %v", tt.action, state, err)
+			} else if !valid && !reflect.DeepEqual(err, ErrInvalidTransition{UUID: sub.UUID, State: state, Action: tt.action}) {
+				t.Fatalf("%s from %s: unexpected error: %v", tt.action, state, err)
+			}
+		}
+	}
+
+	err := Subscription{UUID: "44f83d7cba354d5b84812419f923ea96", State: SubscriptionStateExpired}.CheckTransition(SubscriptionActionCancel)
+	if err.Error() != `recurly: cannot cancel subscription 44f83d7cba354d5b84812419f923ea96 in state "expired", must be active or future` {
+		t.Fatalf("unexpected error message: %s", err)
+	}
+}
+
+func TestSubscriptions_TransitionCheck(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	state := SubscriptionStateExpired
+	var gets, puts int
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		gets++
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>44f83d7cba354d5b84812419f923ea96</uuid><state>%s</state></subscription>`, state)
+	})
+	mux.HandleFunc("/v2/subscriptions/44f83d7cba354d5b84812419f923ea96/reactivate", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		puts++
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><state>active</state></subscription>`)
+	})
+	mux.HandleFunc("/v2/subscriptions/missing/reactivate", func(w http.ResponseWriter, r *http.Request) {
+		puts++
+		w.WriteHeader(404)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><error><symbol>not_found</symbol></error>`)
+	})
+
+	client := NewClient("test", "abc", nil, WithBaseURL(server.URL), WithTransitionCheck())
+
+	// Invalid transitions are not sent.
+	resp, sub, err := client.Subscriptions.Reactivate("44f83d7cba354d5b84812419f923ea96")
+	if _, ok := err.(ErrInvalidTransition); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp != nil || sub != nil {
+		t.Fatalf("unexpected response: %#v %#v", resp, sub)
+	} else if gets != 1 || puts != 0 {
+		t.Fatalf("unexpected requests: %d gets, %d puts", gets, puts)
+	}
+
+	// Valid transitions are sent after the check.
+	state = SubscriptionStateCanceled
+	if _, sub, err := client.Subscriptions.Reactivate("44f83d7cba354d5b84812419f923ea96"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if sub.State != SubscriptionStateActive {
+		t.Fatalf("unexpected subscription: %#v", sub)
+	} else if gets != 2 || puts != 1 {
+		t.Fatalf("unexpected requests: %d gets, %d puts", gets, puts)
+	}
+
+	// Subscriptions that cannot be fetched are left to the API.
+	if resp, _, err := client.Subscriptions.Reactivate("missing"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.StatusCode != 404 || puts != 2 {
+		t.Fatalf("unexpected response: %d, %d puts", resp.StatusCode, puts)
+	}
+
+	// Errors fetching the subscription are returned without sending the
+	// change.
+	closed := httptest.NewServer(mux)
+	closed.Close()
+	if _, _, err := NewClient("test", "abc", nil, WithBaseURL(closed.URL), WithTransitionCheck()).Subscriptions.Reactivate("44f83d7cba354d5b84812419f923ea96"); err == nil {
+		t.Fatal("expected error. None given.")
+	} else if puts != 2 {
+		t.Fatalf("unexpected requests: %d puts", puts)
+	}
+
+	// Without the option no check is made.
+	state = SubscriptionStateExpired
+	client = NewClient("test", "abc", nil, WithBaseURL(server.URL))
+	if _, _, err := client.Subscriptions.Reactivate("44f83d7cba354d5b84812419f923ea96"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if gets != 2 || puts != 3 {
+		t.Fatalf("unexpected requests: %d gets, %d puts", gets, puts)
+	}
+}
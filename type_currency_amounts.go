@@ -0,0 +1,81 @@
+package recurly
+
+import (
+	"encoding/xml"
+	"sort"
+	"strings"
+)
+
+// CurrencyAmounts holds an amount, in minor units, for each currency, as
+// in the balance_in_cents element of an account balance. Currencies are
+// upper case.
+type CurrencyAmounts map[string]int
+
+// UnmarshalXML unmarshals each child element as the amount in the currency
+// named by the element.
+func (c *CurrencyAmounts) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	amounts := CurrencyAmounts{}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			var amount NullInt
+			if err := d.DecodeElement(&amount, &t); err != nil {
+				return err
+			}
+			amounts[strings.ToUpper(t.Name.Local)] = amount.Int
+		case xml.EndElement:
+			*c = amounts
+			return nil
+		}
+	}
+}
+
+// MarshalXML marshals the amounts as a child element for each currency,
+// ordered by currency. Nothing is marshaled if there are no amounts.
+func (c CurrencyAmounts) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if len(c) == 0 {
+		return nil
+	}
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, currency := range c.Currencies() {
+		if err := e.EncodeElement(c[currency], xml.StartElement{Name: xml.Name{Local: currency}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// Currencies returns the currencies with an amount, in alphabetical order.
+func (c CurrencyAmounts) Currencies() []string {
+	currencies := make([]string, 0, len(c))
+	for currency := range c {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// Money returns the amount in currency, which is zero if there is none.
+// Currencies are matched case-insensitively.
+func (c CurrencyAmounts) Money(currency string) Money {
+	m := NewMoney(0, currency)
+	if amount, ok := c[m.Currency]; ok {
+		m.Amount = amount
+		return m
+	}
+	for k, amount := range c {
+		if strings.EqualFold(k, currency) {
+			m.Amount = amount
+			break
+		}
+	}
+	return m
+}
@@ -0,0 +1,76 @@
+package recurly
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestCurrencyAmounts(t *testing.T) {
+	type s struct {
+		XMLName xml.Name        `xml:"s"`
+		Amounts CurrencyAmounts `xml:"amounts"`
+	}
+
+	tests := []struct {
+		v        s
+		expected string
+	}{
+		{v: s{Amounts: CurrencyAmounts{"USD": 1000}}, expected: "<s><amounts><USD>1000</USD></amounts></s>"},
+		{v: s{Amounts: CurrencyAmounts{"EUR": 650, "USD": -800}}, expected: "<s><amounts><EUR>650</EUR><USD>-800</USD></amounts></s>"},
+		{v: s{Amounts: CurrencyAmounts{"JPY": 0}}, expected: "<s><amounts><JPY>0</JPY></amounts></s>"},
+		{v: s{}, expected: "<s></s>"},
+	}
+
+	for _, tt := range tests {
+		var given bytes.Buffer
+		if err := xml.NewEncoder(&given).Encode(tt.v); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		} else if given.String() != tt.expected {
+			t.Fatalf("unexpected xml: %s", given.String())
+		}
+
+		buf := bytes.NewBufferString(tt.expected)
+		var dst s
+		if err := xml.NewDecoder(buf).Decode(&dst); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		dst.XMLName = xml.Name{}
+		if !reflect.DeepEqual(tt.v, dst) {
+			t.Fatalf("unexpected value: %#v", dst)
+		}
+	}
+}
+
+func TestCurrencyAmounts_Unmarshal(t *testing.T) {
+	var dst struct {
+		Amounts CurrencyAmounts `xml:"balance_in_cents"`
+	}
+	if err := xml.Unmarshal([]byte(`<account_balance><balance_in_cents>
+		<usd type="integer">3000</usd>
+		<GBP nil="nil"></GBP>
+	</balance_in_cents></account_balance>`), &dst); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(dst.Amounts, CurrencyAmounts{"USD": 3000, "GBP": 0}) {
+		t.Fatalf("unexpected amounts: %#v", dst.Amounts)
+	}
+}
+
+func TestCurrencyAmounts_Money(t *testing.T) {
+	c := CurrencyAmounts{"USD": 3000, "EUR": -500}
+	if m := c.Money("usd"); m != NewMoney(3000, "USD") {
+		t.Fatalf("unexpected money: %v", m)
+	} else if m := c.Money("EUR"); m != NewMoney(-500, "EUR") {
+		t.Fatalf("unexpected money: %v", m)
+	} else if m := c.Money("GBP"); m != NewMoney(0, "GBP") {
+		t.Fatalf("unexpected money: %v", m)
+	} else if currencies := c.Currencies(); !reflect.DeepEqual(currencies, []string{"EUR", "USD"}) {
+		t.Fatalf("unexpected currencies: %v", currencies)
+	}
+
+	// Amounts built with lower case currencies are found too.
+	if m := (CurrencyAmounts{"usd": 1050}).Money("USD"); m != NewMoney(1050, "USD") {
+		t.Fatalf("unexpected money: %v", m)
+	}
+}
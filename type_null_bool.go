@@ -1,6 +1,7 @@
 package recurly
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"strconv"
 )
@@ -47,3 +48,26 @@ func (n NullBool) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 
 	return nil
 }
+
+// UnmarshalJSON unmarshals a bool, as well as unmarshaling null to an
+// invalid NullBool.
+func (n *NullBool) UnmarshalJSON(b []byte) error {
+	var v *bool
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	} else if v == nil {
+		*n = NullBool{}
+		return nil
+	}
+	*n = NewBool(*v)
+	return nil
+}
+
+// MarshalJSON marshals valid NullBools as a bool. Otherwise null is
+// marshaled.
+func (n NullBool) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Bool)
+}
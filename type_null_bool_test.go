@@ -2,6 +2,7 @@ package recurly
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"reflect"
 	"testing"
@@ -62,3 +63,32 @@ func TestNullBool(t *testing.T) {
 		}
 	}
 }
+
+func TestNullBool_JSON(t *testing.T) {
+	type s struct {
+		Flag NullBool `json:"flag"`
+	}
+
+	tests := []struct {
+		v        s
+		expected string
+	}{
+		{v: s{Flag: NewBool(false)}, expected: `{"flag":false}`},
+		{v: s{}, expected: `{"flag":null}`},
+	}
+
+	for i, tt := range tests {
+		if b, err := json.Marshal(tt.v); err != nil {
+			t.Fatalf("(%d): unexpected error: %v", i, err)
+		} else if string(b) != tt.expected {
+			t.Fatalf("(%d): unexpected json: %s", i, b)
+		}
+
+		var dest s
+		if err := json.Unmarshal([]byte(tt.expected), &dest); err != nil {
+			t.Fatalf("(%d): unexpected error: %v", i, err)
+		} else if !reflect.DeepEqual(tt.v, dest) {
+			t.Fatalf("(%d): unexpected value: %#v", i, dest)
+		}
+	}
+}
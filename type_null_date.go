@@ -1,6 +1,7 @@
 package recurly
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"time"
 )
@@ -63,3 +64,31 @@ func (t NullDate) String() string {
 
 	return ""
 }
+
+// UnmarshalJSON unmarshals a date in the DateDateFormat format, as well as
+// unmarshaling null to nil.
+func (t *NullDate) UnmarshalJSON(b []byte) error {
+	var v *string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	} else if v == nil || *v == "" {
+		*t = NullDate{}
+		return nil
+	}
+
+	parsed, err := time.Parse(DateDateFormat, *v)
+	if err != nil {
+		return err
+	}
+	*t = NewDate(parsed)
+	return nil
+}
+
+// MarshalJSON marshals dates using the DateDateFormat format. Nil dates are
+// marshaled as null.
+func (t NullDate) MarshalJSON() ([]byte, error) {
+	if t.Time == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.String())
+}
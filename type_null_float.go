@@ -1,6 +1,9 @@
 package recurly
 
-import "encoding/xml"
+import (
+	"encoding/json"
+	"encoding/xml"
+)
 
 // NullFloat is used for properly handling float types that could be null.
 type NullFloat struct {
@@ -33,3 +36,26 @@ func (n NullFloat) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 
 	return nil
 }
+
+// UnmarshalJSON unmarshals a float, as well as unmarshaling null to an
+// invalid NullFloat.
+func (n *NullFloat) UnmarshalJSON(b []byte) error {
+	var v *float64
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	} else if v == nil {
+		*n = NullFloat{}
+		return nil
+	}
+	*n = NewFloat(*v)
+	return nil
+}
+
+// MarshalJSON marshals valid NullFloats as a number. Otherwise null is
+// marshaled.
+func (n NullFloat) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Float)
+}
@@ -2,6 +2,7 @@ package recurly
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"reflect"
 	"testing"
@@ -45,3 +46,32 @@ func TestNullFloat(t *testing.T) {
 		}
 	}
 }
+
+func TestNullFloat_JSON(t *testing.T) {
+	type s struct {
+		Rate NullFloat `json:"rate"`
+	}
+
+	tests := []struct {
+		v        s
+		expected string
+	}{
+		{v: s{Rate: NewFloat(8.25)}, expected: `{"rate":8.25}`},
+		{v: s{}, expected: `{"rate":null}`},
+	}
+
+	for i, tt := range tests {
+		if b, err := json.Marshal(tt.v); err != nil {
+			t.Fatalf("(%d): unexpected error: %v", i, err)
+		} else if string(b) != tt.expected {
+			t.Fatalf("(%d): unexpected json: %s", i, b)
+		}
+
+		var dest s
+		if err := json.Unmarshal([]byte(tt.expected), &dest); err != nil {
+			t.Fatalf("(%d): unexpected error: %v", i, err)
+		} else if !reflect.DeepEqual(tt.v, dest) {
+			t.Fatalf("(%d): unexpected value: %#v", i, dest)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package recurly
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"strings"
 )
@@ -39,3 +40,26 @@ func (n NullInt) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	}
 	return nil
 }
+
+// UnmarshalJSON unmarshals an int, as well as unmarshaling null to an
+// invalid NullInt.
+func (n *NullInt) UnmarshalJSON(b []byte) error {
+	var v *int
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	} else if v == nil {
+		*n = NullInt{}
+		return nil
+	}
+	*n = NewInt(*v)
+	return nil
+}
+
+// MarshalJSON marshals valid NullInts as an int. Otherwise null is
+// marshaled.
+func (n NullInt) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Int)
+}
@@ -1,6 +1,7 @@
 package recurly
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"time"
 )
@@ -63,3 +64,31 @@ func (t NullTime) String() string {
 
 	return ""
 }
+
+// UnmarshalJSON unmarshals a time in the DateTimeFormat format, as well as
+// unmarshaling null to nil.
+func (t *NullTime) UnmarshalJSON(b []byte) error {
+	var v *string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	} else if v == nil || *v == "" {
+		*t = NullTime{}
+		return nil
+	}
+
+	parsed, err := time.Parse(DateTimeFormat, *v)
+	if err != nil {
+		return err
+	}
+	*t = NewTime(parsed)
+	return nil
+}
+
+// MarshalJSON marshals times in UTC using the DateTimeFormat format. Nil
+// times are marshaled as null.
+func (t NullTime) MarshalJSON() ([]byte, error) {
+	if t.Time == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.String())
+}
@@ -2,6 +2,7 @@ package recurly
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"reflect"
@@ -76,3 +77,42 @@ func TestNulTime(t *testing.T) {
 		t.Fatalf("expected time.Parse error to result in empty String(), given %s", dest.Stamp.String())
 	}
 }
+
+func TestNullTime_JSON(t *testing.T) {
+	type s struct {
+		Stamp NullTime `json:"stamp"`
+		Day   NullDate `json:"day"`
+	}
+
+	tests := []struct {
+		v        s
+		expected string
+	}{
+		{v: s{Stamp: NewTimeFromString("2011-10-25T19:00:00Z"), Day: NewDateFromString("2011-10-25")}, expected: `{"stamp":"2011-10-25T19:00:00Z","day":"2011-10-25"}`},
+		{v: s{}, expected: `{"stamp":null,"day":null}`},
+	}
+
+	for i, tt := range tests {
+		if b, err := json.Marshal(tt.v); err != nil {
+			t.Fatalf("(%d): unexpected error: %v", i, err)
+		} else if string(b) != tt.expected {
+			t.Fatalf("(%d): unexpected json: %s", i, b)
+		}
+
+		var dest s
+		if err := json.Unmarshal([]byte(tt.expected), &dest); err != nil {
+			t.Fatalf("(%d): unexpected error: %v", i, err)
+		} else if !reflect.DeepEqual(tt.v, dest) {
+			t.Fatalf("(%d): unexpected value: %#v", i, dest)
+		}
+	}
+
+	var dest s
+	if err := json.Unmarshal([]byte(`{"stamp":"ABC"}`), &dest); err == nil {
+		t.Fatal("expected time.Parse error")
+	} else if err := json.Unmarshal([]byte(`{"stamp":"2011-10-25T12:00:00-07:00"}`), &dest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if dest.Stamp.String() != "2011-10-25T19:00:00Z" {
+		t.Fatalf("unexpected time: %s", dest.Stamp)
+	}
+}
@@ -5,8 +5,8 @@ import "encoding/xml"
 // UnitAmount is used in plans where unit amounts are represented in cents
 // in both EUR and USD.
 type UnitAmount struct {
-	USD int `xml:"USD,omitempty"`
-	EUR int `xml:"EUR,omitempty"`
+	USD int `xml:"USD,omitempty" json:"USD,omitempty"`
+	EUR int `xml:"EUR,omitempty" json:"EUR,omitempty"`
 }
 
 type uaAlias struct {
@@ -1,5 +1,7 @@
 package webhooks
 
+import "encoding/xml"
+
 const (
 	// Account notifications.
 	AccountNotificationNewXMLName                     = "new_account_notification"
@@ -65,3 +67,102 @@ type (
 		ShippingAddress ShippingAddress `xml:"shipping_address"`
 	}
 )
+
+// NewAccountNotificationNew returns a new AccountNotificationNew.
+func NewAccountNotificationNew(account Account) *AccountNotificationNew {
+	return &AccountNotificationNew{
+		Account: account,
+	}
+}
+
+// MarshalXML marshals the notification as <new_account_notification>.
+func (n AccountNotificationNew) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, AccountNotificationNewXMLName, n)
+}
+
+// NewAccountNotificationUpdated returns a new AccountNotificationUpdated.
+func NewAccountNotificationUpdated(account Account) *AccountNotificationUpdated {
+	return &AccountNotificationUpdated{
+		Account: account,
+	}
+}
+
+// MarshalXML marshals the notification as <updated_account_notification>.
+func (n AccountNotificationUpdated) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, AccountNotificationUpdatedXMLName, n)
+}
+
+// NewAccountNotificationCanceled returns a new AccountNotificationCanceled.
+func NewAccountNotificationCanceled(account Account) *AccountNotificationCanceled {
+	return &AccountNotificationCanceled{
+		Account: account,
+	}
+}
+
+// MarshalXML marshals the notification as <canceled_account_notification>.
+func (n AccountNotificationCanceled) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, AccountNotificationCanceledXMLName, n)
+}
+
+// NewAccountNotificationBillingInfoUpdated returns a new AccountNotificationBillingInfoUpdated.
+func NewAccountNotificationBillingInfoUpdated(account Account) *AccountNotificationBillingInfoUpdated {
+	return &AccountNotificationBillingInfoUpdated{
+		Account: account,
+	}
+}
+
+// MarshalXML marshals the notification as <billing_info_updated_notification>.
+func (n AccountNotificationBillingInfoUpdated) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, AccountNotificationBillingInfoUpdatedXMLName, n)
+}
+
+// NewAccountNotificationBillingInfoUpdateFailed returns a new AccountNotificationBillingInfoUpdateFailed.
+func NewAccountNotificationBillingInfoUpdateFailed(account Account) *AccountNotificationBillingInfoUpdateFailed {
+	return &AccountNotificationBillingInfoUpdateFailed{
+		Account: account,
+	}
+}
+
+// MarshalXML marshals the notification as <billing_info_update_failed_notification>.
+func (n AccountNotificationBillingInfoUpdateFailed) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, AccountNotificationBillingInfoUpdateFailedXMLName, n)
+}
+
+// NewAccountNotificationNewShippingAddress returns a new AccountNotificationNewShippingAddress.
+func NewAccountNotificationNewShippingAddress(account Account, shippingAddress ShippingAddress) *AccountNotificationNewShippingAddress {
+	return &AccountNotificationNewShippingAddress{
+		Account:         account,
+		ShippingAddress: shippingAddress,
+	}
+}
+
+// MarshalXML marshals the notification as <new_shipping_address_notification>.
+func (n AccountNotificationNewShippingAddress) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, AccountNotificationNewShippingAddressXMLName, n)
+}
+
+// NewAccountNotificationUpdatedShippingAddress returns a new AccountNotificationUpdatedShippingAddress.
+func NewAccountNotificationUpdatedShippingAddress(account Account, shippingAddress ShippingAddress) *AccountNotificationUpdatedShippingAddress {
+	return &AccountNotificationUpdatedShippingAddress{
+		Account:         account,
+		ShippingAddress: shippingAddress,
+	}
+}
+
+// MarshalXML marshals the notification as <updated_shipping_address_notification>.
+func (n AccountNotificationUpdatedShippingAddress) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, AccountNotificationUpdatedShippingAddressXMLName, n)
+}
+
+// NewAccountNotificationDeletedShippingAddress returns a new AccountNotificationDeletedShippingAddress.
+func NewAccountNotificationDeletedShippingAddress(account Account, shippingAddress ShippingAddress) *AccountNotificationDeletedShippingAddress {
+	return &AccountNotificationDeletedShippingAddress{
+		Account:         account,
+		ShippingAddress: shippingAddress,
+	}
+}
+
+// MarshalXML marshals the notification as <deleted_shipping_address_notification>.
+func (n AccountNotificationDeletedShippingAddress) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, AccountNotificationDeletedShippingAddressXMLName, n)
+}
@@ -0,0 +1,89 @@
+package webhooks
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// encodeNotification encodes the fields of the notification v inside a
+// root element named name.
+func encodeNotification(e *xml.Encoder, name string, v interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Type().Field(i)
+		tag := strings.Split(f.Tag.Get("xml"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		field := rv.Field(i)
+		if field.Kind() == reflect.Ptr && field.IsNil() {
+			continue
+		}
+		if err := e.EncodeElement(field.Interface(), xml.StartElement{Name: xml.Name{Local: tag}}); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// Build returns the XML document Recurly would send for notification,
+// which must be one of the notification types in this package or a
+// *GenericNotification. It is the inverse of Parse and is intended for
+// testing webhook handlers. A nil notification returns ErrNilNotification.
+func Build(notification interface{}) ([]byte, error) {
+	if v := reflect.ValueOf(notification); !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return nil, ErrNilNotification
+	}
+
+	if n, ok := notification.(*GenericNotification); ok {
+		return n.Raw, nil
+	} else if _, ok := notification.(xml.Marshaler); !ok {
+		return nil, fmt.Errorf("webhooks: cannot build notification of type %T", notification)
+	}
+
+	b, err := xml.MarshalIndent(notification, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.Write(b)
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// Post builds notification and POSTs it to url the way Recurly delivers
+// webhooks. If username is not empty the request uses HTTP Basic auth.
+// If client is nil, http.DefaultClient is used.
+func Post(client *http.Client, url, username, password string, notification interface{}) (*http.Response, error) {
+	body, err := Build(notification)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
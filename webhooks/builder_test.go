@@ -0,0 +1,127 @@
+package webhooks
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kmikiy/recurly"
+)
+
+func TestBuild_RoundTrip(t *testing.T) {
+	files, err := filepath.Glob("testdata/*/*.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var n int
+	for _, file := range files {
+		if strings.HasPrefix(file, filepath.Join("testdata", "registry")) {
+			continue
+		}
+
+		expected := MustParseFile(file)
+		b, err := Build(expected)
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		} else if !bytes.HasPrefix(b, []byte(xml.Header)) {
+			t.Fatalf("%s: expected xml header: %s", file, b)
+		}
+
+		given, err := Parse(bytes.NewReader(b))
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		} else if !reflect.DeepEqual(given, expected) {
+			t.Fatalf("%s: unexpected notification: %s", file, b)
+		}
+		n++
+	}
+
+	if n != len(builtinNotifications) {
+		t.Fatalf("expected a fixture for each of the %d notifications, given %d", len(builtinNotifications), n)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	n := NewPaymentNotificationFailed(Account{Code: "1", Email: "verena@example.com"}, Transaction{
+		UUID:          "a5143c1d3a6f4a8287d0e2cc1d4c0427",
+		AmountInCents: 1000,
+		Status:        "declined",
+		FailureType:   TransactionFailureTypeDeclined,
+		Test:          recurly.NewBool(true),
+	})
+
+	b, err := Build(n)
+	if err != nil {
+		t.Fatal(err)
+	} else if string(b) != `<?xml version="1.0" encoding="UTF-8"?>
+<failed_payment_notification>
+  <account>
+    <account_code>1</account_code>
+    <email>verena@example.com</email>
+  </account>
+  <transaction>
+    <id>a5143c1d3a6f4a8287d0e2cc1d4c0427</id>
+    <amount_in_cents>1000</amount_in_cents>
+    <status>declined</status>
+    <failure_type>declined</failure_type>
+    <test>true</test>
+  </transaction>
+</failed_payment_notification>
+` {
+		t.Fatalf("unexpected xml: %s", b)
+	}
+
+	if _, err := Build(struct{}{}); err == nil || err.Error() != "webhooks: cannot build notification of type struct {}" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := Build(nil); err != ErrNilNotification {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := Build((*AccountNotificationNew)(nil)); err != ErrNilNotification {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := Build((*GenericNotification)(nil)); err != ErrNilNotification {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := Post(nil, "http://127.0.0.1:0", "", "", nil); err != ErrNilNotification {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPost(t *testing.T) {
+	var received interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("unexpected method: %s", r.Method)
+		} else if r.Header.Get("Content-Type") != "application/xml; charset=utf-8" {
+			t.Fatalf("unexpected content type: %s", r.Header.Get("Content-Type"))
+		} else if user, pass, ok := r.BasicAuth(); !ok || user != "recurly" || pass != "secret" {
+			t.Fatalf("unexpected basic auth: %s %s", user, pass)
+		}
+
+		n, err := Parse(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		received = n
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	n := NewAccountNotificationNew(Account{Code: "1"})
+	resp, err := Post(nil, server.URL, "recurly", "secret", n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("unexpected status: %d", resp.StatusCode)
+	} else if !reflect.DeepEqual(received, &AccountNotificationNew{
+		Account: Account{XMLName: xml.Name{Local: "account"}, Code: "1"},
+	}) {
+		t.Fatalf("unexpected notification: %#v", received)
+	}
+}
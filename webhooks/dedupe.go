@@ -0,0 +1,273 @@
+package webhooks
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+// identityFields are the fields of a notification's nested objects (account,
+// subscription, invoice, ...) that identify the event. Timestamps are also
+// included so that, for example, each renewal of a subscription has a
+// different identity.
+var identityFields = map[string]bool{
+	"Code":             true,
+	"UUID":             true,
+	"ID":               true,
+	"SubscriptionUUID": true,
+	"InvoiceNumber":    true,
+	"State":            true,
+}
+
+// ErrNilNotification is returned by Deduplicator.Handle, Build and Post for
+// a nil notification.
+var ErrNilNotification = errors.New("webhooks: nil notification")
+
+var (
+	nullTimeType = reflect.TypeOf(recurly.NullTime{})
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// Identity returns a stable identity for a notification parsed by Parse.
+// It is computed from the notification type and the UUIDs, IDs, states and
+// timestamps of the objects it contains, so a notification that Recurly
+// retries has the same identity as the original. It returns an empty
+// string for a nil notification or nil pointer.
+func Identity(notification interface{}) string {
+	v := reflect.Indirect(reflect.ValueOf(notification))
+	if !v.IsValid() {
+		return ""
+	}
+
+	var parts []string
+	if n, ok := v.Interface().(GenericNotification); ok {
+		sum := sha256.Sum256(n.Raw)
+		parts = append(parts, n.Name(), hex.EncodeToString(sum[:]))
+	} else {
+		parts = append(parts, v.Type().String())
+		if v.Kind() == reflect.Struct {
+			for i := 0; i < v.NumField(); i++ {
+				parts = appendIdentity(parts, v.Type().Field(i).Name, v.Field(i))
+			}
+		}
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// appendIdentity appends the identifying fields of the object in v.
+func appendIdentity(parts []string, name string, v reflect.Value) []string {
+	v = reflect.Indirect(v)
+	if !v.IsValid() || v.Kind() != reflect.Struct || v.Type() == nullTimeType {
+		return parts
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		field := v.Field(i)
+		switch {
+		case f.Type == nullTimeType:
+			if t := field.Interface().(recurly.NullTime); t.Time != nil {
+				parts = append(parts, fmt.Sprintf("%s.%s=%s", name, f.Name, t.Time.UTC().Format(time.RFC3339)))
+			}
+		case f.Type == timeType:
+			if t := field.Interface().(time.Time); !t.IsZero() {
+				parts = append(parts, fmt.Sprintf("%s.%s=%s", name, f.Name, t.UTC().Format(time.RFC3339)))
+			}
+		case identityFields[f.Name]:
+			parts = append(parts, fmt.Sprintf("%s.%s=%v", name, f.Name, field.Interface()))
+		}
+	}
+	return parts
+}
+
+// Store remembers the identities of processed notifications.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Has returns true if id was stored.
+	Has(id string) (bool, error)
+
+	// Put stores id.
+	Put(id string) error
+}
+
+// MemoryStore is a Store held in memory. It does not survive restarts.
+type MemoryStore struct {
+	mu  sync.RWMutex
+	ids map[string]struct{}
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{ids: map[string]struct{}{}}
+}
+
+// Has implements Store.
+func (s *MemoryStore) Has(id string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.ids[id]
+	return ok, nil
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids[id] = struct{}{}
+	return nil
+}
+
+// FileStore is a Store backed by an append-only file with one identity per
+// line. Each Put is synced to disk before it returns.
+type FileStore struct {
+	mu   sync.Mutex
+	file *os.File
+	ids  map[string]struct{}
+}
+
+// OpenFileStore opens or creates the FileStore at path.
+func OpenFileStore(path string) (*FileStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileStore{file: file, ids: map[string]struct{}{}}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if id := strings.TrimSpace(scanner.Text()); id != "" {
+			s.ids[id] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Has implements Store.
+func (s *FileStore) Has(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.ids[id]
+	return ok, nil
+}
+
+// Put implements Store.
+func (s *FileStore) Put(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.ids[id]; ok {
+		return nil
+	}
+
+	if _, err := s.file.WriteString(id + "\n"); err != nil {
+		return err
+	}
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+	s.ids[id] = struct{}{}
+	return nil
+}
+
+// Close closes the underlying file.
+func (s *FileStore) Close() error {
+	return s.file.Close()
+}
+
+// HandlerFunc processes a parsed notification.
+type HandlerFunc func(notification interface{}) error
+
+// Deduplicator runs a handler at most once per notification identity.
+// Concurrent deliveries of the same notification are serialized, so the
+// second one sees the first as processed.
+type Deduplicator struct {
+	store Store
+
+	mu    sync.Mutex
+	locks map[string]*identityLock
+}
+
+type identityLock struct {
+	sync.Mutex
+	refs int
+}
+
+// NewDeduplicator returns a Deduplicator that records processed
+// notifications in store.
+func NewDeduplicator(store Store) *Deduplicator {
+	return &Deduplicator{store: store, locks: map[string]*identityLock{}}
+}
+
+// Handle calls h unless a notification with the same identity was already
+// processed. It returns false if h was skipped. A notification is only
+// recorded as processed if h returns nil, so a failed delivery is processed
+// again when Recurly retries it. A nil notification is skipped with
+// ErrNilNotification.
+func (d *Deduplicator) Handle(notification interface{}, h HandlerFunc) (bool, error) {
+	id := Identity(notification)
+	if id == "" {
+		return false, ErrNilNotification
+	}
+	unlock := d.lock(id)
+	defer unlock()
+
+	if ok, err := d.store.Has(id); err != nil {
+		return false, err
+	} else if ok {
+		return false, nil
+	}
+
+	if err := h(notification); err != nil {
+		return true, err
+	}
+	return true, d.store.Put(id)
+}
+
+// Wrap returns a HandlerFunc that calls h through Handle.
+func (d *Deduplicator) Wrap(h HandlerFunc) HandlerFunc {
+	return func(notification interface{}) error {
+		_, err := d.Handle(notification, h)
+		return err
+	}
+}
+
+// lock locks id and returns a function that unlocks it.
+func (d *Deduplicator) lock(id string) func() {
+	d.mu.Lock()
+	l, ok := d.locks[id]
+	if !ok {
+		l = &identityLock{}
+		d.locks[id] = l
+	}
+	l.refs++
+	d.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		d.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(d.locks, id)
+		}
+		d.mu.Unlock()
+	}
+}
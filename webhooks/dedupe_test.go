@@ -0,0 +1,184 @@
+package webhooks
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+func MustParseFile(name string) interface{} {
+	n, err := NewRegistry().Parse(MustOpenFile(name))
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func TestIdentity(t *testing.T) {
+	renewed := MustParseFile("testdata/subscriptions/renewed_subscription_notification.xml")
+	if Identity(renewed) != Identity(MustParseFile("testdata/subscriptions/renewed_subscription_notification.xml")) {
+		t.Fatal("expected identity to be stable")
+	} else if len(Identity(renewed)) != 64 {
+		t.Fatalf("unexpected identity: %s", Identity(renewed))
+	}
+
+	// The next renewal of the same subscription has a different identity.
+	next := *renewed.(*SubscriptionNotificationRenewed)
+	ends := next.Subscription.CurrentPeriodEndsAt.Time.AddDate(0, 1, 0)
+	next.Subscription.CurrentPeriodEndsAt = recurly.NewTime(ends)
+	if Identity(renewed) == Identity(&next) {
+		t.Fatal("expected identity to change with timestamps")
+	}
+
+	// The same objects in a different notification type have a different identity.
+	renewedAsNew := SubscriptionNotificationNew(*renewed.(*SubscriptionNotificationRenewed))
+	if Identity(renewed) == Identity(&renewedAsNew) {
+		t.Fatal("expected identity to include the notification type")
+	}
+
+	// Generic notifications are identified by their name and raw XML.
+	r := NewRegistry()
+	r.SetFallback(true)
+	g0, _ := r.Parse(MustOpenFile("testdata/registry/new_dunning_campaign_notification.xml"))
+	g1, _ := r.Parse(MustOpenFile("testdata/registry/new_dunning_campaign_notification.xml"))
+	g2, _ := r.Parse(MustOpenFile("testdata/unknown_notification.xml"))
+	if Identity(g0) != Identity(g1) {
+		t.Fatal("expected generic identity to be stable")
+	} else if Identity(g0) == Identity(g2) {
+		t.Fatal("expected generic identities to differ")
+	}
+}
+
+func TestIdentity_Nil(t *testing.T) {
+	var n *SubscriptionNotificationNew
+	if id := Identity(nil); id != "" {
+		t.Fatalf("unexpected identity: %s", id)
+	} else if id := Identity(n); id != "" {
+		t.Fatalf("unexpected identity: %s", id)
+	}
+
+	d := NewDeduplicator(NewMemoryStore())
+	h := func(interface{}) error {
+		t.Fatal("expected handler to be skipped")
+		return nil
+	}
+	if handled, err := d.Handle(nil, h); handled || err != ErrNilNotification {
+		t.Fatalf("unexpected result: %v %v", handled, err)
+	} else if handled, err := d.Handle(n, h); handled || err != ErrNilNotification {
+		t.Fatalf("unexpected result: %v %v", handled, err)
+	}
+}
+
+func TestDeduplicator_Handle(t *testing.T) {
+	d := NewDeduplicator(NewMemoryStore())
+	n := MustParseFile("testdata/subscriptions/renewed_subscription_notification.xml")
+
+	var mu sync.Mutex
+	var calls int
+	h := func(interface{}) error {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	var handled int
+	var hmu sync.Mutex
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := d.Handle(MustParseFile("testdata/subscriptions/renewed_subscription_notification.xml"), h)
+			if err != nil {
+				t.Error(err)
+			}
+			if ok {
+				hmu.Lock()
+				handled++
+				hmu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 || handled != 1 {
+		t.Fatalf("expected handler to be called once, given %d calls and %d handled", calls, handled)
+	} else if ok, err := d.Handle(n, h); err != nil || ok {
+		t.Fatalf("unexpected result: %v %v", ok, err)
+	} else if len(d.locks) != 0 {
+		t.Fatalf("expected locks to be released: %d", len(d.locks))
+	}
+}
+
+func TestDeduplicator_HandlerError(t *testing.T) {
+	d := NewDeduplicator(NewMemoryStore())
+	n := MustParseFile("testdata/payments/failed_payment_notification.xml")
+
+	var calls int
+	wrapped := d.Wrap(func(interface{}) error {
+		calls++
+		if calls == 1 {
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+
+	if err := wrapped(n); err == nil || err.Error() != "database unavailable" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := wrapped(n); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := wrapped(n); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if calls != 2 {
+		t.Fatalf("expected failed notification to be retried once, given %d calls", calls)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "processed")
+
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	} else if ok, err := s.Has("a"); err != nil || ok {
+		t.Fatalf("unexpected Has: %v %v", ok, err)
+	} else if err := s.Put("a"); err != nil {
+		t.Fatal(err)
+	} else if err := s.Put("a"); err != nil {
+		t.Fatal(err)
+	} else if err := s.Put("b"); err != nil {
+		t.Fatal(err)
+	} else if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, err := ioutil.ReadFile(path); err != nil {
+		t.Fatal(err)
+	} else if string(b) != "a\nb\n" {
+		t.Fatalf("unexpected file: %q", b)
+	}
+
+	s, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if ok, err := s.Has("a"); err != nil || !ok {
+		t.Fatalf("expected a to be stored: %v %v", ok, err)
+	} else if ok, err := s.Has("c"); err != nil || ok {
+		t.Fatalf("unexpected Has: %v %v", ok, err)
+	}
+}
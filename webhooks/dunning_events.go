@@ -1,6 +1,10 @@
 package webhooks
 
-import "github.com/kmikiy/recurly"
+import (
+	"encoding/xml"
+
+	"github.com/kmikiy/recurly"
+)
 
 const (
 	// Dunning event notifications.
@@ -18,3 +22,18 @@ type (
 		Transaction  Transaction          `xml:"transaction"`
 	}
 )
+
+// NewDunningEventNotificationNew returns a new DunningEventNotificationNew.
+func NewDunningEventNotificationNew(account Account, invoice Invoice, subscription recurly.Subscription, transaction Transaction) *DunningEventNotificationNew {
+	return &DunningEventNotificationNew{
+		Account:      account,
+		Invoice:      invoice,
+		Subscription: subscription,
+		Transaction:  transaction,
+	}
+}
+
+// MarshalXML marshals the notification as <new_dunning_event_notification>.
+func (n DunningEventNotificationNew) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, DunningEventNotificationNewXMLName, n)
+}
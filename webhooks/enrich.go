@@ -0,0 +1,195 @@
+package webhooks
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/kmikiy/recurly"
+)
+
+// Enriched holds a notification along with the full API resources it
+// refers to. Resources the notification does not refer to, or that no
+// longer exist, are nil.
+type Enriched struct {
+	Notification interface{}
+	Account      *recurly.Account
+	Invoice      *recurly.Invoice
+	Subscription *recurly.Subscription
+	Transaction  *recurly.Transaction
+}
+
+// Enricher fetches the full resources referenced by notifications. Lookups
+// for a notification run concurrently, and concurrent lookups of the same
+// resource, for example from several notifications for one account, share
+// a single request. It is safe for concurrent use.
+type Enricher struct {
+	client *recurly.Client
+
+	mu       sync.Mutex
+	inflight map[string]*lookup
+}
+
+// lookup is a request shared by concurrent callers.
+type lookup struct {
+	wg  sync.WaitGroup
+	v   interface{}
+	err error
+}
+
+// ErrLookup is returned by Enrich when a lookup returns an error status
+// other than not found, such as when rate limited. It implements the error
+// interface.
+type ErrLookup struct {
+	Response *recurly.Response
+}
+
+// Error implements the error interface.
+func (e ErrLookup) Error() string {
+	return fmt.Sprintf("webhooks: %s %s: %d", e.Response.Request.Method, e.Response.Request.URL.Path, e.Response.StatusCode)
+}
+
+// lookupError returns err, or an ErrLookup if resp has an error status
+// other than not found.
+func lookupError(resp *recurly.Response, err error) error {
+	if err != nil {
+		return err
+	} else if resp.IsError() && resp.StatusCode != http.StatusNotFound {
+		return ErrLookup{Response: resp}
+	}
+	return nil
+}
+
+// NewEnricher returns an Enricher that uses client for lookups.
+func NewEnricher(client *recurly.Client) *Enricher {
+	return &Enricher{client: client, inflight: map[string]*lookup{}}
+}
+
+// Enrich fetches the account, invoice, subscription and transaction
+// referenced by notification. Resources that are not found are left nil.
+// If a lookup fails, or returns an error status other than not found, the
+// first error is returned, which is an ErrLookup for error statuses.
+func (e *Enricher) Enrich(notification interface{}) (*Enriched, error) {
+	accountCode, invoiceNumber, subscriptionUUID, transactionUUID := references(notification)
+	enriched := &Enriched{Notification: notification}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	fetch := func(key string, fn func() (interface{}, error), set func(interface{})) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := e.do(key, fn)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			set(v)
+		}()
+	}
+
+	if accountCode != "" {
+		fetch("account:"+accountCode, func() (interface{}, error) {
+			resp, a, err := e.client.Accounts.Get(accountCode)
+			return a, lookupError(resp, err)
+		}, func(v interface{}) { enriched.Account = v.(*recurly.Account) })
+	}
+	if invoiceNumber != 0 {
+		fetch("invoice:"+strconv.Itoa(invoiceNumber), func() (interface{}, error) {
+			resp, i, err := e.client.Invoices.Get(invoiceNumber)
+			return i, lookupError(resp, err)
+		}, func(v interface{}) { enriched.Invoice = v.(*recurly.Invoice) })
+	}
+	if subscriptionUUID != "" {
+		fetch("subscription:"+subscriptionUUID, func() (interface{}, error) {
+			resp, s, err := e.client.Subscriptions.Get(subscriptionUUID)
+			return s, lookupError(resp, err)
+		}, func(v interface{}) { enriched.Subscription = v.(*recurly.Subscription) })
+	}
+	if transactionUUID != "" {
+		fetch("transaction:"+transactionUUID, func() (interface{}, error) {
+			resp, t, err := e.client.Transactions.Get(transactionUUID)
+			return t, lookupError(resp, err)
+		}, func(v interface{}) { enriched.Transaction = v.(*recurly.Transaction) })
+	}
+
+	wg.Wait()
+	return enriched, firstErr
+}
+
+// do calls fn, or waits for the in-flight call with the same key.
+func (e *Enricher) do(key string, fn func() (interface{}, error)) (interface{}, error) {
+	e.mu.Lock()
+	if l, ok := e.inflight[key]; ok {
+		e.mu.Unlock()
+		l.wg.Wait()
+		return l.v, l.err
+	}
+
+	l := &lookup{}
+	l.wg.Add(1)
+	e.inflight[key] = l
+	e.mu.Unlock()
+
+	l.v, l.err = fn()
+	l.wg.Done()
+
+	e.mu.Lock()
+	delete(e.inflight, key)
+	e.mu.Unlock()
+
+	return l.v, l.err
+}
+
+// references returns the identifiers of the objects in a notification.
+func references(notification interface{}) (accountCode string, invoiceNumber int, subscriptionUUID, transactionUUID string) {
+	v := reflect.Indirect(reflect.ValueOf(notification))
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	switch a := field(v, "Account").(type) {
+	case Account:
+		accountCode = a.Code
+	case *Account:
+		if a != nil {
+			accountCode = a.Code
+		}
+	}
+
+	switch i := field(v, "Invoice").(type) {
+	case Invoice:
+		invoiceNumber = i.InvoiceNumber
+	case ChargeInvoice:
+		invoiceNumber = i.InvoiceNumber
+	case CreditInvoice:
+		invoiceNumber = i.InvoiceNumber
+	}
+
+	if s, ok := field(v, "Subscription").(recurly.Subscription); ok {
+		subscriptionUUID = s.UUID
+	}
+	if t, ok := field(v, "Transaction").(Transaction); ok {
+		transactionUUID = t.UUID
+	}
+	return
+}
+
+// field returns the value of the named field of v, or nil.
+func field(v reflect.Value, name string) interface{} {
+	f := v.FieldByName(name)
+	if !f.IsValid() || !f.CanInterface() {
+		return nil
+	}
+	return f.Interface()
+}
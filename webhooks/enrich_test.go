@@ -0,0 +1,134 @@
+package webhooks
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+func TestEnricher_Enrich(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/v2/accounts/1234", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><account><account_code>1234</account_code><state>active</state></account>`)
+	})
+	mux.HandleFunc("/v2/invoices/1813", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><invoice><invoice_number>1813</invoice_number><state>past_due</state></invoice>`)
+	})
+	mux.HandleFunc("/v2/subscriptions/4110792b3b01967d854f674b7282f542", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><subscription><uuid>4110792b3b01967d854f674b7282f542</uuid><state>active</state></subscription>`)
+	})
+	mux.HandleFunc("/v2/transactions/397083a9a871b53a3d5a4c469fa1216a", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><error><symbol>not_found</symbol></error>`)
+	})
+
+	client := recurly.NewClient("test", "abc", nil, recurly.WithBaseURL(server.URL))
+	n := MustParseFile("testdata/dunning_events/new_dunning_event_notification.xml")
+
+	enriched, err := NewEnricher(client).Enrich(n)
+	if err != nil {
+		t.Fatal(err)
+	} else if enriched.Notification != n {
+		t.Fatalf("unexpected notification: %#v", enriched.Notification)
+	} else if enriched.Account == nil || enriched.Account.Code != "1234" || enriched.Account.State != "active" {
+		t.Fatalf("unexpected account: %#v", enriched.Account)
+	} else if enriched.Invoice == nil || enriched.Invoice.InvoiceNumber != 1813 || enriched.Invoice.State != "past_due" {
+		t.Fatalf("unexpected invoice: %#v", enriched.Invoice)
+	} else if enriched.Subscription == nil || enriched.Subscription.UUID != "4110792b3b01967d854f674b7282f542" {
+		t.Fatalf("unexpected subscription: %#v", enriched.Subscription)
+	} else if enriched.Transaction != nil {
+		t.Fatalf("expected missing transaction to be nil, given %#v", enriched.Transaction)
+	}
+}
+
+func TestEnricher_Enrich_Deduplicated(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var mu sync.Mutex
+	var requests int
+	mux.HandleFunc("/v2/accounts/1", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><account><account_code>1</account_code></account>`)
+	})
+
+	client := recurly.NewClient("test", "abc", nil, recurly.WithBaseURL(server.URL))
+	e := NewEnricher(client)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			enriched, err := e.Enrich(NewAccountNotificationUpdated(Account{Code: "1"}))
+			if err != nil {
+				t.Error(err)
+			} else if enriched.Account == nil || enriched.Account.Code != "1" {
+				t.Errorf("unexpected account: %#v", enriched.Account)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if requests != 1 {
+		t.Fatalf("expected concurrent lookups to share a request, given %d requests", requests)
+	} else if len(e.inflight) != 0 {
+		t.Fatalf("expected in-flight lookups to be released: %d", len(e.inflight))
+	}
+}
+
+func TestEnricher_Enrich_Error(t *testing.T) {
+	client := recurly.NewClient("test", "abc", nil, recurly.WithBaseURL("http://127.0.0.1:0"))
+	enriched, err := NewEnricher(client).Enrich(NewAccountNotificationNew(Account{Code: "1"}))
+	if err == nil {
+		t.Fatal("expected error. None given.")
+	} else if enriched.Account != nil {
+		t.Fatalf("unexpected account: %#v", enriched.Account)
+	}
+
+	// Notifications without references make no requests.
+	if enriched, err := NewEnricher(client).Enrich(NewGiftCardNotificationPurchased(GiftCard{})); err != nil {
+		t.Fatal(err)
+	} else if enriched.Account != nil || enriched.Invoice != nil || enriched.Subscription != nil || enriched.Transaction != nil {
+		t.Fatalf("unexpected resources: %#v", enriched)
+	}
+}
+
+func TestEnricher_Enrich_ErrorStatus(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/v2/accounts/1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><error><symbol>rate_limited</symbol></error>`)
+	})
+
+	client := recurly.NewClient("test", "abc", nil, recurly.WithBaseURL(server.URL))
+	enriched, err := NewEnricher(client).Enrich(NewAccountNotificationNew(Account{Code: "1"}))
+	if e, ok := err.(ErrLookup); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	} else if e.Response.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("unexpected status: %d", e.Response.StatusCode)
+	} else if err.Error() != "webhooks: GET /v2/accounts/1: 429" {
+		t.Fatalf("unexpected error string: %s", err.Error())
+	} else if enriched.Account != nil {
+		t.Fatalf("unexpected account: %#v", enriched.Account)
+	}
+}
@@ -1,5 +1,7 @@
 package webhooks
 
+import "encoding/xml"
+
 const (
 	// Gift Card notifications.
 	GiftCardNotificationPurchasedXMLName      = "purchased_gift_card_notification"
@@ -8,6 +10,7 @@ const (
 	GiftCardNotificationRegeneratedXMLName    = "regenerated_gift_card_notification"
 	GiftCardNotificationRedeemedXMLName       = "redeemed_gift_card_notification"
 	GiftCardNotificationUpdatedBalanceXMLName = "updated_balance_gift_card_notification"
+	GiftCardNotificationLowBalanceXMLName     = "low_balance_gift_card_notification"
 )
 
 // GiftCard types.
@@ -47,4 +50,94 @@ type (
 	GiftCardNotificationUpdatedBalance struct {
 		GiftCard GiftCard `xml:"gift_card,omitempty"`
 	}
+
+	// GiftCardNotificationLowBalance is sent when the balance of a redeemed gift card runs low.
+	// https://dev.recurly.com/page/webhooks#section-low-balance-gift-card
+	GiftCardNotificationLowBalance struct {
+		GiftCard GiftCard `xml:"gift_card,omitempty"`
+	}
 )
+
+// NewGiftCardNotificationPurchased returns a new GiftCardNotificationPurchased.
+func NewGiftCardNotificationPurchased(giftCard GiftCard) *GiftCardNotificationPurchased {
+	return &GiftCardNotificationPurchased{
+		GiftCard: giftCard,
+	}
+}
+
+// MarshalXML marshals the notification as <purchased_gift_card_notification>.
+func (n GiftCardNotificationPurchased) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, GiftCardNotificationPurchasedXMLName, n)
+}
+
+// NewGiftCardNotificationCanceled returns a new GiftCardNotificationCanceled.
+func NewGiftCardNotificationCanceled(giftCard GiftCard) *GiftCardNotificationCanceled {
+	return &GiftCardNotificationCanceled{
+		GiftCard: giftCard,
+	}
+}
+
+// MarshalXML marshals the notification as <canceled_gift_card_notification>.
+func (n GiftCardNotificationCanceled) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, GiftCardNotificationCanceledXMLName, n)
+}
+
+// NewGiftCardNotificationUpdated returns a new GiftCardNotificationUpdated.
+func NewGiftCardNotificationUpdated(giftCard GiftCard) *GiftCardNotificationUpdated {
+	return &GiftCardNotificationUpdated{
+		GiftCard: giftCard,
+	}
+}
+
+// MarshalXML marshals the notification as <updated_gift_card_notification>.
+func (n GiftCardNotificationUpdated) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, GiftCardNotificationUpdatedXMLName, n)
+}
+
+// NewGiftCardNotificationRegenerated returns a new GiftCardNotificationRegenerated.
+func NewGiftCardNotificationRegenerated(giftCard GiftCard) *GiftCardNotificationRegenerated {
+	return &GiftCardNotificationRegenerated{
+		GiftCard: giftCard,
+	}
+}
+
+// MarshalXML marshals the notification as <regenerated_gift_card_notification>.
+func (n GiftCardNotificationRegenerated) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, GiftCardNotificationRegeneratedXMLName, n)
+}
+
+// NewGiftCardNotificationRedeemed returns a new GiftCardNotificationRedeemed.
+func NewGiftCardNotificationRedeemed(giftCard GiftCard) *GiftCardNotificationRedeemed {
+	return &GiftCardNotificationRedeemed{
+		GiftCard: giftCard,
+	}
+}
+
+// MarshalXML marshals the notification as <redeemed_gift_card_notification>.
+func (n GiftCardNotificationRedeemed) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, GiftCardNotificationRedeemedXMLName, n)
+}
+
+// NewGiftCardNotificationUpdatedBalance returns a new GiftCardNotificationUpdatedBalance.
+func NewGiftCardNotificationUpdatedBalance(giftCard GiftCard) *GiftCardNotificationUpdatedBalance {
+	return &GiftCardNotificationUpdatedBalance{
+		GiftCard: giftCard,
+	}
+}
+
+// MarshalXML marshals the notification as <updated_balance_gift_card_notification>.
+func (n GiftCardNotificationUpdatedBalance) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, GiftCardNotificationUpdatedBalanceXMLName, n)
+}
+
+// NewGiftCardNotificationLowBalance returns a new GiftCardNotificationLowBalance.
+func NewGiftCardNotificationLowBalance(giftCard GiftCard) *GiftCardNotificationLowBalance {
+	return &GiftCardNotificationLowBalance{
+		GiftCard: giftCard,
+	}
+}
+
+// MarshalXML marshals the notification as <low_balance_gift_card_notification>.
+func (n GiftCardNotificationLowBalance) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, GiftCardNotificationLowBalanceXMLName, n)
+}
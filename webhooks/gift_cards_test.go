@@ -231,10 +231,53 @@ func TestParse_GiftCardNotificationUpdatedBalance(t *testing.T) {
 				GifterName:      "Sally",
 				PersonalMessage: "Hi John, Happy Birthday! I hope you have a great day! Love, Sally",
 			},
-			CreatedAt:   recurly.NewTime(createdTs),
-			UpdatedAt:   recurly.NewTime(updatedTs),
-			DeliveredAt: recurly.NewTime(deliveredTs),
-			RedeemedAt:  recurly.NewTime(redeemedTs),
+			CreatedAt:      recurly.NewTime(createdTs),
+			UpdatedAt:      recurly.NewTime(updatedTs),
+			DeliveredAt:    recurly.NewTime(deliveredTs),
+			RedeemedAt:     recurly.NewTime(redeemedTs),
+			BalanceInCents: recurly.NewInt(200),
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestParse_GiftCardNotificationLowBalance(t *testing.T) {
+	createdTs, _ := time.Parse(recurly.DateTimeFormat, "2016-07-29T21:41:11Z")
+	updatedTs, _ := time.Parse(recurly.DateTimeFormat, "2016-08-02T23:50:38Z")
+	deliveredTs, _ := time.Parse(recurly.DateTimeFormat, "2016-07-29T21:50:38Z")
+	redeemedTs, _ := time.Parse(recurly.DateTimeFormat, "2016-07-29T21:50:38Z")
+
+	xmlFile := MustOpenFile("testdata/gift_cards/low_balance_gift_card_notification.xml")
+	result, err := Parse(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*GiftCardNotificationLowBalance); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if !reflect.DeepEqual(n, &GiftCardNotificationLowBalance{
+		GiftCard: GiftCard{
+			XMLName:              xml.Name{Local: "gift_card"},
+			RedemptionCode:       "AB54200960E33C93",
+			ID:                   2005384587788419212,
+			ProductCode:          "gift_card",
+			UnitAmountInCents:    recurly.NewInt(1000),
+			Currency:             "USD",
+			GifterAccountCode:    "3543456",
+			RecipientAccountCode: "3547000",
+			InvoiceNumber:        1099,
+			Delivery: GiftCardDelivery{
+				Method:          "email",
+				EmailAddress:    "john@example.com",
+				FirstName:       "John",
+				LastName:        "Smith",
+				GifterName:      "Sally",
+				PersonalMessage: "Hi John, Happy Birthday! I hope you have a great day! Love, Sally",
+			},
+			CreatedAt:      recurly.NewTime(createdTs),
+			UpdatedAt:      recurly.NewTime(updatedTs),
+			DeliveredAt:    recurly.NewTime(deliveredTs),
+			RedeemedAt:     recurly.NewTime(redeemedTs),
+			BalanceInCents: recurly.NewInt(100),
 		},
 	}) {
 		t.Fatalf("unexpected notification: %#v", n)
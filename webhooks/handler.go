@@ -0,0 +1,40 @@
+package webhooks
+
+import "net/http"
+
+// Handler returns an http.Handler that parses incoming webhooks with
+// DefaultRegistry and passes them to h.
+func Handler(h HandlerFunc) http.Handler {
+	return DefaultRegistry.Handler(h)
+}
+
+// Handler returns an http.Handler that parses incoming webhooks with the
+// registry and passes them to h. It responds with:
+//   - 200 if h returns nil
+//   - 204 for unknown notifications, so Recurly does not retry them
+//   - 400 if the webhook cannot be parsed
+//   - 500 if h returns an error, so Recurly retries the webhook
+func (r *Registry) Handler(h HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		notification, err := r.Parse(req.Body)
+		if _, ok := err.(ErrUnknownNotification); ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := h(notification); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
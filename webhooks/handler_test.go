@@ -0,0 +1,97 @@
+package webhooks
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	var handled interface{}
+	var handlerErr error
+	server := httptest.NewServer(Handler(func(n interface{}) error {
+		handled = n
+		return handlerErr
+	}))
+	defer server.Close()
+
+	readFile := func(name string) []byte {
+		b, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	post := func(body []byte) int {
+		resp, err := http.Post(server.URL, "application/xml", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Known notifications are passed to the handler.
+	body := readFile("testdata/subscriptions/new_subscription_notification.xml")
+	if code := post(body); code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", code)
+	} else if _, ok := handled.(*SubscriptionNotificationNew); !ok {
+		t.Fatalf("unexpected notification: %#v", handled)
+	}
+
+	// Handler errors are reported so Recurly retries.
+	handled, handlerErr = nil, errors.New("failed")
+	if code := post(body); code != http.StatusInternalServerError {
+		t.Fatalf("unexpected status: %d", code)
+	} else if handled == nil {
+		t.Fatal("expected handler to be called")
+	}
+
+	// Unknown notifications are acknowledged without calling the handler.
+	handled, handlerErr = nil, nil
+	if code := post(readFile("testdata/unknown_notification.xml")); code != http.StatusNoContent {
+		t.Fatalf("unexpected status: %d", code)
+	} else if handled != nil {
+		t.Fatalf("unexpected notification: %#v", handled)
+	}
+
+	// Invalid XML is rejected.
+	if code := post([]byte("<new_subscription_notification>")); code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: %d", code)
+	} else if handled != nil {
+		t.Fatalf("unexpected notification: %#v", handled)
+	}
+
+	// Only POST is allowed.
+	if resp, err := http.Get(server.URL); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("unexpected status: %d", resp.StatusCode)
+	}
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := NewRegistry()
+	r.SetFallback(true)
+
+	var handled interface{}
+	server := httptest.NewServer(r.Handler(func(n interface{}) error {
+		handled = n
+		return nil
+	}))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/xml", MustOpenFile("testdata/unknown_notification.xml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status: %d", resp.StatusCode)
+	} else if _, ok := handled.(*GenericNotification); !ok {
+		t.Fatalf("unexpected notification: %#v", handled)
+	}
+}
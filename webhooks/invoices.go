@@ -1,10 +1,23 @@
 package webhooks
 
+import "encoding/xml"
+
 const (
 	// Invoice notifications.
 	InvoiceNotificationNewXMLName     = "new_invoice_notification"
 	InvoiceNotificationPastDueXMLName = "past_due_invoice_notification"
 	InvoiceNotificationClosedXMLName  = "closed_invoice_notification"
+
+	// Charge invoice notifications.
+	ChargeInvoiceNotificationNewXMLName        = "new_charge_invoice_notification"
+	ChargeInvoiceNotificationProcessingXMLName = "processing_charge_invoice_notification"
+	ChargeInvoiceNotificationClosedXMLName     = "closed_charge_invoice_notification"
+	ChargeInvoiceNotificationPastDueXMLName    = "past_due_charge_invoice_notification"
+
+	// Credit invoice notifications.
+	CreditInvoiceNotificationNewXMLName        = "new_credit_invoice_notification"
+	CreditInvoiceNotificationProcessingXMLName = "processing_credit_invoice_notification"
+	CreditInvoiceNotificationClosedXMLName     = "closed_credit_invoice_notification"
 )
 
 // Invoice types.
@@ -30,3 +43,191 @@ type (
 		Invoice Invoice `xml:"invoice"`
 	}
 )
+
+// Charge invoice types.
+type (
+	// ChargeInvoiceNotificationNew is sent when a charge invoice is created.
+	// https://dev.recurly.com/page/webhooks#section-new-charge-invoice
+	ChargeInvoiceNotificationNew struct {
+		Account Account       `xml:"account"`
+		Invoice ChargeInvoice `xml:"invoice"`
+	}
+
+	// ChargeInvoiceNotificationProcessing is sent when a charge invoice is
+	// paid by a payment method that takes time to settle, such as ACH.
+	// https://dev.recurly.com/page/webhooks#section-processing-charge-invoice
+	ChargeInvoiceNotificationProcessing struct {
+		Account Account       `xml:"account"`
+		Invoice ChargeInvoice `xml:"invoice"`
+	}
+
+	// ChargeInvoiceNotificationClosed is sent when a charge invoice is paid or failed.
+	// https://dev.recurly.com/page/webhooks#section-closed-charge-invoice
+	ChargeInvoiceNotificationClosed struct {
+		Account Account       `xml:"account"`
+		Invoice ChargeInvoice `xml:"invoice"`
+	}
+
+	// ChargeInvoiceNotificationPastDue is sent when a charge invoice is past due.
+	// https://dev.recurly.com/page/webhooks#section-past-due-charge-invoice
+	ChargeInvoiceNotificationPastDue struct {
+		Account Account       `xml:"account"`
+		Invoice ChargeInvoice `xml:"invoice"`
+	}
+)
+
+// Credit invoice types.
+type (
+	// CreditInvoiceNotificationNew is sent when a credit invoice is created.
+	// https://dev.recurly.com/page/webhooks#section-new-credit-invoice
+	CreditInvoiceNotificationNew struct {
+		Account Account       `xml:"account"`
+		Invoice CreditInvoice `xml:"invoice"`
+	}
+
+	// CreditInvoiceNotificationProcessing is sent when a credit invoice is
+	// being refunded to a payment method that takes time to settle.
+	// https://dev.recurly.com/page/webhooks#section-processing-credit-invoice
+	CreditInvoiceNotificationProcessing struct {
+		Account Account       `xml:"account"`
+		Invoice CreditInvoice `xml:"invoice"`
+	}
+
+	// CreditInvoiceNotificationClosed is sent when a credit invoice is fully
+	// applied or refunded.
+	// https://dev.recurly.com/page/webhooks#section-closed-credit-invoice
+	CreditInvoiceNotificationClosed struct {
+		Account Account       `xml:"account"`
+		Invoice CreditInvoice `xml:"invoice"`
+	}
+)
+
+// NewInvoiceNotificationNew returns a new InvoiceNotificationNew.
+func NewInvoiceNotificationNew(account Account, invoice Invoice) *InvoiceNotificationNew {
+	return &InvoiceNotificationNew{
+		Account: account,
+		Invoice: invoice,
+	}
+}
+
+// MarshalXML marshals the notification as <new_invoice_notification>.
+func (n InvoiceNotificationNew) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, InvoiceNotificationNewXMLName, n)
+}
+
+// NewInvoiceNotificationPastDue returns a new InvoiceNotificationPastDue.
+func NewInvoiceNotificationPastDue(account Account, invoice Invoice) *InvoiceNotificationPastDue {
+	return &InvoiceNotificationPastDue{
+		Account: account,
+		Invoice: invoice,
+	}
+}
+
+// MarshalXML marshals the notification as <past_due_invoice_notification>.
+func (n InvoiceNotificationPastDue) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, InvoiceNotificationPastDueXMLName, n)
+}
+
+// NewInvoiceNotificationClosed returns a new InvoiceNotificationClosed.
+func NewInvoiceNotificationClosed(account Account, invoice Invoice) *InvoiceNotificationClosed {
+	return &InvoiceNotificationClosed{
+		Account: account,
+		Invoice: invoice,
+	}
+}
+
+// MarshalXML marshals the notification as <closed_invoice_notification>.
+func (n InvoiceNotificationClosed) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, InvoiceNotificationClosedXMLName, n)
+}
+
+// NewChargeInvoiceNotificationNew returns a new ChargeInvoiceNotificationNew.
+func NewChargeInvoiceNotificationNew(account Account, invoice ChargeInvoice) *ChargeInvoiceNotificationNew {
+	return &ChargeInvoiceNotificationNew{
+		Account: account,
+		Invoice: invoice,
+	}
+}
+
+// MarshalXML marshals the notification as <new_charge_invoice_notification>.
+func (n ChargeInvoiceNotificationNew) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, ChargeInvoiceNotificationNewXMLName, n)
+}
+
+// NewChargeInvoiceNotificationProcessing returns a new ChargeInvoiceNotificationProcessing.
+func NewChargeInvoiceNotificationProcessing(account Account, invoice ChargeInvoice) *ChargeInvoiceNotificationProcessing {
+	return &ChargeInvoiceNotificationProcessing{
+		Account: account,
+		Invoice: invoice,
+	}
+}
+
+// MarshalXML marshals the notification as <processing_charge_invoice_notification>.
+func (n ChargeInvoiceNotificationProcessing) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, ChargeInvoiceNotificationProcessingXMLName, n)
+}
+
+// NewChargeInvoiceNotificationClosed returns a new ChargeInvoiceNotificationClosed.
+func NewChargeInvoiceNotificationClosed(account Account, invoice ChargeInvoice) *ChargeInvoiceNotificationClosed {
+	return &ChargeInvoiceNotificationClosed{
+		Account: account,
+		Invoice: invoice,
+	}
+}
+
+// MarshalXML marshals the notification as <closed_charge_invoice_notification>.
+func (n ChargeInvoiceNotificationClosed) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, ChargeInvoiceNotificationClosedXMLName, n)
+}
+
+// NewChargeInvoiceNotificationPastDue returns a new ChargeInvoiceNotificationPastDue.
+func NewChargeInvoiceNotificationPastDue(account Account, invoice ChargeInvoice) *ChargeInvoiceNotificationPastDue {
+	return &ChargeInvoiceNotificationPastDue{
+		Account: account,
+		Invoice: invoice,
+	}
+}
+
+// MarshalXML marshals the notification as <past_due_charge_invoice_notification>.
+func (n ChargeInvoiceNotificationPastDue) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, ChargeInvoiceNotificationPastDueXMLName, n)
+}
+
+// NewCreditInvoiceNotificationNew returns a new CreditInvoiceNotificationNew.
+func NewCreditInvoiceNotificationNew(account Account, invoice CreditInvoice) *CreditInvoiceNotificationNew {
+	return &CreditInvoiceNotificationNew{
+		Account: account,
+		Invoice: invoice,
+	}
+}
+
+// MarshalXML marshals the notification as <new_credit_invoice_notification>.
+func (n CreditInvoiceNotificationNew) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, CreditInvoiceNotificationNewXMLName, n)
+}
+
+// NewCreditInvoiceNotificationProcessing returns a new CreditInvoiceNotificationProcessing.
+func NewCreditInvoiceNotificationProcessing(account Account, invoice CreditInvoice) *CreditInvoiceNotificationProcessing {
+	return &CreditInvoiceNotificationProcessing{
+		Account: account,
+		Invoice: invoice,
+	}
+}
+
+// MarshalXML marshals the notification as <processing_credit_invoice_notification>.
+func (n CreditInvoiceNotificationProcessing) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, CreditInvoiceNotificationProcessingXMLName, n)
+}
+
+// NewCreditInvoiceNotificationClosed returns a new CreditInvoiceNotificationClosed.
+func NewCreditInvoiceNotificationClosed(account Account, invoice CreditInvoice) *CreditInvoiceNotificationClosed {
+	return &CreditInvoiceNotificationClosed{
+		Account: account,
+		Invoice: invoice,
+	}
+}
+
+// MarshalXML marshals the notification as <closed_credit_invoice_notification>.
+func (n CreditInvoiceNotificationClosed) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, CreditInvoiceNotificationClosedXMLName, n)
+}
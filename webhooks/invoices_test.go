@@ -104,3 +104,277 @@ func TestParse_ClosedInvoiceNotification(t *testing.T) {
 		t.Fatalf("unexpected notification: %v", n)
 	}
 }
+
+func TestParse_ChargeInvoiceNotificationNew(t *testing.T) {
+	createdTs, _ := time.Parse(recurly.DateTimeFormat, "2018-02-13T16:00:04Z")
+	updatedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-02-13T16:00:04Z")
+	dueTs, _ := time.Parse(recurly.DateTimeFormat, "2018-03-16T15:00:04Z")
+
+	xmlFile := MustOpenFile("testdata/invoices/new_charge_invoice_notification.xml")
+	result, err := Parse(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*ChargeInvoiceNotificationNew); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if !reflect.DeepEqual(n, &ChargeInvoiceNotificationNew{
+		Account: Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		Invoice: ChargeInvoice{
+			XMLName:           xml.Name{Local: "invoice"},
+			UUID:              "42feb03ce368c0e1ead35d4bfa89b82e",
+			State:             "pending",
+			Origin:            "purchase",
+			SubscriptionUUIDs: []string{"40b8f5e99df03b8684b99d4993b6e089"},
+			InvoiceNumber:     1000,
+			PONumber:          "PO-1234",
+			BalanceInCents:    1100,
+			TotalInCents:      1100,
+			Currency:          "USD",
+			CreatedAt:         recurly.NewTime(createdTs),
+			UpdatedAt:         recurly.NewTime(updatedTs),
+			DueOn:             recurly.NewTime(dueTs),
+			NetTerms:          recurly.NewInt(30),
+			CollectionMethod:  recurly.CollectionMethodManual,
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestParse_ChargeInvoiceNotificationProcessing(t *testing.T) {
+	createdTs, _ := time.Parse(recurly.DateTimeFormat, "2018-02-13T16:00:04Z")
+	updatedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-02-13T16:00:04Z")
+	dueTs, _ := time.Parse(recurly.DateTimeFormat, "2018-03-16T15:00:04Z")
+
+	xmlFile := MustOpenFile("testdata/invoices/processing_charge_invoice_notification.xml")
+	result, err := Parse(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*ChargeInvoiceNotificationProcessing); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if !reflect.DeepEqual(n, &ChargeInvoiceNotificationProcessing{
+		Account: Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		Invoice: ChargeInvoice{
+			XMLName:           xml.Name{Local: "invoice"},
+			UUID:              "42feb03ce368c0e1ead35d4bfa89b82e",
+			State:             "processing",
+			Origin:            "purchase",
+			SubscriptionUUIDs: []string{"40b8f5e99df03b8684b99d4993b6e089"},
+			InvoiceNumber:     1000,
+			PONumber:          "PO-1234",
+			BalanceInCents:    1100,
+			TotalInCents:      1100,
+			Currency:          "USD",
+			CreatedAt:         recurly.NewTime(createdTs),
+			UpdatedAt:         recurly.NewTime(updatedTs),
+			DueOn:             recurly.NewTime(dueTs),
+			NetTerms:          recurly.NewInt(30),
+			CollectionMethod:  recurly.CollectionMethodManual,
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestParse_ChargeInvoiceNotificationClosed(t *testing.T) {
+	createdTs, _ := time.Parse(recurly.DateTimeFormat, "2018-02-13T16:00:04Z")
+	updatedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-03-20T16:00:04Z")
+	dueTs, _ := time.Parse(recurly.DateTimeFormat, "2018-03-16T15:00:04Z")
+	closedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-03-20T16:00:04Z")
+
+	xmlFile := MustOpenFile("testdata/invoices/closed_charge_invoice_notification.xml")
+	result, err := Parse(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*ChargeInvoiceNotificationClosed); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if !reflect.DeepEqual(n, &ChargeInvoiceNotificationClosed{
+		Account: Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		Invoice: ChargeInvoice{
+			XMLName:           xml.Name{Local: "invoice"},
+			UUID:              "42feb03ce368c0e1ead35d4bfa89b82e",
+			State:             "paid",
+			Origin:            "purchase",
+			SubscriptionUUIDs: []string{"40b8f5e99df03b8684b99d4993b6e089"},
+			InvoiceNumber:     1000,
+			PONumber:          "PO-1234",
+			TotalInCents:      1100,
+			Currency:          "USD",
+			CreatedAt:         recurly.NewTime(createdTs),
+			UpdatedAt:         recurly.NewTime(updatedTs),
+			DueOn:             recurly.NewTime(dueTs),
+			ClosedAt:          recurly.NewTime(closedTs),
+			NetTerms:          recurly.NewInt(30),
+			CollectionMethod:  recurly.CollectionMethodManual,
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestParse_ChargeInvoiceNotificationPastDue(t *testing.T) {
+	createdTs, _ := time.Parse(recurly.DateTimeFormat, "2018-02-13T16:00:04Z")
+	updatedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-03-20T16:00:04Z")
+	dueTs, _ := time.Parse(recurly.DateTimeFormat, "2018-03-16T15:00:04Z")
+
+	xmlFile := MustOpenFile("testdata/invoices/past_due_charge_invoice_notification.xml")
+	result, err := Parse(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*ChargeInvoiceNotificationPastDue); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if !reflect.DeepEqual(n, &ChargeInvoiceNotificationPastDue{
+		Account: Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		Invoice: ChargeInvoice{
+			XMLName:           xml.Name{Local: "invoice"},
+			UUID:              "42feb03ce368c0e1ead35d4bfa89b82e",
+			State:             "past_due",
+			Origin:            "renewal",
+			SubscriptionUUIDs: []string{"40b8f5e99df03b8684b99d4993b6e089"},
+			InvoiceNumber:     1000,
+			PONumber:          "PO-1234",
+			BalanceInCents:    1100,
+			TotalInCents:      1100,
+			Currency:          "USD",
+			CreatedAt:         recurly.NewTime(createdTs),
+			UpdatedAt:         recurly.NewTime(updatedTs),
+			DueOn:             recurly.NewTime(dueTs),
+			NetTerms:          recurly.NewInt(30),
+			CollectionMethod:  recurly.CollectionMethodManual,
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestParse_CreditInvoiceNotificationNew(t *testing.T) {
+	createdTs, _ := time.Parse(recurly.DateTimeFormat, "2018-02-13T00:56:22Z")
+	updatedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-02-13T00:56:22Z")
+
+	xmlFile := MustOpenFile("testdata/invoices/new_credit_invoice_notification.xml")
+	result, err := Parse(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*CreditInvoiceNotificationNew); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if !reflect.DeepEqual(n, &CreditInvoiceNotificationNew{
+		Account: Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		Invoice: CreditInvoice{
+			XMLName:           xml.Name{Local: "invoice"},
+			UUID:              "42fb74de65e9395eb004614144a7b91f",
+			State:             "open",
+			Origin:            "write_off",
+			SubscriptionUUIDs: []string{"42fb74ba9efe4c6981c2064436a4e9cd"},
+			InvoiceNumber:     2404,
+			BalanceInCents:    -4882,
+			TotalInCents:      -4882,
+			Currency:          "USD",
+			CreatedAt:         recurly.NewTime(createdTs),
+			UpdatedAt:         recurly.NewTime(updatedTs),
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestParse_CreditInvoiceNotificationProcessing(t *testing.T) {
+	createdTs, _ := time.Parse(recurly.DateTimeFormat, "2018-02-13T00:56:22Z")
+	updatedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-02-13T00:56:22Z")
+
+	xmlFile := MustOpenFile("testdata/invoices/processing_credit_invoice_notification.xml")
+	result, err := Parse(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*CreditInvoiceNotificationProcessing); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if !reflect.DeepEqual(n, &CreditInvoiceNotificationProcessing{
+		Account: Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		Invoice: CreditInvoice{
+			XMLName:           xml.Name{Local: "invoice"},
+			UUID:              "42fb74de65e9395eb004614144a7b91f",
+			State:             "processing",
+			Origin:            "write_off",
+			SubscriptionUUIDs: []string{"42fb74ba9efe4c6981c2064436a4e9cd"},
+			InvoiceNumber:     2404,
+			BalanceInCents:    -4882,
+			TotalInCents:      -4882,
+			Currency:          "USD",
+			CreatedAt:         recurly.NewTime(createdTs),
+			UpdatedAt:         recurly.NewTime(updatedTs),
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestParse_CreditInvoiceNotificationClosed(t *testing.T) {
+	createdTs, _ := time.Parse(recurly.DateTimeFormat, "2018-02-13T00:56:22Z")
+	updatedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-02-14T00:56:22Z")
+	closedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-02-14T00:56:22Z")
+
+	xmlFile := MustOpenFile("testdata/invoices/closed_credit_invoice_notification.xml")
+	result, err := Parse(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*CreditInvoiceNotificationClosed); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if !reflect.DeepEqual(n, &CreditInvoiceNotificationClosed{
+		Account: Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		Invoice: CreditInvoice{
+			XMLName:           xml.Name{Local: "invoice"},
+			UUID:              "42fb74de65e9395eb004614144a7b91f",
+			State:             "closed",
+			Origin:            "write_off",
+			SubscriptionUUIDs: []string{"42fb74ba9efe4c6981c2064436a4e9cd"},
+			InvoiceNumber:     2404,
+			TotalInCents:      -4882,
+			Currency:          "USD",
+			CreatedAt:         recurly.NewTime(createdTs),
+			UpdatedAt:         recurly.NewTime(updatedTs),
+			ClosedAt:          recurly.NewTime(closedTs),
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
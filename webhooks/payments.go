@@ -1,11 +1,15 @@
 package webhooks
 
+import "encoding/xml"
+
 const (
 	// Payment notifications.
 	PaymentNotificationSuccessfulXMLName       = "successful_payment_notification"
 	PaymentNotificationFailedXMLName           = "failed_payment_notification"
 	PaymentNotificationVoidXMLName             = "void_payment_notification"
 	PaymentNotificationSuccessfulRefundXMLName = "successful_refund_notification"
+	PaymentNotificationScheduledXMLName        = "scheduled_payment_notification"
+	PaymentNotificationFraudInfoUpdatedXMLName = "fraud_info_updated_notification"
 )
 
 // Payment types.
@@ -37,4 +41,98 @@ type (
 		Account     Account     `xml:"account"`
 		Transaction Transaction `xml:"transaction"`
 	}
+
+	// PaymentNotificationScheduled is sent when a payment is scheduled, for
+	// example an ACH payment that has been submitted but not yet settled.
+	// https://dev.recurly.com/page/webhooks#section-scheduled-payment
+	PaymentNotificationScheduled struct {
+		Account     Account     `xml:"account"`
+		Transaction Transaction `xml:"transaction"`
+	}
+
+	// PaymentNotificationFraudInfoUpdated is sent when the fraud details of a
+	// transaction are updated, for example after a manual review.
+	// https://dev.recurly.com/page/webhooks#section-fraud-info-updated
+	PaymentNotificationFraudInfoUpdated struct {
+		Account     Account     `xml:"account"`
+		Transaction Transaction `xml:"transaction"`
+	}
 )
+
+// NewPaymentNotificationSuccessful returns a new PaymentNotificationSuccessful.
+func NewPaymentNotificationSuccessful(account Account, transaction Transaction) *PaymentNotificationSuccessful {
+	return &PaymentNotificationSuccessful{
+		Account:     account,
+		Transaction: transaction,
+	}
+}
+
+// MarshalXML marshals the notification as <successful_payment_notification>.
+func (n PaymentNotificationSuccessful) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, PaymentNotificationSuccessfulXMLName, n)
+}
+
+// NewPaymentNotificationFailed returns a new PaymentNotificationFailed.
+func NewPaymentNotificationFailed(account Account, transaction Transaction) *PaymentNotificationFailed {
+	return &PaymentNotificationFailed{
+		Account:     account,
+		Transaction: transaction,
+	}
+}
+
+// MarshalXML marshals the notification as <failed_payment_notification>.
+func (n PaymentNotificationFailed) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, PaymentNotificationFailedXMLName, n)
+}
+
+// NewPaymentNotificationVoid returns a new PaymentNotificationVoid.
+func NewPaymentNotificationVoid(account Account, transaction Transaction) *PaymentNotificationVoid {
+	return &PaymentNotificationVoid{
+		Account:     account,
+		Transaction: transaction,
+	}
+}
+
+// MarshalXML marshals the notification as <void_payment_notification>.
+func (n PaymentNotificationVoid) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, PaymentNotificationVoidXMLName, n)
+}
+
+// NewPaymentNotificationSuccessfulRefund returns a new PaymentNotificationSuccessfulRefund.
+func NewPaymentNotificationSuccessfulRefund(account Account, transaction Transaction) *PaymentNotificationSuccessfulRefund {
+	return &PaymentNotificationSuccessfulRefund{
+		Account:     account,
+		Transaction: transaction,
+	}
+}
+
+// MarshalXML marshals the notification as <successful_refund_notification>.
+func (n PaymentNotificationSuccessfulRefund) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, PaymentNotificationSuccessfulRefundXMLName, n)
+}
+
+// NewPaymentNotificationScheduled returns a new PaymentNotificationScheduled.
+func NewPaymentNotificationScheduled(account Account, transaction Transaction) *PaymentNotificationScheduled {
+	return &PaymentNotificationScheduled{
+		Account:     account,
+		Transaction: transaction,
+	}
+}
+
+// MarshalXML marshals the notification as <scheduled_payment_notification>.
+func (n PaymentNotificationScheduled) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, PaymentNotificationScheduledXMLName, n)
+}
+
+// NewPaymentNotificationFraudInfoUpdated returns a new PaymentNotificationFraudInfoUpdated.
+func NewPaymentNotificationFraudInfoUpdated(account Account, transaction Transaction) *PaymentNotificationFraudInfoUpdated {
+	return &PaymentNotificationFraudInfoUpdated{
+		Account:     account,
+		Transaction: transaction,
+	}
+}
+
+// MarshalXML marshals the notification as <fraud_info_updated_notification>.
+func (n PaymentNotificationFraudInfoUpdated) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, PaymentNotificationFraudInfoUpdatedXMLName, n)
+}
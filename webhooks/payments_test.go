@@ -151,3 +151,75 @@ func TestParse_PaymentNotificationSuccessfulRefund(t *testing.T) {
 		t.Fatalf("unexpected notification: %#v", n)
 	}
 }
+
+func TestParse_PaymentNotificationScheduled(t *testing.T) {
+	xmlFile := MustOpenFile("testdata/payments/scheduled_payment_notification.xml")
+	result, err := Parse(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*PaymentNotificationScheduled); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if !reflect.DeepEqual(n, &PaymentNotificationScheduled{
+		Account: Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		Transaction: Transaction{
+			XMLName:          xml.Name{Local: "transaction"},
+			UUID:             "4997ace0f57ea6a0fd2f0d4f5d7baf1b",
+			InvoiceNumber:    1016,
+			SubscriptionUUID: "4997ace0f0e7c3b4e2b87c4e4b2ee1c4",
+			Action:           "purchase",
+			AmountInCents:    2000,
+			Status:           "scheduled",
+			Message:          "Transaction scheduled",
+			Source:           "subscription",
+			Test:             recurly.NewBool(true),
+			Voidable:         recurly.NewBool(true),
+			Refundable:       recurly.NewBool(false),
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestParse_PaymentNotificationFraudInfoUpdated(t *testing.T) {
+	xmlFile := MustOpenFile("testdata/payments/fraud_info_updated_notification.xml")
+	result, err := Parse(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*PaymentNotificationFraudInfoUpdated); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if !reflect.DeepEqual(n, &PaymentNotificationFraudInfoUpdated{
+		Account: Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		Transaction: Transaction{
+			XMLName:          xml.Name{Local: "transaction"},
+			UUID:             "4997ace0f57ea6a0fd2f0d4f5d7baf1b",
+			InvoiceNumber:    1016,
+			SubscriptionUUID: "4997ace0f0e7c3b4e2b87c4e4b2ee1c4",
+			Action:           "purchase",
+			AmountInCents:    2000,
+			Status:           "success",
+			Message:          "Successful test transaction",
+			Source:           "subscription",
+			Test:             recurly.NewBool(true),
+			Voidable:         recurly.NewBool(true),
+			Refundable:       recurly.NewBool(false),
+			FraudInfo: &FraudInfo{
+				Score:    recurly.NewInt(88),
+				Decision: "approve",
+			},
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
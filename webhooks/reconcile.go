@@ -0,0 +1,258 @@
+package webhooks
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+// Reconciler synthesizes the notifications Recurly would have sent during a
+// time window from the API, so events missed while a webhook endpoint was
+// unavailable can be processed by the same handler.
+//
+// The following notifications are synthesized:
+//   - SubscriptionNotificationNew, SubscriptionNotificationRenewed and
+//     SubscriptionNotificationExpired
+//   - ChargeInvoiceNotificationClosed for paid and failed charge invoices,
+//     and InvoiceNotificationClosed for legacy invoices
+//   - PaymentNotificationSuccessful, PaymentNotificationFailed and
+//     PaymentNotificationSuccessfulRefund
+//
+// Synthesized notifications hold the fields available from the API, which
+// may differ from those Recurly sends. Their Identity may therefore differ
+// from the live notification for the same event.
+//
+// Subscription notifications are derived from each subscription's current
+// state, so only its latest renewal is seen: a subscription renewed several
+// times during the window yields a single SubscriptionNotificationRenewed,
+// and none if it renewed again after the window. Payments and invoices are
+// derived from each transaction and invoice, so none are collapsed.
+type Reconciler struct {
+	client  *recurly.Client
+	handler HandlerFunc
+
+	// PerPage is the page size used when listing resources. Defaults to 200.
+	PerPage int
+}
+
+// NewReconciler returns a Reconciler that passes synthesized notifications
+// to handler.
+func NewReconciler(client *recurly.Client, handler HandlerFunc) *Reconciler {
+	return &Reconciler{client: client, handler: handler}
+}
+
+// event is a synthesized notification and when it occurred.
+type event struct {
+	at           time.Time
+	notification interface{}
+}
+
+// Reconcile synthesizes the notifications for events between begin and
+// end and passes them to the handler in the order they occurred. It stops
+// at the first error and returns the number of notifications handled.
+// Wrapping the handler with a Deduplicator makes it safe to run again
+// after an error.
+func (r *Reconciler) Reconcile(begin, end time.Time) (int, error) {
+	var events []event
+
+	if err := r.list(begin, func(params recurly.Params) (*recurly.Response, error) {
+		resp, subs, err := r.client.Subscriptions.List(params)
+		for _, s := range subs {
+			events = append(events, subscriptionEvents(s, begin, end)...)
+		}
+		return resp, err
+	}); err != nil {
+		return 0, err
+	}
+
+	if err := r.list(begin, func(params recurly.Params) (*recurly.Response, error) {
+		resp, invoices, err := r.client.Invoices.List(params)
+		for _, i := range invoices {
+			events = append(events, invoiceEvents(i, begin, end)...)
+		}
+		return resp, err
+	}); err != nil {
+		return 0, err
+	}
+
+	if err := r.list(begin, func(params recurly.Params) (*recurly.Response, error) {
+		resp, transactions, err := r.client.Transactions.List(params)
+		for _, t := range transactions {
+			events = append(events, transactionEvents(t, begin, end)...)
+		}
+		return resp, err
+	}); err != nil {
+		return 0, err
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].at.Before(events[j].at)
+	})
+
+	for i, e := range events {
+		if err := r.handler(e.notification); err != nil {
+			return i, err
+		}
+	}
+	return len(events), nil
+}
+
+// list calls fn for each page of resources updated since begin. Resources
+// updated again after end are included, as an event in the window may have
+// been followed by another change; callers filter events by the window.
+func (r *Reconciler) list(begin time.Time, fn func(recurly.Params) (*recurly.Response, error)) error {
+	perPage := r.PerPage
+	if perPage <= 0 {
+		perPage = 200
+	}
+
+	params := recurly.Params{
+		"begin_time": begin.UTC().Format(time.RFC3339),
+		"sort":       "updated_at",
+		"order":      "asc",
+		"per_page":   perPage,
+	}
+	for {
+		resp, err := fn(params)
+		if err != nil {
+			return err
+		} else if resp.IsError() {
+			return ErrReconcile{Response: resp}
+		}
+
+		next := resp.Next()
+		if next == "" {
+			return nil
+		}
+		params["cursor"] = next
+	}
+}
+
+// ErrReconcile is returned when listing resources fails. It implements the
+// error interface.
+type ErrReconcile struct {
+	Response *recurly.Response
+}
+
+// Error implements the error interface.
+func (e ErrReconcile) Error() string {
+	return fmt.Sprintf("webhooks: reconcile: %s %s: %d", e.Response.Request.Method, e.Response.Request.URL.Path, e.Response.StatusCode)
+}
+
+// within returns the time of t and whether it is between begin and end.
+func within(t recurly.NullTime, begin, end time.Time) (time.Time, bool) {
+	if t.Time == nil || t.Time.Before(begin) || t.Time.After(end) {
+		return time.Time{}, false
+	}
+	return *t.Time, true
+}
+
+func subscriptionEvents(s recurly.Subscription, begin, end time.Time) []event {
+	account := Account{Code: s.AccountCode}
+
+	var events []event
+	if at, ok := within(s.ActivatedAt, begin, end); ok {
+		events = append(events, event{at, NewSubscriptionNotificationNew(account, s)})
+	}
+	if at, ok := within(s.CurrentPeriodStartedAt, begin, end); ok && s.ActivatedAt.Time != nil && at.After(*s.ActivatedAt.Time) {
+		events = append(events, event{at, NewSubscriptionNotificationRenewed(account, s)})
+	}
+	if at, ok := within(s.ExpiresAt, begin, end); ok && s.State == recurly.SubscriptionStateExpired {
+		events = append(events, event{at, NewSubscriptionNotificationExpired(account, s)})
+	}
+	return events
+}
+
+func invoiceEvents(i recurly.Invoice, begin, end time.Time) []event {
+	at, ok := within(i.ClosedAt, begin, end)
+	if !ok {
+		return nil
+	}
+
+	account := Account{Code: i.AccountCode}
+	switch {
+	case i.Type == recurly.InvoiceTypeCharge && (i.State == recurly.ChargeInvoiceStatePaid || i.State == recurly.ChargeInvoiceStateFailed):
+		return []event{{at, NewChargeInvoiceNotificationClosed(account, ChargeInvoice{
+			UUID:                i.UUID,
+			State:               i.State,
+			Origin:              i.Origin,
+			InvoiceNumberPrefix: i.InvoiceNumberPrefix,
+			InvoiceNumber:       i.InvoiceNumber,
+			PONumber:            i.PONumber,
+			VATNumber:           i.VATNumber,
+			BalanceInCents:      i.BalanceInCents,
+			TotalInCents:        i.TotalInCents,
+			Currency:            i.Currency,
+			CreatedAt:           i.CreatedAt,
+			UpdatedAt:           i.UpdatedAt,
+			DueOn:               i.DueOn,
+			ClosedAt:            i.ClosedAt,
+			NetTerms:            i.NetTerms,
+			CollectionMethod:    i.CollectionMethod,
+			CustomerNotes:       i.CustomerNotes,
+			TermsAndConditions:  i.TermsAndConditions,
+		})}}
+	case i.Type == "" || i.Type == recurly.InvoiceTypeLegacy:
+		return []event{{at, NewInvoiceNotificationClosed(account, Invoice{
+			UUID:                i.UUID,
+			State:               i.State,
+			InvoiceNumberPrefix: i.InvoiceNumberPrefix,
+			InvoiceNumber:       i.InvoiceNumber,
+			PONumber:            i.PONumber,
+			VATNumber:           i.VATNumber,
+			TotalInCents:        i.TotalInCents,
+			Currency:            i.Currency,
+			CreatedAt:           i.CreatedAt,
+			ClosedAt:            i.ClosedAt,
+			NetTerms:            i.NetTerms,
+			CollectionMethod:    i.CollectionMethod,
+		})}}
+	}
+	return nil
+}
+
+func transactionEvents(t recurly.Transaction, begin, end time.Time) []event {
+	at, ok := within(t.CreatedAt, begin, end)
+	if !ok {
+		return nil
+	}
+
+	account := Account{
+		Code:        t.Account.Code,
+		Username:    t.Account.Username,
+		Email:       t.Account.Email,
+		FirstName:   t.Account.FirstName,
+		LastName:    t.Account.LastName,
+		CompanyName: t.Account.CompanyName,
+	}
+	transaction := Transaction{
+		UUID:             t.UUID,
+		InvoiceNumber:    t.InvoiceNumber,
+		SubscriptionUUID: t.SubscriptionUUID,
+		Action:           t.Action,
+		AmountInCents:    t.AmountInCents,
+		Status:           t.Status,
+		Reference:        t.Reference,
+		Source:           t.Source,
+		Test:             recurly.NewBool(t.Test),
+		Voidable:         t.Voidable,
+		Refundable:       t.Refundable,
+	}
+	if t.TransactionError != nil {
+		transaction.Message = t.TransactionError.MerchantMessage
+		transaction.FailureType = t.TransactionError.ErrorCode
+		transaction.GatewayErrorCodes = t.TransactionError.GatewayErrorCode
+	}
+
+	switch {
+	case t.Action == "purchase" && t.Status == recurly.TransactionStatusSuccess:
+		return []event{{at, NewPaymentNotificationSuccessful(account, transaction)}}
+	case t.Action == "purchase" && (t.Status == recurly.TransactionStatusFailed || t.Status == "declined"):
+		return []event{{at, NewPaymentNotificationFailed(account, transaction)}}
+	case t.Action == "refund" && t.Status == recurly.TransactionStatusSuccess:
+		return []event{{at, NewPaymentNotificationSuccessfulRefund(account, transaction)}}
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+package webhooks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kmikiy/recurly"
+)
+
+func TestReconciler_Reconcile(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	checkParams := func(r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("begin_time") != "2018-03-01T00:00:00Z" || q.Get("end_time") != "" {
+			t.Fatalf("unexpected window: %s", r.URL.RawQuery)
+		} else if q.Get("sort") != "updated_at" || q.Get("order") != "asc" || q.Get("per_page") != "200" {
+			t.Fatalf("unexpected params: %s", r.URL.RawQuery)
+		}
+	}
+
+	var subscriptionPages int
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		checkParams(r)
+		subscriptionPages++
+		if r.URL.Query().Get("cursor") == "" {
+			w.Header().Set("Link", `<https://your-subdomain.recurly.com/v2/subscriptions?cursor=1520000000>; rel="next"`)
+			w.WriteHeader(200)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+				<subscriptions type="array">
+					<subscription>
+						<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+						<uuid>a</uuid>
+						<state>active</state>
+						<activated_at type="datetime">2018-03-01T10:00:00Z</activated_at>
+						<current_period_started_at type="datetime">2018-03-01T10:00:00Z</current_period_started_at>
+					</subscription>
+				</subscriptions>`)
+			return
+		} else if r.URL.Query().Get("cursor") != "1520000000" {
+			t.Fatalf("unexpected cursor: %s", r.URL.Query().Get("cursor"))
+		}
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<subscriptions type="array">
+				<subscription>
+					<account href="https://your-subdomain.recurly.com/v2/accounts/2"/>
+					<uuid>b</uuid>
+					<state>active</state>
+					<activated_at type="datetime">2017-03-01T08:00:00Z</activated_at>
+					<current_period_started_at type="datetime">2018-03-01T08:00:00Z</current_period_started_at>
+				</subscription>
+				<subscription>
+					<account href="https://your-subdomain.recurly.com/v2/accounts/3"/>
+					<uuid>c</uuid>
+					<state>expired</state>
+					<activated_at type="datetime">2017-03-01T08:00:00Z</activated_at>
+					<current_period_started_at type="datetime">2018-02-01T08:00:00Z</current_period_started_at>
+					<expires_at type="datetime">2018-03-01T12:00:00Z</expires_at>
+				</subscription>
+			</subscriptions>`)
+	})
+	mux.HandleFunc("/v2/invoices", func(w http.ResponseWriter, r *http.Request) {
+		checkParams(r)
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<invoices type="array">
+				<invoice>
+					<account href="https://your-subdomain.recurly.com/v2/accounts/1"/>
+					<uuid>d</uuid>
+					<state>paid</state>
+					<invoice_number type="integer">1001</invoice_number>
+					<type>charge</type>
+					<closed_at type="datetime">2018-03-01T10:00:01Z</closed_at>
+				</invoice>
+				<invoice>
+					<account href="https://your-subdomain.recurly.com/v2/accounts/2"/>
+					<uuid>e</uuid>
+					<state>pending</state>
+					<invoice_number type="integer">1002</invoice_number>
+					<type>charge</type>
+				</invoice>
+				<invoice>
+					<account href="https://your-subdomain.recurly.com/v2/accounts/4"/>
+					<uuid>f</uuid>
+					<state>collected</state>
+					<invoice_number type="integer">1003</invoice_number>
+					<type>legacy</type>
+					<closed_at type="datetime">2018-03-01T09:00:00Z</closed_at>
+				</invoice>
+				<invoice>
+					<account href="https://your-subdomain.recurly.com/v2/accounts/6"/>
+					<uuid>k</uuid>
+					<state>failed</state>
+					<invoice_number type="integer">1004</invoice_number>
+					<type>charge</type>
+					<updated_at type="datetime">2018-03-05T00:00:00Z</updated_at>
+					<closed_at type="datetime">2018-03-01T11:00:00Z</closed_at>
+				</invoice>
+			</invoices>`)
+	})
+	mux.HandleFunc("/v2/transactions", func(w http.ResponseWriter, r *http.Request) {
+		checkParams(r)
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<transactions type="array">
+				<transaction>
+					<uuid>g</uuid>
+					<action>purchase</action>
+					<amount_in_cents type="integer">1000</amount_in_cents>
+					<status>success</status>
+					<test type="boolean">true</test>
+					<created_at type="datetime">2018-03-01T10:00:02Z</created_at>
+					<details><account><account_code>1</account_code></account></details>
+				</transaction>
+				<transaction>
+					<uuid>h</uuid>
+					<action>purchase</action>
+					<amount_in_cents type="integer">500</amount_in_cents>
+					<status>declined</status>
+					<test type="boolean">false</test>
+					<transaction_error>
+						<error_code>insufficient_funds</error_code>
+						<merchant_message>The card has insufficient funds.</merchant_message>
+						<gateway_error_code>51</gateway_error_code>
+					</transaction_error>
+					<created_at type="datetime">2018-03-01T07:00:00Z</created_at>
+					<details><account><account_code>5</account_code></account></details>
+				</transaction>
+				<transaction>
+					<uuid>i</uuid>
+					<action>verify</action>
+					<status>success</status>
+					<created_at type="datetime">2018-03-01T07:30:00Z</created_at>
+				</transaction>
+				<transaction>
+					<uuid>j</uuid>
+					<action>refund</action>
+					<status>success</status>
+					<created_at type="datetime">2018-03-03T00:00:00Z</created_at>
+				</transaction>
+			</transactions>`)
+	})
+
+	client := recurly.NewClient("test", "abc", nil, recurly.WithBaseURL(server.URL))
+
+	var types []string
+	var handled []interface{}
+	n, err := NewReconciler(client, func(n interface{}) error {
+		types = append(types, reflect.TypeOf(n).Elem().Name())
+		handled = append(handled, n)
+		return nil
+	}).Reconcile(
+		time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, 3, 2, 0, 0, 0, 0, time.UTC),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if subscriptionPages != 2 {
+		t.Fatalf("unexpected subscription pages: %d", subscriptionPages)
+	} else if n != 8 || len(handled) != 8 {
+		t.Fatalf("unexpected number of notifications: %d", n)
+	} else if !reflect.DeepEqual(types, []string{
+		"PaymentNotificationFailed",
+		"SubscriptionNotificationRenewed",
+		"InvoiceNotificationClosed",
+		"SubscriptionNotificationNew",
+		"ChargeInvoiceNotificationClosed",
+		"PaymentNotificationSuccessful",
+		"ChargeInvoiceNotificationClosed",
+		"SubscriptionNotificationExpired",
+	}) {
+		t.Fatalf("unexpected notifications: %v", types)
+	}
+
+	if n := handled[0].(*PaymentNotificationFailed); n.Account.Code != "5" || !reflect.DeepEqual(n.Transaction, Transaction{
+		UUID:              "h",
+		Action:            "purchase",
+		AmountInCents:     500,
+		Status:            "declined",
+		Message:           "The card has insufficient funds.",
+		FailureType:       "insufficient_funds",
+		GatewayErrorCodes: "51",
+		Test:              recurly.NewBool(false),
+	}) {
+		t.Fatalf("unexpected failed payment: %#v", n)
+	} else if n := handled[1].(*SubscriptionNotificationRenewed); n.Account.Code != "2" || n.Subscription.UUID != "b" {
+		t.Fatalf("unexpected renewal: %#v", n)
+	} else if n := handled[2].(*InvoiceNotificationClosed); n.Account.Code != "4" || n.Invoice.InvoiceNumber != 1003 || n.Invoice.State != "collected" {
+		t.Fatalf("unexpected invoice: %#v", n)
+	} else if n := handled[4].(*ChargeInvoiceNotificationClosed); n.Account.Code != "1" || n.Invoice.UUID != "d" || n.Invoice.State != "paid" {
+		t.Fatalf("unexpected charge invoice: %#v", n)
+	} else if n := handled[5].(*PaymentNotificationSuccessful); n.Account.Code != "1" || n.Transaction.UUID != "g" || n.Transaction.Test != recurly.NewBool(true) {
+		t.Fatalf("unexpected payment: %#v", n)
+	} else if n := handled[6].(*ChargeInvoiceNotificationClosed); n.Account.Code != "6" || n.Invoice.UUID != "k" || n.Invoice.State != "failed" {
+		t.Fatalf("unexpected charge invoice updated after the window: %#v", n)
+	} else if n := handled[7].(*SubscriptionNotificationExpired); n.Account.Code != "3" || n.Subscription.UUID != "c" {
+		t.Fatalf("unexpected expiration: %#v", n)
+	}
+}
+
+func TestReconciler_Reconcile_HandlerError(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+			<subscriptions type="array">
+				<subscription>
+					<uuid>a</uuid>
+					<activated_at type="datetime">2018-03-01T10:00:00Z</activated_at>
+				</subscription>
+				<subscription>
+					<uuid>b</uuid>
+					<activated_at type="datetime">2018-03-01T11:00:00Z</activated_at>
+				</subscription>
+			</subscriptions>`)
+	})
+	mux.HandleFunc("/v2/invoices", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><invoices type="array"></invoices>`)
+	})
+	mux.HandleFunc("/v2/transactions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><transactions type="array"></transactions>`)
+	})
+
+	client := recurly.NewClient("test", "abc", nil, recurly.WithBaseURL(server.URL))
+	errFailed := errors.New("failed")
+
+	var calls int
+	n, err := NewReconciler(client, func(n interface{}) error {
+		calls++
+		if n.(*SubscriptionNotificationNew).Subscription.UUID == "b" {
+			return errFailed
+		}
+		return nil
+	}).Reconcile(
+		time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, 3, 2, 0, 0, 0, 0, time.UTC),
+	)
+	if err != errFailed {
+		t.Fatalf("unexpected error: %v", err)
+	} else if n != 1 || calls != 2 {
+		t.Fatalf("unexpected count: %d, calls: %d", n, calls)
+	}
+}
+
+func TestReconciler_Reconcile_APIError(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/v2/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	})
+
+	client := recurly.NewClient("test", "abc", nil, recurly.WithBaseURL(server.URL))
+
+	var called bool
+	n, err := NewReconciler(client, func(n interface{}) error {
+		called = true
+		return nil
+	}).Reconcile(time.Now().Add(-time.Hour), time.Now())
+	if e, ok := err.(ErrReconcile); !ok || e.Response.StatusCode != 500 {
+		t.Fatalf("unexpected error: %v", err)
+	} else if e.Error() != "webhooks: reconcile: GET /v2/subscriptions: 500" {
+		t.Fatalf("unexpected error message: %s", e.Error())
+	} else if n != 0 || called {
+		t.Fatal("expected no notifications to be handled")
+	}
+}
@@ -0,0 +1,112 @@
+package webhooks
+
+import (
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+// Factory returns a pointer to a new, empty notification that an incoming
+// webhook is unmarshaled into.
+type Factory func() interface{}
+
+// GenericNotification is returned for notifications that are not registered
+// when the registry has a fallback enabled. It keeps the raw XML so the
+// notification can be decoded by the caller.
+type GenericNotification struct {
+	XMLName xml.Name
+
+	// Account is the account the notification is for, if it has one.
+	Account *Account `xml:"account,omitempty"`
+
+	// Raw is the notification as it was received.
+	Raw []byte `xml:"-"`
+}
+
+// Name returns the XML root name of the notification.
+func (n GenericNotification) Name() string {
+	return n.XMLName.Local
+}
+
+// Registry maps notification names to the types they are parsed into.
+// It is safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+	fallback  bool
+}
+
+// DefaultRegistry is used by Parse and Register. It includes every
+// notification supported by this package.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry returns a registry with every notification supported by this
+// package registered.
+func NewRegistry() *Registry {
+	r := &Registry{factories: make(map[string]Factory, len(builtinNotifications))}
+	for name, f := range builtinNotifications {
+		r.factories[name] = f
+	}
+	return r
+}
+
+// Register registers a notification type on DefaultRegistry.
+func Register(name string, f Factory) {
+	DefaultRegistry.Register(name, f)
+}
+
+// Register registers f for notifications with the XML root name, replacing
+// any existing registration, including built-in ones. f must return a
+// pointer that can be passed to xml.Unmarshal.
+func (r *Registry) Register(name string, f Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[name] = f
+}
+
+// SetFallback controls how unregistered notifications are parsed. If
+// enabled, Parse returns a *GenericNotification; otherwise it returns
+// ErrUnknownNotification. The fallback is disabled by default.
+func (r *Registry) SetFallback(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = enabled
+}
+
+// Parse parses an incoming webhook and returns the notification.
+func (r *Registry) Parse(rd io.Reader) (interface{}, error) {
+	if closer, ok := rd.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	notification, err := ioutil.ReadAll(rd)
+	if err != nil {
+		return nil, err
+	}
+
+	var n notificationName
+	if err := xml.Unmarshal(notification, &n); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	f, ok := r.factories[n.XMLName.Local]
+	fallback := r.fallback
+	r.mu.RUnlock()
+
+	var dst interface{}
+	if ok {
+		dst = f()
+	} else if fallback {
+		dst = &GenericNotification{Raw: notification}
+	} else {
+		return nil, ErrUnknownNotification{name: n.XMLName.Local}
+	}
+
+	if err := xml.Unmarshal(notification, dst); err != nil {
+		return nil, err
+	}
+
+	return dst, nil
+}
@@ -0,0 +1,119 @@
+package webhooks
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+type dunningCampaignNotification struct {
+	Account         Account `xml:"account"`
+	DunningCampaign struct {
+		Code string `xml:"code"`
+		Name string `xml:"name"`
+	} `xml:"dunning_campaign"`
+}
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry()
+	r.Register("new_dunning_campaign_notification", func() interface{} { return &dunningCampaignNotification{} })
+
+	result, err := r.Parse(MustOpenFile("testdata/registry/new_dunning_campaign_notification.xml"))
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*dunningCampaignNotification); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if n.Account.Code != "1" || n.DunningCampaign.Code != "default" || n.DunningCampaign.Name != "Default campaign" {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+
+	// Registering on one registry does not affect others.
+	if _, err := NewRegistry().Parse(MustOpenFile("testdata/registry/new_dunning_campaign_notification.xml")); err == nil {
+		t.Fatal("expected error. None given.")
+	} else if _, ok := err.(ErrUnknownNotification); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Built-in notifications are still parsed.
+	if result, err := r.Parse(MustOpenFile("testdata/invoices/new_invoice_notification.xml")); err != nil {
+		t.Fatal(err)
+	} else if _, ok := result.(*InvoiceNotificationNew); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	}
+}
+
+type customInvoiceNotification struct {
+	XMLName xml.Name `xml:"new_invoice_notification"`
+	Invoice struct {
+		UUID string `xml:"uuid"`
+	} `xml:"invoice"`
+}
+
+func TestRegistry_Override(t *testing.T) {
+	r := NewRegistry()
+	r.Register(InvoiceNotificationNewXMLName, func() interface{} { return &customInvoiceNotification{} })
+
+	result, err := r.Parse(MustOpenFile("testdata/invoices/new_invoice_notification.xml"))
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*customInvoiceNotification); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if n.Invoice.UUID != "ffc64d71d4b5404e93f13aac9c63b007" {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestRegistry_Fallback(t *testing.T) {
+	raw, err := ioutil.ReadFile("testdata/registry/new_dunning_campaign_notification.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRegistry()
+	r.SetFallback(true)
+	result, err := r.Parse(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*GenericNotification); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if n.Name() != "new_dunning_campaign_notification" {
+		t.Fatalf("unexpected name: %s", n.Name())
+	} else if !bytes.Equal(n.Raw, raw) {
+		t.Fatalf("unexpected raw xml: %s", n.Raw)
+	} else if !reflect.DeepEqual(n.Account, &Account{
+		XMLName:   xml.Name{Local: "account"},
+		Code:      "1",
+		Email:     "verena@example.com",
+		FirstName: "Verena",
+		LastName:  "Example",
+	}) {
+		t.Fatalf("unexpected account: %#v", n.Account)
+	}
+
+	// Notifications without an account have a nil Account.
+	if result, err := r.Parse(MustOpenFile("testdata/unknown_notification.xml")); err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*GenericNotification); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if n.Name() != "unknown_notification" || n.Account != nil {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+
+	r.SetFallback(false)
+	if _, err := r.Parse(bytes.NewReader(raw)); err == nil {
+		t.Fatal("expected error. None given.")
+	}
+}
+
+func TestRegister_DefaultRegistry(t *testing.T) {
+	defer func() { DefaultRegistry = NewRegistry() }()
+
+	Register("new_dunning_campaign_notification", func() interface{} { return &dunningCampaignNotification{} })
+	if result, err := Parse(MustOpenFile("testdata/registry/new_dunning_campaign_notification.xml")); err != nil {
+		t.Fatal(err)
+	} else if _, ok := result.(*dunningCampaignNotification); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	}
+}
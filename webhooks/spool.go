@@ -0,0 +1,492 @@
+package webhooks
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Names of the files and directories in a spool directory.
+const (
+	spoolLogName  = "spool.log"
+	spoolDoneName = "done.log"
+	spoolSeqName  = "seq"
+	spoolDeadName = "dead"
+)
+
+// ErrSpoolClosed is returned by Enqueue after the spool is closed.
+var ErrSpoolClosed = errors.New("webhooks: spool is closed")
+
+// SpoolOptions configures a Spool.
+type SpoolOptions struct {
+	// Registry parses spooled webhooks. Defaults to DefaultRegistry.
+	Registry *Registry
+
+	// Workers is the number of webhooks processed concurrently. Defaults to 4.
+	Workers int
+
+	// MaxAttempts is the number of times a webhook is parsed and handled
+	// before it is moved to the dead letter directory. Defaults to 5.
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry. It doubles after each
+	// attempt. Defaults to one second.
+	Backoff time.Duration
+}
+
+// Spool accepts webhooks and processes them in the background, so Recurly
+// receives a response as soon as the webhook is stored.
+//
+// Webhooks are appended to spool.log in the spool directory and synced to
+// disk before they are acknowledged. Once a webhook is processed its ID is
+// appended to done.log. The highest ID assigned is kept in seq, so IDs
+// keep increasing after the logs are compacted. Webhooks that still fail to
+// parse or be handled after MaxAttempts are written to the dead directory,
+// as <id>.xml along with the last error in <id>.err, and are not retried.
+// Existing dead letters are never overwritten: if <id>.xml exists, for
+// example because the spool's logs were removed, <id>-1.xml is used, and
+// so on.
+//
+// Webhooks that were not processed when the spool was closed, or when the
+// process stopped, are processed when it is opened again. Webhooks are
+// processed concurrently, so handlers must not depend on delivery order,
+// and a webhook may be handled again if the process stops after handling
+// it but before recording it as done. Wrapping the handler with a
+// Deduplicator guards against both.
+type Spool struct {
+	dir     string
+	handler HandlerFunc
+	opts    SpoolOptions
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	log     spoolFile
+	done    *os.File
+	nextID  int64
+	pending []spoolRecord
+	closed  bool
+
+	stop    chan struct{}
+	workers sync.WaitGroup
+}
+
+// spoolFile is the spool's log file. It is replaced in tests.
+type spoolFile interface {
+	io.Writer
+	Stat() (os.FileInfo, error)
+	Truncate(size int64) error
+	Sync() error
+	Close() error
+}
+
+// spoolRecord is a webhook in the spool.
+type spoolRecord struct {
+	id      int64
+	payload []byte
+}
+
+// OpenSpool opens or creates the spool in dir and starts processing
+// webhooks with h, starting with those left unprocessed by a previous run.
+func OpenSpool(dir string, h HandlerFunc, opts SpoolOptions) (*Spool, error) {
+	if opts.Registry == nil {
+		opts.Registry = DefaultRegistry
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = time.Second
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, spoolDeadName), 0755); err != nil {
+		return nil, err
+	}
+
+	s := &Spool{dir: dir, handler: h, opts: opts, stop: make(chan struct{})}
+	s.cond = sync.NewCond(&s.mu)
+	if err := s.recover(); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < opts.Workers; i++ {
+		s.workers.Add(1)
+		go s.work()
+	}
+	return s, nil
+}
+
+// recover loads the webhooks that were not processed and compacts the spool
+// so it only holds those.
+func (s *Spool) recover() error {
+	records, maxID, err := readSpoolLog(filepath.Join(s.dir, spoolLogName))
+	if err != nil {
+		return err
+	}
+	done, maxDoneID, err := readSpoolDone(filepath.Join(s.dir, spoolDoneName))
+	if err != nil {
+		return err
+	}
+	seq, err := readSpoolSeq(filepath.Join(s.dir, spoolSeqName))
+	if err != nil {
+		return err
+	}
+	if maxDoneID > maxID {
+		maxID = maxDoneID
+	}
+	if seq > maxID {
+		maxID = seq
+	}
+	s.nextID = maxID + 1
+
+	// Record the highest ID before the logs are compacted, as they may both
+	// be empty afterwards.
+	if err := writeSpoolSeq(filepath.Join(s.dir, spoolSeqName), maxID); err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		if !done[r.id] {
+			s.pending = append(s.pending, r)
+		}
+	}
+
+	// Rewrite the log with only the pending webhooks. The done log is only
+	// cleared once the new log is in place, and IDs keep increasing, so a
+	// crash in between cannot mark a new webhook as done.
+	tmp := filepath.Join(s.dir, spoolLogName+".tmp")
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, r := range s.pending {
+		writeSpoolRecord(w, r)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	} else if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	} else if err := f.Close(); err != nil {
+		return err
+	} else if err := os.Rename(tmp, filepath.Join(s.dir, spoolLogName)); err != nil {
+		return err
+	}
+
+	if s.log, err = os.OpenFile(filepath.Join(s.dir, spoolLogName), os.O_WRONLY|os.O_APPEND, 0644); err != nil {
+		return err
+	}
+	if s.done, err = os.OpenFile(filepath.Join(s.dir, spoolDoneName), os.O_WRONLY|os.O_CREATE|os.O_TRUNC|os.O_APPEND, 0644); err != nil {
+		s.log.Close()
+		return err
+	}
+	return nil
+}
+
+// Enqueue stores a webhook for processing. The webhook is synced to disk
+// before Enqueue returns. If it cannot be, any part of it that was written
+// is removed so the log stays readable.
+func (s *Spool) Enqueue(payload []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrSpoolClosed
+	}
+
+	info, err := s.log.Stat()
+	if err != nil {
+		return err
+	}
+
+	r := spoolRecord{id: s.nextID, payload: payload}
+	w := bufio.NewWriter(s.log)
+	writeSpoolRecord(w, r)
+	if err = w.Flush(); err == nil {
+		err = s.log.Sync()
+	}
+	if err != nil {
+		// If the record cannot be removed it may be read back, so its ID
+		// must not be reused.
+		if s.log.Truncate(info.Size()) != nil {
+			s.nextID++
+		}
+		return err
+	}
+
+	s.nextID++
+	s.pending = append(s.pending, r)
+	s.cond.Signal()
+	return nil
+}
+
+// Handler returns an http.Handler that enqueues webhooks. It responds with
+// 200 once the webhook is stored, and 500 if it could not be stored so
+// Recurly retries.
+func (s *Spool) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		payload, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := s.Enqueue(payload); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// Close stops accepting webhooks and waits for the webhooks being processed
+// to finish. Retries that are waiting are abandoned; those webhooks, and any
+// that were not started, are processed when the spool is opened again.
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.stop)
+	s.cond.Broadcast()
+	s.mu.Unlock()
+
+	s.workers.Wait()
+
+	err := s.log.Close()
+	if e := s.done.Close(); err == nil {
+		err = e
+	}
+	return err
+}
+
+// work processes webhooks until the spool is closed.
+func (s *Spool) work() {
+	defer s.workers.Done()
+	for {
+		s.mu.Lock()
+		for len(s.pending) == 0 && !s.closed {
+			s.cond.Wait()
+		}
+		if s.closed {
+			s.mu.Unlock()
+			return
+		}
+		r := s.pending[0]
+		s.pending = s.pending[1:]
+		s.mu.Unlock()
+
+		if !s.process(r) {
+			return
+		}
+	}
+}
+
+// process parses and handles a webhook, retrying failures. It returns false
+// if the spool was closed while waiting to retry.
+func (s *Spool) process(r spoolRecord) bool {
+	backoff := s.opts.Backoff
+	var err error
+	for attempt := 1; attempt <= s.opts.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-s.stop:
+				return false
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		var notification interface{}
+		if notification, err = s.opts.Registry.Parse(bytes.NewReader(r.payload)); err == nil {
+			if err = s.handler(notification); err == nil {
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		if e := s.deadLetter(r, err); e != nil {
+			// Leave the webhook pending so it is retried on the next open.
+			return true
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, e := fmt.Fprintf(s.done, "%d\n", r.id); e == nil {
+		s.done.Sync()
+	}
+	return true
+}
+
+// deadLetter writes a webhook that kept failing, and its last error, to the
+// dead letter directory. Existing dead letters are not overwritten.
+func (s *Spool) deadLetter(r spoolRecord, err error) error {
+	base := filepath.Join(s.dir, spoolDeadName, strconv.FormatInt(r.id, 10))
+	for i := 0; ; i++ {
+		name := base
+		if i > 0 {
+			name = base + "-" + strconv.Itoa(i)
+		}
+
+		e := writeExclusive(name+".xml", r.payload)
+		if os.IsExist(e) {
+			continue
+		} else if e != nil {
+			return e
+		}
+		return writeExclusive(name+".err", []byte(err.Error()+"\n"))
+	}
+}
+
+// writeExclusive creates the file at path, failing if it exists, and writes
+// b to it.
+func writeExclusive(path string, b []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	} else if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readSpoolSeq returns the highest ID recorded in the seq file, or 0 if
+// there is none.
+func readSpoolSeq(path string) (int64, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("webhooks: %s: invalid sequence %q", path, b)
+	}
+	return id, nil
+}
+
+// writeSpoolSeq atomically replaces the seq file with id.
+func writeSpoolSeq(path string, id int64) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(f, "%d\n", id); err != nil {
+		f.Close()
+		return err
+	} else if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	} else if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// writeSpoolRecord writes a record as a header line holding its ID and
+// length, followed by the payload and a newline.
+func writeSpoolRecord(w *bufio.Writer, r spoolRecord) {
+	fmt.Fprintf(w, "%d %d\n", r.id, len(r.payload))
+	w.Write(r.payload)
+	w.WriteByte('\n')
+}
+
+// readSpoolLog returns the records in the spool log and the highest ID. A
+// record that was only partly written is ignored; it was never
+// acknowledged.
+func readSpoolLog(path string) ([]spoolRecord, int64, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	} else if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var records []spoolRecord
+	var maxID int64
+	rd := bufio.NewReader(f)
+	for {
+		header, err := rd.ReadString('\n')
+		if err == io.EOF {
+			return records, maxID, nil
+		} else if err != nil {
+			return nil, 0, err
+		}
+
+		var r spoolRecord
+		var n int
+		if _, err := fmt.Sscanf(header, "%d %d\n", &r.id, &n); err != nil || n < 0 {
+			return nil, 0, fmt.Errorf("webhooks: %s: invalid record header %q", path, header)
+		}
+
+		r.payload = make([]byte, n+1)
+		if _, err := io.ReadFull(rd, r.payload); err == io.EOF || err == io.ErrUnexpectedEOF {
+			return records, maxID, nil
+		} else if err != nil {
+			return nil, 0, err
+		}
+		r.payload = r.payload[:n]
+
+		records = append(records, r)
+		if r.id > maxID {
+			maxID = r.id
+		}
+	}
+}
+
+// readSpoolDone returns the IDs in the done log and the highest ID.
+func readSpoolDone(path string) (map[int64]bool, int64, error) {
+	done := map[int64]bool{}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return done, 0, nil
+	} else if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var maxID int64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		id, err := strconv.ParseInt(strings.TrimSpace(scanner.Text()), 10, 64)
+		if err != nil {
+			// A partly written line; the webhook is processed again.
+			continue
+		}
+		done[id] = true
+		if id > maxID {
+			maxID = id
+		}
+	}
+	return done, maxID, scanner.Err()
+}
@@ -0,0 +1,401 @@
+package webhooks
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSpool(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	body, err := ioutil.ReadFile("testdata/subscriptions/new_subscription_notification.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handled := make(chan interface{}, 1)
+	s, err := OpenSpool(dir, func(n interface{}) error {
+		handled <- n
+		return nil
+	}, SpoolOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/xml", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status: %d", resp.StatusCode)
+	}
+
+	select {
+	case n := <-handled:
+		if _, ok := n.(*SubscriptionNotificationNew); !ok {
+			t.Fatalf("unexpected notification: %#v", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected notification to be handled")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := s.Enqueue(body); err != ErrSpoolClosed {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Processed webhooks are compacted away when the spool is reopened.
+	s, err = OpenSpool(dir, func(n interface{}) error { return nil }, SpoolOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "spool.log")); err != nil {
+		t.Fatal(err)
+	} else if len(b) != 0 {
+		t.Fatalf("unexpected spool: %q", b)
+	}
+}
+
+func TestSpool_Retry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var mu sync.Mutex
+	var attempts int
+	handled := make(chan struct{})
+	s, err := OpenSpool(dir, func(n interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts < 3 {
+			return errors.New("failed")
+		}
+		close(handled)
+		return nil
+	}, SpoolOptions{Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	body, err := ioutil.ReadFile("testdata/subscriptions/new_subscription_notification.xml")
+	if err != nil {
+		t.Fatal(err)
+	} else if err := s.Enqueue(body); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected notification to be handled")
+	}
+	if files, err := ioutil.ReadDir(filepath.Join(dir, "dead")); err != nil {
+		t.Fatal(err)
+	} else if len(files) != 0 {
+		t.Fatalf("unexpected dead letters: %v", files)
+	}
+}
+
+func TestSpool_DeadLetter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var mu sync.Mutex
+	var calls int
+	s, err := OpenSpool(dir, func(n interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return errors.New("handler failed")
+	}, SpoolOptions{MaxAttempts: 2, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	body, err := ioutil.ReadFile("testdata/subscriptions/new_subscription_notification.xml")
+	if err != nil {
+		t.Fatal(err)
+	} else if err := s.Enqueue(body); err != nil {
+		t.Fatal(err)
+	} else if err := s.Enqueue([]byte("<new_subscription_notification>")); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		files, _ := ioutil.ReadDir(filepath.Join(dir, "dead"))
+		if len(files) == 4 {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatalf("unexpected dead letters: %v", files)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "dead", "1.xml")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(b, body) {
+		t.Fatalf("unexpected dead letter: %s", b)
+	} else if b, err := ioutil.ReadFile(filepath.Join(dir, "dead", "1.err")); err != nil {
+		t.Fatal(err)
+	} else if string(b) != "handler failed\n" {
+		t.Fatalf("unexpected dead letter error: %q", b)
+	} else if b, err := ioutil.ReadFile(filepath.Join(dir, "dead", "2.err")); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(string(b), "EOF") {
+		t.Fatalf("unexpected dead letter error: %q", b)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 2 {
+		t.Fatalf("unexpected calls: %d", calls)
+	}
+}
+
+// waitDeadLetters waits until the dead letter directory holds n files.
+func waitDeadLetters(t *testing.T, dir string, n int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		files, _ := ioutil.ReadDir(filepath.Join(dir, "dead"))
+		if len(files) == n {
+			return
+		} else if time.Now().After(deadline) {
+			t.Fatalf("unexpected dead letters: %v", files)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSpool_DeadLetter_Compacted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	h := func(n interface{}) error { return errors.New("handler failed") }
+	opts := SpoolOptions{MaxAttempts: 1, Backoff: time.Millisecond}
+
+	s, err := OpenSpool(dir, h, opts)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := s.Enqueue([]byte("<a>")); err != nil {
+		t.Fatal(err)
+	}
+	waitDeadLetters(t, dir, 2)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening compacts both logs to empty files; IDs must not restart.
+	s, err = OpenSpool(dir, h, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Enqueue([]byte("<b>")); err != nil {
+		t.Fatal(err)
+	}
+	waitDeadLetters(t, dir, 4)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "dead", "1.xml")); err != nil {
+		t.Fatal(err)
+	} else if string(b) != "<a>" {
+		t.Fatalf("unexpected dead letter: %s", b)
+	} else if b, err := ioutil.ReadFile(filepath.Join(dir, "dead", "2.xml")); err != nil {
+		t.Fatal(err)
+	} else if string(b) != "<b>" {
+		t.Fatalf("unexpected dead letter: %s", b)
+	}
+
+	// Without the logs, IDs restart, but existing dead letters are kept.
+	for _, name := range []string{"spool.log", "done.log", "seq"} {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, err = OpenSpool(dir, h, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.Enqueue([]byte("<c>")); err != nil {
+		t.Fatal(err)
+	}
+	waitDeadLetters(t, dir, 6)
+
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "dead", "1.xml")); err != nil {
+		t.Fatal(err)
+	} else if string(b) != "<a>" {
+		t.Fatalf("unexpected dead letter: %s", b)
+	} else if b, err := ioutil.ReadFile(filepath.Join(dir, "dead", "1-1.xml")); err != nil {
+		t.Fatal(err)
+	} else if string(b) != "<c>" {
+		t.Fatalf("unexpected dead letter: %s", b)
+	} else if _, err := os.Stat(filepath.Join(dir, "dead", "1-1.err")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSpool_Recover(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	body, err := ioutil.ReadFile("testdata/subscriptions/new_subscription_notification.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Webhook 1 was processed, 2 was not, and 3 was only partly written
+	// before the process stopped.
+	var log bytes.Buffer
+	log.WriteString("1 5\nfirst\n")
+	log.WriteString("2 " + strconv.Itoa(len(body)) + "\n")
+	log.Write(body)
+	log.WriteString("\n3 100\n<new_subscr")
+	if err := ioutil.WriteFile(filepath.Join(dir, "spool.log"), log.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	} else if err := ioutil.WriteFile(filepath.Join(dir, "done.log"), []byte("1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	handled := make(chan interface{}, 2)
+	s, err := OpenSpool(dir, func(n interface{}) error {
+		handled <- n
+		return nil
+	}, SpoolOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	select {
+	case n := <-handled:
+		if _, ok := n.(*SubscriptionNotificationNew); !ok {
+			t.Fatalf("unexpected notification: %#v", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected pending notification to be handled")
+	}
+
+	// New webhooks continue after the highest ID seen.
+	if err := s.Enqueue(body); err != nil {
+		t.Fatal(err)
+	}
+	<-handled
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "done.log")); err != nil {
+		t.Fatal(err)
+	} else if string(b) != "2\n3\n" && string(b) != "3\n2\n" {
+		t.Fatalf("unexpected done log: %q", b)
+	}
+}
+
+// failingFile writes at most n bytes to the log before failing.
+type failingFile struct {
+	spoolFile
+	n int
+}
+
+func (f *failingFile) Write(p []byte) (int, error) {
+	if len(p) <= f.n {
+		f.n -= len(p)
+		return f.spoolFile.Write(p)
+	}
+	n, _ := f.spoolFile.Write(p[:f.n])
+	f.n = 0
+	return n, errors.New("disk full")
+}
+
+func TestSpool_EnqueueFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	body, err := ioutil.ReadFile("testdata/subscriptions/new_subscription_notification.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handled := make(chan interface{}, 1)
+	s, err := OpenSpool(dir, func(n interface{}) error {
+		handled <- n
+		return nil
+	}, SpoolOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// The webhook fails partway through being written.
+	log := s.log
+	s.log = &failingFile{spoolFile: log, n: 10}
+	if err := s.Enqueue(body); err == nil || err.Error() != "disk full" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.log = log
+
+	if err := s.Enqueue(body); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected notification to be handled")
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "spool.log")); err != nil {
+		t.Fatal(err)
+	} else if string(b) != "1 "+strconv.Itoa(len(body))+"\n"+string(body)+"\n" {
+		t.Fatalf("unexpected spool: %q", b)
+	}
+
+	s, err = OpenSpool(dir, func(n interface{}) error { return nil }, SpoolOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.Close()
+}
@@ -1,17 +1,23 @@
 package webhooks
 
 import (
+	"encoding/xml"
+
 	"github.com/kmikiy/recurly"
 )
 
 const (
 	// Subscription notifications.
-	SubscriptionNotificationNewXMLName         = "new_subscription_notification"
-	SubscriptionNotificationUpdatedXMLName     = "updated_subscription_notification"
-	SubscriptionNotificationCanceledXMLName    = "canceled_subscription_notification"
-	SubscriptionNotificationExpiredXMLName     = "expired_subscription_notification"
-	SubscriptionNotificationRenewedXMLName     = "renewed_subscription_notification"
-	SubscriptionNotificationReactivatedXMLName = "reactivated_account_notification"
+	SubscriptionNotificationNewXMLName           = "new_subscription_notification"
+	SubscriptionNotificationUpdatedXMLName       = "updated_subscription_notification"
+	SubscriptionNotificationCanceledXMLName      = "canceled_subscription_notification"
+	SubscriptionNotificationExpiredXMLName       = "expired_subscription_notification"
+	SubscriptionNotificationRenewedXMLName       = "renewed_subscription_notification"
+	SubscriptionNotificationReactivatedXMLName   = "reactivated_account_notification"
+	SubscriptionNotificationPausedXMLName        = "subscription_paused_notification"
+	SubscriptionNotificationResumedXMLName       = "subscription_resumed_notification"
+	SubscriptionNotificationPauseCanceledXMLName = "subscription_pause_canceled_notification"
+	SubscriptionNotificationPrerenewalXMLName    = "prerenewal_notification"
 )
 
 // Subscription types.
@@ -57,4 +63,163 @@ type (
 		Account      Account              `xml:"account"`
 		Subscription recurly.Subscription `xml:"subscription"`
 	}
+
+	// SubscriptionNotificationPaused is sent when a subscription enters a paused state.
+	// https://dev.recurly.com/page/webhooks#section-subscription-paused
+	SubscriptionNotificationPaused struct {
+		Account      Account              `xml:"account"`
+		Subscription recurly.Subscription `xml:"subscription"`
+	}
+
+	// SubscriptionNotificationResumed is sent when a paused subscription is resumed.
+	// https://dev.recurly.com/page/webhooks#section-subscription-resumed
+	SubscriptionNotificationResumed struct {
+		Account      Account              `xml:"account"`
+		Subscription recurly.Subscription `xml:"subscription"`
+	}
+
+	// SubscriptionNotificationPauseCanceled is sent when a scheduled pause is canceled.
+	// https://dev.recurly.com/page/webhooks#section-subscription-pause-canceled
+	SubscriptionNotificationPauseCanceled struct {
+		Account      Account              `xml:"account"`
+		Subscription recurly.Subscription `xml:"subscription"`
+	}
+
+	// SubscriptionNotificationPrerenewal is sent ahead of a subscription renewal,
+	// according to the prerenewal setting of the plan.
+	// https://dev.recurly.com/page/webhooks#section-prerenewal
+	SubscriptionNotificationPrerenewal struct {
+		Account      Account              `xml:"account"`
+		Subscription recurly.Subscription `xml:"subscription"`
+	}
 )
+
+// NewSubscriptionNotificationNew returns a new SubscriptionNotificationNew.
+func NewSubscriptionNotificationNew(account Account, subscription recurly.Subscription) *SubscriptionNotificationNew {
+	return &SubscriptionNotificationNew{
+		Account:      account,
+		Subscription: subscription,
+	}
+}
+
+// MarshalXML marshals the notification as <new_subscription_notification>.
+func (n SubscriptionNotificationNew) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, SubscriptionNotificationNewXMLName, n)
+}
+
+// NewSubscriptionNotificationUpdated returns a new SubscriptionNotificationUpdated.
+func NewSubscriptionNotificationUpdated(account Account, subscription recurly.Subscription) *SubscriptionNotificationUpdated {
+	return &SubscriptionNotificationUpdated{
+		Account:      account,
+		Subscription: subscription,
+	}
+}
+
+// MarshalXML marshals the notification as <updated_subscription_notification>.
+func (n SubscriptionNotificationUpdated) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, SubscriptionNotificationUpdatedXMLName, n)
+}
+
+// NewSubscriptionNotificationCanceled returns a new SubscriptionNotificationCanceled.
+func NewSubscriptionNotificationCanceled(account Account, subscription recurly.Subscription) *SubscriptionNotificationCanceled {
+	return &SubscriptionNotificationCanceled{
+		Account:      account,
+		Subscription: subscription,
+	}
+}
+
+// MarshalXML marshals the notification as <canceled_subscription_notification>.
+func (n SubscriptionNotificationCanceled) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, SubscriptionNotificationCanceledXMLName, n)
+}
+
+// NewSubscriptionNotificationExpired returns a new SubscriptionNotificationExpired.
+func NewSubscriptionNotificationExpired(account Account, subscription recurly.Subscription) *SubscriptionNotificationExpired {
+	return &SubscriptionNotificationExpired{
+		Account:      account,
+		Subscription: subscription,
+	}
+}
+
+// MarshalXML marshals the notification as <expired_subscription_notification>.
+func (n SubscriptionNotificationExpired) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, SubscriptionNotificationExpiredXMLName, n)
+}
+
+// NewSubscriptionNotificationRenewed returns a new SubscriptionNotificationRenewed.
+func NewSubscriptionNotificationRenewed(account Account, subscription recurly.Subscription) *SubscriptionNotificationRenewed {
+	return &SubscriptionNotificationRenewed{
+		Account:      account,
+		Subscription: subscription,
+	}
+}
+
+// MarshalXML marshals the notification as <renewed_subscription_notification>.
+func (n SubscriptionNotificationRenewed) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, SubscriptionNotificationRenewedXMLName, n)
+}
+
+// NewSubscriptionNotificationReactivated returns a new SubscriptionNotificationReactivated.
+func NewSubscriptionNotificationReactivated(account Account, subscription recurly.Subscription) *SubscriptionNotificationReactivated {
+	return &SubscriptionNotificationReactivated{
+		Account:      account,
+		Subscription: subscription,
+	}
+}
+
+// MarshalXML marshals the notification as <reactivated_account_notification>.
+func (n SubscriptionNotificationReactivated) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, SubscriptionNotificationReactivatedXMLName, n)
+}
+
+// NewSubscriptionNotificationPaused returns a new SubscriptionNotificationPaused.
+func NewSubscriptionNotificationPaused(account Account, subscription recurly.Subscription) *SubscriptionNotificationPaused {
+	return &SubscriptionNotificationPaused{
+		Account:      account,
+		Subscription: subscription,
+	}
+}
+
+// MarshalXML marshals the notification as <subscription_paused_notification>.
+func (n SubscriptionNotificationPaused) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, SubscriptionNotificationPausedXMLName, n)
+}
+
+// NewSubscriptionNotificationResumed returns a new SubscriptionNotificationResumed.
+func NewSubscriptionNotificationResumed(account Account, subscription recurly.Subscription) *SubscriptionNotificationResumed {
+	return &SubscriptionNotificationResumed{
+		Account:      account,
+		Subscription: subscription,
+	}
+}
+
+// MarshalXML marshals the notification as <subscription_resumed_notification>.
+func (n SubscriptionNotificationResumed) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, SubscriptionNotificationResumedXMLName, n)
+}
+
+// NewSubscriptionNotificationPauseCanceled returns a new SubscriptionNotificationPauseCanceled.
+func NewSubscriptionNotificationPauseCanceled(account Account, subscription recurly.Subscription) *SubscriptionNotificationPauseCanceled {
+	return &SubscriptionNotificationPauseCanceled{
+		Account:      account,
+		Subscription: subscription,
+	}
+}
+
+// MarshalXML marshals the notification as <subscription_pause_canceled_notification>.
+func (n SubscriptionNotificationPauseCanceled) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, SubscriptionNotificationPauseCanceledXMLName, n)
+}
+
+// NewSubscriptionNotificationPrerenewal returns a new SubscriptionNotificationPrerenewal.
+func NewSubscriptionNotificationPrerenewal(account Account, subscription recurly.Subscription) *SubscriptionNotificationPrerenewal {
+	return &SubscriptionNotificationPrerenewal{
+		Account:      account,
+		Subscription: subscription,
+	}
+}
+
+// MarshalXML marshals the notification as <prerenewal_notification>.
+func (n SubscriptionNotificationPrerenewal) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, SubscriptionNotificationPrerenewalXMLName, n)
+}
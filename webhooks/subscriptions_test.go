@@ -259,3 +259,164 @@ func TestParse_SubscriptionNotificationReactivated(t *testing.T) {
 		t.Fatalf("unexpected notification: %#v", n)
 	}
 }
+
+func TestParse_SubscriptionNotificationPaused(t *testing.T) {
+	activatedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-03-01T15:42:31Z")
+	startedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-04-01T15:42:31Z")
+	endsTs, _ := time.Parse(recurly.DateTimeFormat, "2018-05-01T15:42:31Z")
+	pausedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-04-01T15:42:31Z")
+	resumeTs, _ := time.Parse(recurly.DateTimeFormat, "2018-06-01T15:42:31Z")
+
+	xmlFile := MustOpenFile("testdata/subscriptions/subscription_paused_notification.xml")
+	result, err := Parse(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*SubscriptionNotificationPaused); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if !reflect.DeepEqual(n, &SubscriptionNotificationPaused{
+		Account: Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		Subscription: recurly.Subscription{
+			XMLName: xml.Name{Local: "subscription"},
+			Plan: recurly.NestedPlan{
+				Code: "gold",
+				Name: "Gold",
+			},
+			UUID:                   "4110792b3b01967d854f674b7282f542",
+			State:                  "paused",
+			Quantity:               1,
+			TotalAmountInCents:     1000,
+			ActivatedAt:            recurly.NewTime(activatedTs),
+			CurrentPeriodStartedAt: recurly.NewTime(startedTs),
+			CurrentPeriodEndsAt:    recurly.NewTime(endsTs),
+			PausedAt:               recurly.NewTime(pausedTs),
+			ResumeAt:               recurly.NewTime(resumeTs),
+			RemainingPauseCycles:   2,
+			CollectionMethod:       recurly.CollectionMethodAutomatic,
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestParse_SubscriptionNotificationResumed(t *testing.T) {
+	activatedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-03-01T15:42:31Z")
+	startedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-04-01T15:42:31Z")
+	endsTs, _ := time.Parse(recurly.DateTimeFormat, "2018-05-01T15:42:31Z")
+
+	xmlFile := MustOpenFile("testdata/subscriptions/subscription_resumed_notification.xml")
+	result, err := Parse(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*SubscriptionNotificationResumed); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if !reflect.DeepEqual(n, &SubscriptionNotificationResumed{
+		Account: Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		Subscription: recurly.Subscription{
+			XMLName: xml.Name{Local: "subscription"},
+			Plan: recurly.NestedPlan{
+				Code: "gold",
+				Name: "Gold",
+			},
+			UUID:                   "4110792b3b01967d854f674b7282f542",
+			State:                  "active",
+			Quantity:               1,
+			TotalAmountInCents:     1000,
+			ActivatedAt:            recurly.NewTime(activatedTs),
+			CurrentPeriodStartedAt: recurly.NewTime(startedTs),
+			CurrentPeriodEndsAt:    recurly.NewTime(endsTs),
+			CollectionMethod:       recurly.CollectionMethodAutomatic,
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestParse_SubscriptionNotificationPauseCanceled(t *testing.T) {
+	activatedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-03-01T15:42:31Z")
+	startedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-04-01T15:42:31Z")
+	endsTs, _ := time.Parse(recurly.DateTimeFormat, "2018-05-01T15:42:31Z")
+
+	xmlFile := MustOpenFile("testdata/subscriptions/subscription_pause_canceled_notification.xml")
+	result, err := Parse(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*SubscriptionNotificationPauseCanceled); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if !reflect.DeepEqual(n, &SubscriptionNotificationPauseCanceled{
+		Account: Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		Subscription: recurly.Subscription{
+			XMLName: xml.Name{Local: "subscription"},
+			Plan: recurly.NestedPlan{
+				Code: "gold",
+				Name: "Gold",
+			},
+			UUID:                   "4110792b3b01967d854f674b7282f542",
+			State:                  "active",
+			Quantity:               1,
+			TotalAmountInCents:     1000,
+			ActivatedAt:            recurly.NewTime(activatedTs),
+			CurrentPeriodStartedAt: recurly.NewTime(startedTs),
+			CurrentPeriodEndsAt:    recurly.NewTime(endsTs),
+			CollectionMethod:       recurly.CollectionMethodAutomatic,
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
+
+func TestParse_SubscriptionNotificationPrerenewal(t *testing.T) {
+	activatedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-03-01T15:42:31Z")
+	startedTs, _ := time.Parse(recurly.DateTimeFormat, "2018-04-01T15:42:31Z")
+	endsTs, _ := time.Parse(recurly.DateTimeFormat, "2018-05-01T15:42:31Z")
+
+	xmlFile := MustOpenFile("testdata/subscriptions/prerenewal_notification.xml")
+	result, err := Parse(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	} else if n, ok := result.(*SubscriptionNotificationPrerenewal); !ok {
+		t.Fatalf("unexpected type: %T", result)
+	} else if !reflect.DeepEqual(n, &SubscriptionNotificationPrerenewal{
+		Account: Account{
+			XMLName:   xml.Name{Local: "account"},
+			Code:      "1",
+			Email:     "verena@example.com",
+			FirstName: "Verena",
+			LastName:  "Example",
+		},
+		Subscription: recurly.Subscription{
+			XMLName: xml.Name{Local: "subscription"},
+			Plan: recurly.NestedPlan{
+				Code: "gold",
+				Name: "Gold",
+			},
+			UUID:                   "4110792b3b01967d854f674b7282f542",
+			State:                  "active",
+			Quantity:               1,
+			TotalAmountInCents:     1000,
+			ActivatedAt:            recurly.NewTime(activatedTs),
+			CurrentPeriodStartedAt: recurly.NewTime(startedTs),
+			CurrentPeriodEndsAt:    recurly.NewTime(endsTs),
+			CollectionMethod:       recurly.CollectionMethodAutomatic,
+		},
+	}) {
+		t.Fatalf("unexpected notification: %#v", n)
+	}
+}
@@ -1,5 +1,7 @@
 package webhooks
 
+import "encoding/xml"
+
 const (
 	// Usage notifications.
 	UsageNotificationNewUsageXMLName = "new_usage_notification"
@@ -14,3 +16,16 @@ type (
 		Usage   Usage   `xml:"usage"`
 	}
 )
+
+// NewUsageNotificationNewUsage returns a new UsageNotificationNewUsage.
+func NewUsageNotificationNewUsage(account Account, usage Usage) *UsageNotificationNewUsage {
+	return &UsageNotificationNewUsage{
+		Account: account,
+		Usage:   usage,
+	}
+}
+
+// MarshalXML marshals the notification as <new_usage_notification>.
+func (n UsageNotificationNewUsage) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeNotification(e, UsageNotificationNewUsageXMLName, n)
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
-	"io/ioutil"
 
 	"github.com/kmikiy/recurly"
 )
@@ -61,6 +60,13 @@ type Transaction struct {
 	Test              recurly.NullBool `xml:"test,omitempty"`
 	Voidable          recurly.NullBool `xml:"voidable,omitempty"`
 	Refundable        recurly.NullBool `xml:"refundable,omitempty"`
+	FraudInfo         *FraudInfo       `xml:"fraud_info,omitempty"`
+}
+
+// FraudInfo represents the fraud_info object sent with transactions in webhooks.
+type FraudInfo struct {
+	Score    recurly.NullInt `xml:"score,omitempty"`
+	Decision string          `xml:"decision,omitempty"`
 }
 
 // Invoice represents the invoice object sent in webhooks.
@@ -81,6 +87,49 @@ type Invoice struct {
 	CollectionMethod    string           `xml:"collection_method,omitempty"`
 }
 
+// ChargeInvoice represents the charge invoice object sent in webhooks.
+type ChargeInvoice struct {
+	XMLName             xml.Name         `xml:"invoice,omitempty"`
+	UUID                string           `xml:"uuid,omitempty"`
+	State               string           `xml:"state,omitempty"`
+	Origin              string           `xml:"origin,omitempty"`
+	SubscriptionUUIDs   []string         `xml:"subscription_ids>subscription_id,omitempty"`
+	InvoiceNumberPrefix string           `xml:"invoice_number_prefix,omitempty"`
+	InvoiceNumber       int              `xml:"invoice_number,omitempty"`
+	PONumber            string           `xml:"po_number,omitempty"`
+	VATNumber           string           `xml:"vat_number,omitempty"`
+	BalanceInCents      int              `xml:"balance_in_cents,omitempty"`
+	TotalInCents        int              `xml:"total_in_cents,omitempty"`
+	Currency            string           `xml:"currency,omitempty"`
+	CreatedAt           recurly.NullTime `xml:"created_at,omitempty"`
+	UpdatedAt           recurly.NullTime `xml:"updated_at,omitempty"`
+	DueOn               recurly.NullTime `xml:"due_on,omitempty"`
+	ClosedAt            recurly.NullTime `xml:"closed_at,omitempty"`
+	NetTerms            recurly.NullInt  `xml:"net_terms,omitempty"`
+	CollectionMethod    string           `xml:"collection_method,omitempty"`
+	CustomerNotes       string           `xml:"customer_notes,omitempty"`
+	TermsAndConditions  string           `xml:"terms_and_conditions,omitempty"`
+}
+
+// CreditInvoice represents the credit invoice object sent in webhooks.
+type CreditInvoice struct {
+	XMLName             xml.Name         `xml:"invoice,omitempty"`
+	UUID                string           `xml:"uuid,omitempty"`
+	State               string           `xml:"state,omitempty"`
+	Origin              string           `xml:"origin,omitempty"`
+	SubscriptionUUIDs   []string         `xml:"subscription_ids>subscription_id,omitempty"`
+	InvoiceNumberPrefix string           `xml:"invoice_number_prefix,omitempty"`
+	InvoiceNumber       int              `xml:"invoice_number,omitempty"`
+	VATNumber           string           `xml:"vat_number,omitempty"`
+	BalanceInCents      int              `xml:"balance_in_cents,omitempty"`
+	TotalInCents        int              `xml:"total_in_cents,omitempty"`
+	Currency            string           `xml:"currency,omitempty"`
+	CreatedAt           recurly.NullTime `xml:"created_at,omitempty"`
+	UpdatedAt           recurly.NullTime `xml:"updated_at,omitempty"`
+	ClosedAt            recurly.NullTime `xml:"closed_at,omitempty"`
+	CustomerNotes       string           `xml:"customer_notes,omitempty"`
+}
+
 // Usage represents the usage object sent in webhooks.
 type Usage struct {
 	XMLName            xml.Name          `xml:"usage,omitempty"`
@@ -117,6 +166,7 @@ type GiftCard struct {
 	DeliveredAt          recurly.NullTime `xml:"delivered_at,omitempty"`
 	RedeemedAt           recurly.NullTime `xml:"redeemed_at,omitempty"`
 	CanceledAt           recurly.NullTime `xml:"canceled_at,omitempty"`
+	BalanceInCents       recurly.NullInt  `xml:"balance_in_cents,omitempty"`
 }
 
 type GiftCardDelivery struct {
@@ -162,102 +212,73 @@ func (e ErrUnknownNotification) Name() string {
 	return e.name
 }
 
-// Parse parses an incoming webhook and returns the notification.
-func Parse(r io.Reader) (interface{}, error) {
-	if closer, ok := r.(io.Closer); ok {
-		defer closer.Close()
-	}
-
-	notification, err := ioutil.ReadAll(r)
-	if err != nil {
-		return nil, err
-	}
-
-	var n notificationName
-	if err := xml.Unmarshal(notification, &n); err != nil {
-		return nil, err
-	}
-
-	var dst interface{}
-	switch n.XMLName.Local {
+// builtinNotifications maps the XML root name of each notification this
+// package supports to a factory for its type.
+var builtinNotifications = map[string]Factory{
 	// Account notifications
-	case AccountNotificationNewXMLName:
-		dst = &AccountNotificationNew{}
-	case AccountNotificationUpdatedXMLName:
-		dst = &AccountNotificationUpdated{}
-	case AccountNotificationCanceledXMLName:
-		dst = &AccountNotificationCanceled{}
-	case AccountNotificationBillingInfoUpdatedXMLName:
-		dst = &AccountNotificationBillingInfoUpdated{}
-	case AccountNotificationBillingInfoUpdateFailedXMLName:
-		dst = &AccountNotificationBillingInfoUpdateFailed{}
-	case AccountNotificationNewShippingAddressXMLName:
-		dst = &AccountNotificationNewShippingAddress{}
-	case AccountNotificationUpdatedShippingAddressXMLName:
-		dst = &AccountNotificationUpdatedShippingAddress{}
-	case AccountNotificationDeletedShippingAddressXMLName:
-		dst = &AccountNotificationDeletedShippingAddress{}
+	AccountNotificationNewXMLName:                     func() interface{} { return &AccountNotificationNew{} },
+	AccountNotificationUpdatedXMLName:                 func() interface{} { return &AccountNotificationUpdated{} },
+	AccountNotificationCanceledXMLName:                func() interface{} { return &AccountNotificationCanceled{} },
+	AccountNotificationBillingInfoUpdatedXMLName:      func() interface{} { return &AccountNotificationBillingInfoUpdated{} },
+	AccountNotificationBillingInfoUpdateFailedXMLName: func() interface{} { return &AccountNotificationBillingInfoUpdateFailed{} },
+	AccountNotificationNewShippingAddressXMLName:      func() interface{} { return &AccountNotificationNewShippingAddress{} },
+	AccountNotificationUpdatedShippingAddressXMLName:  func() interface{} { return &AccountNotificationUpdatedShippingAddress{} },
+	AccountNotificationDeletedShippingAddressXMLName:  func() interface{} { return &AccountNotificationDeletedShippingAddress{} },
 
 	// Subscription notifications
-	case SubscriptionNotificationNewXMLName:
-		dst = &SubscriptionNotificationNew{}
-	case SubscriptionNotificationUpdatedXMLName:
-		dst = &SubscriptionNotificationUpdated{}
-	case SubscriptionNotificationCanceledXMLName:
-		dst = &SubscriptionNotificationCanceled{}
-	case SubscriptionNotificationExpiredXMLName:
-		dst = &SubscriptionNotificationExpired{}
-	case SubscriptionNotificationRenewedXMLName:
-		dst = &SubscriptionNotificationRenewed{}
-	case SubscriptionNotificationReactivatedXMLName:
-		dst = &SubscriptionNotificationReactivated{}
+	SubscriptionNotificationNewXMLName:           func() interface{} { return &SubscriptionNotificationNew{} },
+	SubscriptionNotificationUpdatedXMLName:       func() interface{} { return &SubscriptionNotificationUpdated{} },
+	SubscriptionNotificationCanceledXMLName:      func() interface{} { return &SubscriptionNotificationCanceled{} },
+	SubscriptionNotificationExpiredXMLName:       func() interface{} { return &SubscriptionNotificationExpired{} },
+	SubscriptionNotificationRenewedXMLName:       func() interface{} { return &SubscriptionNotificationRenewed{} },
+	SubscriptionNotificationReactivatedXMLName:   func() interface{} { return &SubscriptionNotificationReactivated{} },
+	SubscriptionNotificationPausedXMLName:        func() interface{} { return &SubscriptionNotificationPaused{} },
+	SubscriptionNotificationResumedXMLName:       func() interface{} { return &SubscriptionNotificationResumed{} },
+	SubscriptionNotificationPauseCanceledXMLName: func() interface{} { return &SubscriptionNotificationPauseCanceled{} },
+	SubscriptionNotificationPrerenewalXMLName:    func() interface{} { return &SubscriptionNotificationPrerenewal{} },
 
 	// Usage notifications
-	case UsageNotificationNewUsageXMLName:
-		dst = &UsageNotificationNewUsage{}
+	UsageNotificationNewUsageXMLName: func() interface{} { return &UsageNotificationNewUsage{} },
 
 	// Gift Card notifications
-	case GiftCardNotificationPurchasedXMLName:
-		dst = &GiftCardNotificationPurchased{}
-	case GiftCardNotificationCanceledXMLName:
-		dst = &GiftCardNotificationCanceled{}
-	case GiftCardNotificationUpdatedXMLName:
-		dst = &GiftCardNotificationUpdated{}
-	case GiftCardNotificationRegeneratedXMLName:
-		dst = &GiftCardNotificationRegenerated{}
-	case GiftCardNotificationRedeemedXMLName:
-		dst = &GiftCardNotificationRedeemed{}
-	case GiftCardNotificationUpdatedBalanceXMLName:
-		dst = &GiftCardNotificationUpdatedBalance{}
+	GiftCardNotificationPurchasedXMLName:      func() interface{} { return &GiftCardNotificationPurchased{} },
+	GiftCardNotificationCanceledXMLName:       func() interface{} { return &GiftCardNotificationCanceled{} },
+	GiftCardNotificationUpdatedXMLName:        func() interface{} { return &GiftCardNotificationUpdated{} },
+	GiftCardNotificationRegeneratedXMLName:    func() interface{} { return &GiftCardNotificationRegenerated{} },
+	GiftCardNotificationRedeemedXMLName:       func() interface{} { return &GiftCardNotificationRedeemed{} },
+	GiftCardNotificationUpdatedBalanceXMLName: func() interface{} { return &GiftCardNotificationUpdatedBalance{} },
+	GiftCardNotificationLowBalanceXMLName:     func() interface{} { return &GiftCardNotificationLowBalance{} },
 
 	// Invoice notifications
-	case InvoiceNotificationNewXMLName:
-		dst = &InvoiceNotificationNew{}
-	case InvoiceNotificationPastDueXMLName:
-		dst = &InvoiceNotificationPastDue{}
-	case InvoiceNotificationClosedXMLName:
-		dst = &InvoiceNotificationClosed{}
+	InvoiceNotificationNewXMLName:     func() interface{} { return &InvoiceNotificationNew{} },
+	InvoiceNotificationPastDueXMLName: func() interface{} { return &InvoiceNotificationPastDue{} },
+	InvoiceNotificationClosedXMLName:  func() interface{} { return &InvoiceNotificationClosed{} },
+
+	// Charge invoice notifications
+	ChargeInvoiceNotificationNewXMLName:        func() interface{} { return &ChargeInvoiceNotificationNew{} },
+	ChargeInvoiceNotificationProcessingXMLName: func() interface{} { return &ChargeInvoiceNotificationProcessing{} },
+	ChargeInvoiceNotificationClosedXMLName:     func() interface{} { return &ChargeInvoiceNotificationClosed{} },
+	ChargeInvoiceNotificationPastDueXMLName:    func() interface{} { return &ChargeInvoiceNotificationPastDue{} },
+
+	// Credit invoice notifications
+	CreditInvoiceNotificationNewXMLName:        func() interface{} { return &CreditInvoiceNotificationNew{} },
+	CreditInvoiceNotificationProcessingXMLName: func() interface{} { return &CreditInvoiceNotificationProcessing{} },
+	CreditInvoiceNotificationClosedXMLName:     func() interface{} { return &CreditInvoiceNotificationClosed{} },
 
 	// Payment notifications
-	case PaymentNotificationSuccessfulXMLName:
-		dst = &PaymentNotificationSuccessful{}
-	case PaymentNotificationFailedXMLName:
-		dst = &PaymentNotificationFailed{}
-	case PaymentNotificationVoidXMLName:
-		dst = &PaymentNotificationVoid{}
-	case PaymentNotificationSuccessfulRefundXMLName:
-		dst = &PaymentNotificationSuccessfulRefund{}
+	PaymentNotificationSuccessfulXMLName:       func() interface{} { return &PaymentNotificationSuccessful{} },
+	PaymentNotificationFailedXMLName:           func() interface{} { return &PaymentNotificationFailed{} },
+	PaymentNotificationVoidXMLName:             func() interface{} { return &PaymentNotificationVoid{} },
+	PaymentNotificationSuccessfulRefundXMLName: func() interface{} { return &PaymentNotificationSuccessfulRefund{} },
+	PaymentNotificationScheduledXMLName:        func() interface{} { return &PaymentNotificationScheduled{} },
+	PaymentNotificationFraudInfoUpdatedXMLName: func() interface{} { return &PaymentNotificationFraudInfoUpdated{} },
 
 	// Dunning event notifications
-	case DunningEventNotificationNewXMLName:
-		dst = &DunningEventNotificationNew{}
-	default:
-		return nil, ErrUnknownNotification{name: n.XMLName.Local}
-	}
-
-	if err := xml.Unmarshal(notification, dst); err != nil {
-		return nil, err
-	}
+	DunningEventNotificationNewXMLName: func() interface{} { return &DunningEventNotificationNew{} },
+}
 
-	return dst, nil
+// Parse parses an incoming webhook using DefaultRegistry and returns the
+// notification.
+func Parse(r io.Reader) (interface{}, error) {
+	return DefaultRegistry.Parse(r)
 }